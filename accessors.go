@@ -0,0 +1,773 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// PrefixInfo is a summary of a prefix that is advertised in one of the IP
+// reachability TLVs of an LSP, which abstracts the differences between the
+// TLV types such that consumers can handle all prefixes in the same manner.
+type PrefixInfo struct {
+	// Prefix is the advertised prefix, in CIDR format.
+	Prefix string
+	// TLV is the type of the TLV that the prefix was advertised in.
+	TLV oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE
+	// Metric is the metric of the prefix.
+	Metric uint32
+	// UpDown indicates whether the prefix has been leaked from a higher
+	// to a lower level.
+	UpDown bool
+	// External indicates whether the prefix is external to IS-IS, i.e.,
+	// has been redistributed from another protocol.
+	External bool
+	// DoNotUse indicates that the prefix is advertised with a metric larger
+	// than MAX_PATH_METRIC, such that it must not be used for SPF.
+	DoNotUse bool
+}
+
+// hasExternalFlag returns true if the external (X) flag is included in the
+// prefix attribute flags supplied.
+func hasExternalFlag(flags []oc.E_OpenconfigIsis_Flags_Flags) bool {
+	for _, f := range flags {
+		if f == oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExternalIPv4Prefix returns true if the prefix, advertised in the extended
+// IPv4 reachability TLV (type 135), is external. Since TLV 135 does not carry
+// an internal/external indication, the X-flag of the prefix attribute flags
+// sub-TLV (RFC7794) is used.
+func IsExternalIPv4Prefix(p *oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix) bool {
+	if p == nil {
+		return false
+	}
+	st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS]
+	return st != nil && st.Flags != nil && hasExternalFlag(st.Flags.Flags)
+}
+
+// maxPathMetric is MAX_PATH_METRIC, as defined in RFC5305 Section 4. Prefixes
+// in the extended IPv4 reachability TLV (type 135), or the MT IPv4
+// reachability TLV (type 235), that are advertised with a metric larger than
+// it must not be used for SPF.
+const maxPathMetric uint32 = 0xFE000000
+
+// IsUnusableIPv4Prefix returns true if the prefix, advertised in the extended
+// IPv4 reachability TLV (type 135), carries a metric larger than
+// MAX_PATH_METRIC (0xFE000000), such that it must not be considered by SPF
+// (RFC5305).
+func IsUnusableIPv4Prefix(p *oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix) bool {
+	return p != nil && uint32Value(p.Metric) > maxPathMetric
+}
+
+// IsExternalIPv6Prefix returns true if the prefix, advertised in the IPv6
+// reachability TLV (type 236), is external. The prefix is external if either
+// the external origin bit of the TLV (RFC5308) or the X-flag of the prefix
+// attribute flags sub-TLV (RFC7794) is set.
+func IsExternalIPv6Prefix(p *oc.Lsp_Tlv_Ipv6Reachability_Prefix) bool {
+	if p == nil {
+		return false
+	}
+	if boolValue(p.XBit) {
+		return true
+	}
+	st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS]
+	return st != nil && st.Flags != nil && hasExternalFlag(st.Flags.Flags)
+}
+
+// Prefixes returns the set of prefixes that are advertised in the IP
+// reachability TLVs of the LSP, sorted by TLV type and prefix.
+func Prefixes(lsp *oc.Lsp) []PrefixInfo {
+	var pfxs []PrefixInfo
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability(); r != nil {
+		for pfx, p := range r.Prefix {
+			pfxs = append(pfxs, PrefixInfo{
+				Prefix:   pfx,
+				TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+				Metric:   uint32Value(p.Metric),
+				UpDown:   boolValue(p.UpDown),
+				External: IsExternalIPv4Prefix(p),
+				DoNotUse: IsUnusableIPv4Prefix(p),
+			})
+		}
+	}
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetIpv6Reachability(); r != nil {
+		for pfx, p := range r.Prefix {
+			pfxs = append(pfxs, PrefixInfo{
+				Prefix:   pfx,
+				TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+				Metric:   uint32Value(p.Metric),
+				UpDown:   boolValue(p.UpDown),
+				External: IsExternalIPv6Prefix(p),
+			})
+		}
+	}
+
+	sort.Slice(pfxs, func(i, j int) bool {
+		if pfxs[i].TLV != pfxs[j].TLV {
+			return pfxs[i].TLV < pfxs[j].TLV
+		}
+		return pfxs[i].Prefix < pfxs[j].Prefix
+	})
+	return pfxs
+}
+
+// hasTag returns true if tags contains tag.
+func hasTag(tags []uint32, tag uint32) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TaggedPrefixes returns the prefixes that are advertised in the extended IPv4
+// reachability (135) and IPv6 reachability (236) TLVs of the LSP with the
+// 32-bit administrative tag supplied. The IPv4 prefixes are returned, sorted,
+// followed by the sorted IPv6 prefixes.
+func TaggedPrefixes(lsp *oc.Lsp, tag uint32) []string {
+	var v4, v6 []string
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability(); r != nil {
+		for pfx, p := range r.Prefix {
+			if t := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetTag(); t != nil && hasTag(t.Tag32, tag) {
+				v4 = append(v4, pfx)
+			}
+		}
+	}
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetIpv6Reachability(); r != nil {
+		for pfx, p := range r.Prefix {
+			if t := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetTag(); t != nil && hasTag(t.Tag32, tag) {
+				v6 = append(v6, pfx)
+			}
+		}
+	}
+
+	sort.Strings(v4)
+	sort.Strings(v6)
+	return append(v4, v6...)
+}
+
+// prefixKey uniquely identifies a prefix within an LSP.
+type prefixKey struct {
+	tlv    oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE
+	prefix string
+}
+
+// PrefixDelta compares the prefixes that are advertised in two versions of an
+// LSP, prev and cur, and returns the prefixes that were added, removed and
+// changed. Prefixes are identified by their prefix string and the TLV that
+// they are advertised in. A prefix is changed if its metric or flags differ,
+// in which case the version in cur is returned. If prev is nil, all prefixes
+// in cur are added. Each slice is sorted in the same order as Prefixes.
+func PrefixDelta(prev, cur *oc.Lsp) (added, removed, changed []PrefixInfo) {
+	prevList := Prefixes(prev)
+	prevPfxs := map[prefixKey]PrefixInfo{}
+	for _, p := range prevList {
+		prevPfxs[prefixKey{p.TLV, p.Prefix}] = p
+	}
+
+	for _, p := range Prefixes(cur) {
+		k := prefixKey{p.TLV, p.Prefix}
+		old, ok := prevPfxs[k]
+		switch {
+		case !ok:
+			added = append(added, p)
+		case old != p:
+			changed = append(changed, p)
+		}
+		delete(prevPfxs, k)
+	}
+
+	// Iterate through prev in order, such that the removed prefixes are
+	// sorted.
+	for _, p := range prevList {
+		if _, ok := prevPfxs[prefixKey{p.TLV, p.Prefix}]; ok {
+			removed = append(removed, p)
+		}
+	}
+	return added, removed, changed
+}
+
+// uint32Value returns the value of the uint32 pointer v, or 0 if it is nil.
+func uint32Value(v *uint32) uint32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// uint8Value returns the value of the uint8 pointer v, or 0 if it is nil.
+func uint8Value(v *uint8) uint8 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// stringValue returns the value of the string pointer v, or "" if it is nil.
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// boolValue returns the value of the bool pointer v, or false if it is nil.
+func boolValue(v *bool) bool {
+	return v != nil && *v
+}
+
+// GeneratesDefaultRoute returns true if any of the attached bits are set in
+// the flags of the LSP, indicating that the originating system is attached to
+// another area, such that level 1 systems should install a default route
+// towards it. The result does not depend upon the level of the LSP.
+func GeneratesDefaultRoute(lsp *oc.Lsp) bool {
+	if lsp == nil {
+		return false
+	}
+	for _, f := range lsp.Flags {
+		switch f {
+		case oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DELAY,
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_EXPENSE, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_ERROR:
+			return true
+		}
+	}
+	return false
+}
+
+// NeighborInfo is a summary of an IS neighbor that is advertised in the IS
+// reachability TLVs of an LSP.
+type NeighborInfo struct {
+	// SystemID is the neighbor ID, consisting of the system ID and the
+	// pseudonode ID of the neighbor - i.e., xxxx.yyyy.zzzz.nn.
+	SystemID string
+	// Metric is the metric of the adjacency to the neighbor. Wide metrics
+	// are used in preference to narrow metrics, and the lowest metric is
+	// used when there are multiple adjacencies to the neighbor.
+	Metric uint32
+	// Wide indicates that the neighbor was advertised in the extended IS
+	// reachability TLV (22).
+	Wide bool
+	// Narrow indicates that the neighbor was advertised in the IS
+	// reachability TLV (2).
+	Narrow bool
+	// Conflict indicates that the neighbor was advertised in both the
+	// narrow and wide IS reachability TLVs with differing metrics.
+	Conflict bool
+}
+
+// ISReachability returns the set of neighbors that are advertised in the IS
+// reachability (2) and extended IS reachability (22) TLVs of the LSP, sorted
+// by system ID. Neighbors that are advertised in both TLVs, as is the case in
+// networks that are migrating between narrow and wide metrics, are merged
+// into a single entry.
+func ISReachability(lsp *oc.Lsp) []NeighborInfo {
+	nbrs := map[string]*NeighborInfo{}
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability(); r != nil {
+		for id, n := range r.Neighbor {
+			ni := &NeighborInfo{SystemID: id, Wide: true}
+			first := true
+			for _, inst := range n.Instance {
+				if m := uint32Value(inst.Metric); first || m < ni.Metric {
+					ni.Metric = m
+					first = false
+				}
+			}
+			nbrs[id] = ni
+		}
+	}
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS).GetIsReachability(); r != nil {
+		for id, n := range r.Neighbor {
+			var m uint32
+			if n.DefaultMetric != nil && n.DefaultMetric.Metric != nil {
+				m = uint32(*n.DefaultMetric.Metric)
+			}
+
+			ni, ok := nbrs[id]
+			if !ok {
+				nbrs[id] = &NeighborInfo{SystemID: id, Metric: m, Narrow: true}
+				continue
+			}
+			ni.Narrow = true
+			ni.Conflict = ni.Metric != m
+		}
+	}
+
+	var ns []NeighborInfo
+	for _, n := range nbrs {
+		ns = append(ns, *n)
+	}
+	sort.Slice(ns, func(i, j int) bool { return ns[i].SystemID < ns[j].SystemID })
+	return ns
+}
+
+// NeighborDelta compares the neighbors that are advertised in two versions of
+// an LSP, prev and cur, as returned by ISReachability, and returns the
+// neighbors that were added and removed. Neighbors are identified by their
+// system ID, such that a change in the metric of an adjacency is not reported.
+// If prev is nil, all neighbors in cur are added. Each slice is sorted by
+// system ID.
+func NeighborDelta(prev, cur *oc.Lsp) (added, removed []NeighborInfo) {
+	prevList := ISReachability(prev)
+	prevNbrs := map[string]bool{}
+	for _, n := range prevList {
+		prevNbrs[n.SystemID] = true
+	}
+
+	for _, n := range ISReachability(cur) {
+		if !prevNbrs[n.SystemID] {
+			added = append(added, n)
+		}
+		delete(prevNbrs, n.SystemID)
+	}
+
+	// Iterate through prev in order, such that the removed neighbors are
+	// sorted.
+	for _, n := range prevList {
+		if prevNbrs[n.SystemID] {
+			removed = append(removed, n)
+		}
+	}
+	return added, removed
+}
+
+// AdjacencySID is a summary of an adjacency SID that is advertised for a
+// neighbor in the extended IS reachability TLV of an LSP.
+type AdjacencySID struct {
+	// Neighbor is the neighbor ID that the adjacency SID is advertised
+	// for, i.e., xxxx.yyyy.zzzz.nn.
+	Neighbor string
+	// Value is the label or index of the SID.
+	Value uint32
+	// Weight is the weight of the SID for load-balancing purposes.
+	Weight uint8
+	// Flags is the set of flags that are set for the SID.
+	Flags []oc.E_OpenconfigIsis_AdjacencySid_Flags
+	// IPv6 indicates that the address family (F) flag is set, such that the
+	// SID refers to an adjacency that is used for IPv6 forwarding. If it is
+	// not set, the SID refers to an adjacency used for IPv4 forwarding.
+	IPv6 bool
+}
+
+// AdjacencySIDs returns the point-to-point adjacency SIDs that are advertised
+// in the extended IS reachability TLV (22) of the LSP, sorted by neighbor ID
+// and then SID value. LAN adjacency SIDs are not included.
+func AdjacencySIDs(lsp *oc.Lsp) []AdjacencySID {
+	var sids []AdjacencySID
+
+	r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability()
+	if r == nil {
+		return nil
+	}
+
+	for id, n := range r.Neighbor {
+		for _, inst := range n.Instance {
+			st := inst.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
+			if st == nil {
+				continue
+			}
+			for _, sid := range st.AdjacencySid {
+				a := AdjacencySID{
+					Neighbor: id,
+					Value:    uint32Value(sid.Value),
+					Flags:    sid.Flags,
+				}
+				if sid.Weight != nil {
+					a.Weight = *sid.Weight
+				}
+				for _, f := range sid.Flags {
+					if f == oc.OpenconfigIsis_AdjacencySid_Flags_ADDRESS_FAMILY {
+						a.IPv6 = true
+					}
+				}
+				sids = append(sids, a)
+			}
+		}
+	}
+
+	sort.Slice(sids, func(i, j int) bool {
+		if sids[i].Neighbor != sids[j].Neighbor {
+			return sids[i].Neighbor < sids[j].Neighbor
+		}
+		return sids[i].Value < sids[j].Value
+	})
+	return sids
+}
+
+// LinkLatencyInfo is a summary of the link delay sub-TLVs (RFC8570) that are
+// advertised for an extended IS reachability neighbor instance. All delays
+// are in microseconds.
+type LinkLatencyInfo struct {
+	// Delay is the average unidirectional link delay (sub-TLV 33).
+	Delay uint32
+	// HasDelay indicates that Delay is populated.
+	HasDelay bool
+	// DelayAnomalous indicates that the anomalous flag is set in the
+	// unidirectional link delay sub-TLV.
+	DelayAnomalous bool
+	// MinDelay and MaxDelay are the minimum and maximum unidirectional link
+	// delay (sub-TLV 34).
+	MinDelay, MaxDelay uint32
+	// HasMinMaxDelay indicates that MinDelay and MaxDelay are populated.
+	HasMinMaxDelay bool
+	// MinMaxDelayAnomalous indicates that the anomalous flag is set in the
+	// min/max unidirectional link delay sub-TLV.
+	MinMaxDelayAnomalous bool
+	// Jitter is the unidirectional delay variation (sub-TLV 35).
+	Jitter uint32
+	// HasJitter indicates that Jitter is populated.
+	HasJitter bool
+}
+
+// LinkLatency returns the link delay, min/max link delay and delay variation
+// that are advertised for the neighbor instance n. The bool returned is false
+// if none of the link delay sub-TLVs are present.
+func LinkLatency(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) (LinkLatencyInfo, bool) {
+	var l LinkLatencyInfo
+
+	if d := n.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY).GetLinkDelay(); d != nil && d.Delay != nil {
+		l.Delay, l.HasDelay = *d.Delay, true
+		l.DelayAnomalous = boolValue(d.ABit)
+	}
+
+	if d := n.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY).GetMinMaxLinkDelay(); d != nil && d.MinDelay != nil && d.MaxDelay != nil {
+		l.MinDelay, l.MaxDelay, l.HasMinMaxDelay = *d.MinDelay, *d.MaxDelay, true
+		l.MinMaxDelayAnomalous = boolValue(d.ABit)
+	}
+
+	if d := n.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY_VARIATION).GetLinkDelayVariation(); d != nil && d.Delay != nil {
+		l.Jitter, l.HasJitter = *d.Delay, true
+	}
+
+	return l, l.HasDelay || l.HasMinMaxDelay || l.HasJitter
+}
+
+// GlobalIPv6InterfaceAddresses returns the IPv6 interface addresses that are
+// advertised in the IPv6 interface address TLV (232) of the LSP that are of
+// global scope, in the order in which they appear in the TLV.
+func GlobalIPv6InterfaceAddresses(lsp *oc.Lsp) []string {
+	return ipv6InterfaceAddresses(lsp, func(ip net.IP) bool {
+		return ip.IsGlobalUnicast()
+	})
+}
+
+// LinkLocalIPv6InterfaceAddresses returns the IPv6 interface addresses that
+// are advertised in the IPv6 interface address TLV (232) of the LSP that are
+// link-local (fe80::/10), in the order in which they appear in the TLV.
+func LinkLocalIPv6InterfaceAddresses(lsp *oc.Lsp) []string {
+	return ipv6InterfaceAddresses(lsp, func(ip net.IP) bool {
+		return ip.IsLinkLocalUnicast()
+	})
+}
+
+// ipv6InterfaceAddresses returns the IPv6 interface addresses of the LSP for
+// which the match function returns true. Addresses that cannot be parsed are
+// skipped.
+func ipv6InterfaceAddresses(lsp *oc.Lsp, match func(net.IP) bool) []string {
+	t := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES).GetIpv6InterfaceAddresses()
+	if t == nil {
+		return nil
+	}
+
+	var addrs []string
+	for _, a := range t.Address {
+		ip := net.ParseIP(a)
+		if ip == nil || ip.To4() != nil {
+			continue
+		}
+		if match(ip) {
+			addrs = append(addrs, a)
+		}
+	}
+	return addrs
+}
+
+// CommonAreaAddresses returns the area addresses that are advertised in the
+// area addresses TLV (1) of both a and b, in the order in which they appear
+// in a. Level 1 neighbors that share no area address cannot form an
+// adjacency. If either LSP does not carry TLV 1, the result is empty.
+func CommonAreaAddresses(a, b *oc.Lsp) []string {
+	bAreas := map[string]bool{}
+	for _, addr := range areaAddresses(b) {
+		bAreas[addr] = true
+	}
+
+	var common []string
+	for _, addr := range areaAddresses(a) {
+		if bAreas[addr] {
+			common = append(common, addr)
+			// Avoid reporting an area that is duplicated in a more than once.
+			delete(bAreas, addr)
+		}
+	}
+	return common
+}
+
+// areaAddresses returns the area addresses that are advertised in the area
+// addresses TLV of the LSP.
+func areaAddresses(lsp *oc.Lsp) []string {
+	t := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES).GetAreaAddress()
+	if t == nil {
+		return nil
+	}
+	return t.Address
+}
+
+// nlpidTLVType is the type of the Protocols Supported TLV, defined in RFC 1195.
+const nlpidTLVType uint8 = 129
+
+// UnknownNLPIDs takes an input slice of bytes that contain an IS-IS LSP
+// starting at the LSP ID field, discarding the first offset bytes, and returns
+// the NLPIDs advertised in its Protocols Supported TLVs (129) that cannot be
+// represented in the OpenConfig model - i.e., those other than IPv4 and IPv6,
+// such as CLNP (0x81). Returns an error if the TLVs cannot be extracted.
+func UnknownNLPIDs(lspBytes []byte, offset int) ([]uint8, error) {
+	var ids []uint8
+	err := ParseTLVStream(lspBytes, offset, func(tlvType uint8, value []byte) error {
+		if tlvType != nlpidTLVType {
+			return nil
+		}
+		for _, b := range value {
+			if _, ok := nlpidValues[b]; !ok {
+				ids = append(ids, b)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// LSPClass is a classification of an LSP according to the system that
+// originated it.
+type LSPClass int
+
+const (
+	// LSPClassUnknown indicates that the LSP could not be classified, since
+	// its LSP ID or IS type is missing or invalid.
+	LSPClassUnknown LSPClass = iota
+	// Level1Router indicates that the LSP was originated by a level 1
+	// intermediate system.
+	Level1Router
+	// Level2Router indicates that the LSP was originated by a level 2
+	// intermediate system. ISO10589 uses the same IS type for systems that
+	// are level 2 only and those that are both level 1 and level 2, such
+	// that the two cannot be distinguished from the LSP.
+	Level2Router
+	// Pseudonode indicates that the LSP was originated on behalf of a
+	// broadcast network by its designated intermediate system.
+	Pseudonode
+)
+
+// String returns a human-readable name for the LSP class.
+func (c LSPClass) String() string {
+	switch c {
+	case Level1Router:
+		return "LEVEL_1_ROUTER"
+	case Level2Router:
+		return "LEVEL_2_ROUTER"
+	case Pseudonode:
+		return "PSEUDONODE"
+	}
+	return "UNKNOWN"
+}
+
+// ClassifyLSP returns the class of the LSP, based on the pseudonode ID within
+// its LSP ID, and its IS type. LSPs with a non-zero pseudonode ID are
+// classified as pseudonode LSPs regardless of their IS type.
+func ClassifyLSP(lsp *oc.Lsp) LSPClass {
+	if lsp == nil || lsp.LspId == nil {
+		return LSPClassUnknown
+	}
+
+	pn, ok := pseudonodeID(*lsp.LspId)
+	switch {
+	case !ok:
+		return LSPClassUnknown
+	case pn != 0:
+		return Pseudonode
+	}
+
+	if lsp.IsType == nil {
+		return LSPClassUnknown
+	}
+	// The IS type values are defined in ISO10589, values 0 and 2 are unused.
+	switch *lsp.IsType {
+	case 1:
+		return Level1Router
+	case 3:
+		return Level2Router
+	}
+	return LSPClassUnknown
+}
+
+// pseudonodeID returns the pseudonode ID from an LSP ID in the canonical
+// format, xxxx.yyyy.zzzz.pp-ff. Returns false if the LSP ID is not in the
+// expected format.
+func pseudonodeID(lspID string) (uint8, bool) {
+	if len(lspID) != len("xxxx.yyyy.zzzz.pp-ff") || lspID[14] != '.' || lspID[17] != '-' {
+		return 0, false
+	}
+	pn, err := strconv.ParseUint(lspID[15:17], 16, 8)
+	if err != nil {
+		return 0, false
+	}
+	return uint8(pn), true
+}
+
+// OriginatingSystemID returns the system ID of the IS that originated the
+// LSP, in the format xxxx.yyyy.zzzz, based on its LSP ID. Returns an empty
+// string if the LSP does not have a valid LSP ID.
+func OriginatingSystemID(lsp *oc.Lsp) string {
+	if lsp == nil || lsp.LspId == nil {
+		return ""
+	}
+	if _, ok := pseudonodeID(*lsp.LspId); !ok {
+		return ""
+	}
+	return (*lsp.LspId)[:len("xxxx.yyyy.zzzz")]
+}
+
+// RouteIntent describes a prefix that an IS advertises reachability to, and
+// the cost at which it can be reached from that IS.
+type RouteIntent struct {
+	// Prefix is the advertised prefix, in CIDR format.
+	Prefix string
+	// SystemID is the system ID of the IS that advertised the prefix.
+	SystemID string
+	// Metric is the metric of the prefix.
+	Metric uint32
+}
+
+// RouteIntents returns the prefixes that are advertised in the IP
+// reachability TLVs of the LSP, paired with the system ID of the IS that
+// originated the LSP, and sorted in the same order as Prefixes. It provides
+// the per-node input to an SPF computation that is performed externally.
+func RouteIntents(lsp *oc.Lsp) []RouteIntent {
+	sysID := OriginatingSystemID(lsp)
+
+	var ris []RouteIntent
+	for _, p := range Prefixes(lsp) {
+		ris = append(ris, RouteIntent{
+			Prefix:   p.Prefix,
+			SystemID: sysID,
+			Metric:   p.Metric,
+		})
+	}
+	return ris
+}
+
+// TopologyView is the view of an LSP for a single topology. It joins the
+// reachability that is advertised for the topology with the attributes of
+// the node, such as its hostname, which are not scoped to a topology.
+type TopologyView struct {
+	// MTID is the multi-topology ID of the topology, where 0 is the
+	// standard topology.
+	MTID uint16
+	// SystemID is the system ID of the IS that originated the LSP.
+	SystemID string
+	// Hostname is the hostname advertised in the Dynamic Hostname TLV (137)
+	// of the LSP, which applies to all topologies.
+	Hostname string
+	// Prefixes is the set of prefixes that are advertised for the topology,
+	// sorted by TLV type and prefix.
+	Prefixes []PrefixInfo
+}
+
+// TopologyViews returns a view of the LSP for each topology that it
+// advertises, sorted by MT ID. The Dynamic Hostname TLV (137) and the Router
+// Capability TLV (242) are not scoped to a topology, and hence the node-level
+// attributes within them apply to every topology. Topologies are those listed
+// in the Multi-Topology TLV (229), or for which MT IPv4 reachability (235) is
+// advertised. The standard topology (MT ID 0) is included if the LSP contains
+// prefixes in the IP reachability TLVs (135, 236) that are not scoped to a
+// topology.
+func TopologyViews(lsp *oc.Lsp) []TopologyView {
+	views := map[uint16]*TopologyView{}
+	view := func(id uint16) *TopologyView {
+		if v, ok := views[id]; ok {
+			return v
+		}
+		v := &TopologyView{MTID: id}
+		views[id] = v
+		return v
+	}
+
+	if pfxs := Prefixes(lsp); len(pfxs) != 0 {
+		view(0).Prefixes = pfxs
+	}
+
+	if mt := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY).GetMultiTopology(); mt != nil {
+		for id := range mt.Topology {
+			view(id)
+		}
+	}
+
+	if r := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY).GetMtIpv4Reachability(); r != nil {
+		for k, p := range r.Prefix {
+			var ext bool
+			if st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS]; st != nil && st.Flags != nil {
+				ext = hasExternalFlag(st.Flags.Flags)
+			}
+			v := view(k.MtId)
+			v.Prefixes = append(v.Prefixes, PrefixInfo{
+				Prefix:   k.Prefix,
+				TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY,
+				Metric:   uint32Value(p.Metric),
+				UpDown:   boolValue(p.UpDown),
+				External: ext,
+				DoNotUse: uint32Value(p.Metric) > maxPathMetric,
+			})
+		}
+	}
+
+	var hostname string
+	if h := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetHostname(); h != nil && len(h.Hostname) != 0 {
+		hostname = h.Hostname[0]
+	}
+	sysID := OriginatingSystemID(lsp)
+
+	var vs []TopologyView
+	for _, v := range views {
+		v.SystemID = sysID
+		v.Hostname = hostname
+		sort.Slice(v.Prefixes, func(i, j int) bool {
+			if v.Prefixes[i].TLV != v.Prefixes[j].TLV {
+				return v.Prefixes[i].TLV < v.Prefixes[j].TLV
+			}
+			return v.Prefixes[i].Prefix < v.Prefixes[j].Prefix
+		})
+		vs = append(vs, *v)
+	}
+	sort.Slice(vs, func(i, j int) bool { return vs[i].MTID < vs[j].MTID })
+	return vs
+}
@@ -0,0 +1,948 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestPrefixes(t *testing.T) {
+	tests := []struct {
+		name  string
+		inLSP *oc.Lsp
+		want  []PrefixInfo
+	}{{
+		name: "IPv4 prefix with X-flag",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+					ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+						Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+							"192.0.2.0/24": {
+								Prefix: ygot.String("192.0.2.0/24"),
+								Metric: ygot.Uint32(10),
+								Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+									oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS: {
+										Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS,
+										Flags: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Flags{
+											Flags: []oc.E_OpenconfigIsis_Flags_Flags{
+												oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG,
+											},
+										},
+									},
+								},
+							},
+							"198.51.100.0/24": {
+								Prefix: ygot.String("198.51.100.0/24"),
+								Metric: ygot.Uint32(20),
+								UpDown: ygot.Bool(true),
+								Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+									oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS: {
+										Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS,
+										Flags: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Flags{
+											Flags: []oc.E_OpenconfigIsis_Flags_Flags{
+												oc.OpenconfigIsis_Flags_Flags_NODE_FLAG,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		want: []PrefixInfo{{
+			Prefix:   "192.0.2.0/24",
+			TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+			Metric:   10,
+			External: true,
+		}, {
+			Prefix: "198.51.100.0/24",
+			TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+			Metric: 20,
+			UpDown: true,
+		}},
+	}, {
+		name: "IPv6 prefixes with external origin bit and X-flag",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+					Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+						Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+							"2001:db8::/32": {
+								Prefix: ygot.String("2001:db8::/32"),
+								Metric: ygot.Uint32(10),
+								XBit:   ygot.Bool(true),
+							},
+							"2001:db8:1::/48": {
+								Prefix: ygot.String("2001:db8:1::/48"),
+								Metric: ygot.Uint32(10),
+								Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+									oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS: {
+										Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS,
+										Flags: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Flags{
+											Flags: []oc.E_OpenconfigIsis_Flags_Flags{
+												oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG,
+											},
+										},
+									},
+								},
+							},
+							"2001:db8:2::/48": {
+								Prefix: ygot.String("2001:db8:2::/48"),
+								Metric: ygot.Uint32(30),
+								XBit:   ygot.Bool(false),
+							},
+						},
+					},
+				},
+			},
+		},
+		want: []PrefixInfo{{
+			Prefix:   "2001:db8:1::/48",
+			TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+			Metric:   10,
+			External: true,
+		}, {
+			Prefix: "2001:db8:2::/48",
+			TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+			Metric: 30,
+		}, {
+			Prefix:   "2001:db8::/32",
+			TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+			Metric:   10,
+			External: true,
+		}},
+	}, {
+		name:  "no reachability TLVs",
+		inLSP: &oc.Lsp{},
+	}, {
+		name: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		if diff := pretty.Compare(Prefixes(tt.inLSP), tt.want); diff != "" {
+			t.Errorf("%s: Prefixes(%v): did not get expected prefixes, diff(-got,+want):\n%s", tt.name, tt.inLSP, diff)
+		}
+	}
+}
+
+func TestTaggedPrefixes(t *testing.T) {
+	lsp := &oc.Lsp{}
+	// 192.0.2.0/24 has tags 100 and 200, 198.51.100.0/24 has tag 200, and
+	// 203.0.113.0/24 has no tags.
+	if err := ParseTLV(lsp, 135, []byte{
+		0x00, 0x00, 0x00, 0x0A, 0x58, 192, 0, 2,
+		0x0A, 1, 8, 0x00, 0x00, 0x00, 0x64, 0x00, 0x00, 0x00, 0xC8,
+		0x00, 0x00, 0x00, 0x0A, 0x58, 198, 51, 100,
+		0x06, 1, 4, 0x00, 0x00, 0x00, 0xC8,
+		0x00, 0x00, 0x00, 0x0A, 0x18, 203, 0, 113,
+	}); err != nil {
+		t.Fatalf("ParseTLV(135, ...): got unexpected error: %v", err)
+	}
+	// 2001:db8::/32 has tag 100.
+	if err := ParseTLV(lsp, 236, []byte{
+		0x00, 0x00, 0x00, 0x0A, 0x20, 0x20, 0x20, 0x01, 0x0D, 0xB8,
+		0x06, 1, 4, 0x00, 0x00, 0x00, 0x64,
+	}); err != nil {
+		t.Fatalf("ParseTLV(236, ...): got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		inTag uint32
+		want  []string
+	}{{
+		name:  "tag on IPv4 and IPv6 prefixes",
+		inTag: 100,
+		want:  []string{"192.0.2.0/24", "2001:db8::/32"},
+	}, {
+		name:  "tag shared by IPv4 prefixes",
+		inTag: 200,
+		want:  []string{"192.0.2.0/24", "198.51.100.0/24"},
+	}, {
+		name:  "tag not advertised",
+		inTag: 300,
+	}}
+
+	for _, tt := range tests {
+		if diff := pretty.Compare(TaggedPrefixes(lsp, tt.inTag), tt.want); diff != "" {
+			t.Errorf("%s: TaggedPrefixes(%v, %d): did not get expected prefixes, diff(-got,+want):\n%s", tt.name, lsp, tt.inTag, diff)
+		}
+	}
+}
+
+func TestGeneratesDefaultRoute(t *testing.T) {
+	tests := []struct {
+		name  string
+		inLSP *oc.Lsp
+		want  bool
+	}{{
+		name: "default metric attached bit",
+		inLSP: &oc.Lsp{
+			Flags: []oc.E_OpenconfigIsis_Lsp_Flags{oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT},
+		},
+		want: true,
+	}, {
+		name: "error metric attached bit with overload",
+		inLSP: &oc.Lsp{
+			Flags: []oc.E_OpenconfigIsis_Lsp_Flags{oc.OpenconfigIsis_Lsp_Flags_OVERLOAD, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_ERROR},
+		},
+		want: true,
+	}, {
+		name: "level 2 LSP with attached bit",
+		inLSP: &oc.Lsp{
+			PduType: oc.OpenconfigIsis_Lsp_PduType_LEVEL_2,
+			Flags:   parseLSPFlags(0x08),
+		},
+		want: true,
+	}, {
+		name: "no attached bits",
+		inLSP: &oc.Lsp{
+			Flags: []oc.E_OpenconfigIsis_Lsp_Flags{oc.OpenconfigIsis_Lsp_Flags_OVERLOAD, oc.OpenconfigIsis_Lsp_Flags_PARTITION_REPAIR},
+		},
+	}, {
+		name:  "no flags",
+		inLSP: &oc.Lsp{},
+	}, {
+		name: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		if got := GeneratesDefaultRoute(tt.inLSP); got != tt.want {
+			t.Errorf("%s: GeneratesDefaultRoute(%v): did not get expected result, got: %v, want: %v", tt.name, tt.inLSP, got, tt.want)
+		}
+	}
+}
+
+func TestISReachability(t *testing.T) {
+	type tlv struct {
+		tlvType uint8
+		value   []byte
+	}
+
+	tests := []struct {
+		name   string
+		inTLVs []tlv
+		want   []NeighborInfo
+	}{{
+		name: "same neighbor in narrow and wide TLVs with differing metrics",
+		inTLVs: []tlv{{
+			tlvType: 2,
+			value: []byte{
+				0x00,
+				0x0A, 0x80, 0x80, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00,
+				0x14, 0x80, 0x80, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x03, 0x00,
+			},
+		}, {
+			tlvType: 22,
+			value: []byte{
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x64, 0x00,
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, 0x00, 0x00, 0x0A, 0x00,
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x03, 0x00, 0x00, 0x00, 0x14, 0x00,
+			},
+		}},
+		want: []NeighborInfo{{
+			SystemID: "1920.0000.2001.00",
+			Metric:   100,
+			Wide:     true,
+			Narrow:   true,
+			Conflict: true,
+		}, {
+			SystemID: "1920.0000.2002.00",
+			Metric:   10,
+			Wide:     true,
+		}, {
+			SystemID: "1920.0000.2003.00",
+			Metric:   20,
+			Wide:     true,
+			Narrow:   true,
+		}},
+	}, {
+		name: "narrow only",
+		inTLVs: []tlv{{
+			tlvType: 2,
+			value: []byte{
+				0x00,
+				0x0A, 0x80, 0x80, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00,
+			},
+		}},
+		want: []NeighborInfo{{
+			SystemID: "1920.0000.2001.00",
+			Metric:   10,
+			Narrow:   true,
+		}},
+	}, {
+		name: "multiple wide adjacencies to the same neighbor",
+		inTLVs: []tlv{{
+			tlvType: 22,
+			value: []byte{
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x64, 0x00,
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x32, 0x00,
+			},
+		}},
+		want: []NeighborInfo{{
+			SystemID: "1920.0000.2001.00",
+			Metric:   50,
+			Wide:     true,
+		}},
+	}, {
+		name: "no IS reachability",
+	}}
+
+	for _, tt := range tests {
+		lsp := &oc.Lsp{}
+		for _, r := range tt.inTLVs {
+			if err := ParseTLV(lsp, r.tlvType, r.value); err != nil {
+				t.Fatalf("%s: ParseTLV(%d, %v): got unexpected error: %v", tt.name, r.tlvType, r.value, err)
+			}
+		}
+
+		if diff := pretty.Compare(ISReachability(lsp), tt.want); diff != "" {
+			t.Errorf("%s: ISReachability(%v): did not get expected neighbors, diff(-got,+want):\n%s", tt.name, lsp, diff)
+		}
+	}
+}
+
+func TestNeighborDelta(t *testing.T) {
+	// wideLSP returns an LSP advertising a wide metric of 10 to each of
+	// the neighbors, identified by the last byte of their system ID.
+	wideLSP := func(nbrs ...byte) *oc.Lsp {
+		var v []byte
+		for _, n := range nbrs {
+			v = append(v, 0x19, 0x20, 0x00, 0x00, 0x20, n, 0x00, 0x00, 0x00, 0x0A, 0x00)
+		}
+		lsp := &oc.Lsp{}
+		if err := ParseTLV(lsp, 22, v); err != nil {
+			t.Fatalf("ParseTLV(22, %v): got unexpected error: %v", v, err)
+		}
+		return lsp
+	}
+
+	nbr := func(id string) NeighborInfo {
+		return NeighborInfo{SystemID: id, Metric: 10, Wide: true}
+	}
+
+	tests := []struct {
+		name        string
+		inPrev      *oc.Lsp
+		inCur       *oc.Lsp
+		wantAdded   []NeighborInfo
+		wantRemoved []NeighborInfo
+	}{{
+		name:        "added and removed neighbor",
+		inPrev:      wideLSP(0x01, 0x02),
+		inCur:       wideLSP(0x01, 0x03),
+		wantAdded:   []NeighborInfo{nbr("1920.0000.2003.00")},
+		wantRemoved: []NeighborInfo{nbr("1920.0000.2002.00")},
+	}, {
+		name:   "unchanged",
+		inPrev: wideLSP(0x01),
+		inCur:  wideLSP(0x01),
+	}, {
+		name:      "nil previous LSP",
+		inCur:     wideLSP(0x01, 0x02),
+		wantAdded: []NeighborInfo{nbr("1920.0000.2001.00"), nbr("1920.0000.2002.00")},
+	}, {
+		name:        "nil current LSP",
+		inPrev:      wideLSP(0x01),
+		wantRemoved: []NeighborInfo{nbr("1920.0000.2001.00")},
+	}}
+
+	for _, tt := range tests {
+		added, removed := NeighborDelta(tt.inPrev, tt.inCur)
+		if diff := pretty.Compare(added, tt.wantAdded); diff != "" {
+			t.Errorf("%s: NeighborDelta(...): did not get expected added neighbors, diff(-got,+want):\n%s", tt.name, diff)
+		}
+		if diff := pretty.Compare(removed, tt.wantRemoved); diff != "" {
+			t.Errorf("%s: NeighborDelta(...): did not get expected removed neighbors, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestAdjacencySIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want []AdjacencySID
+	}{{
+		name: "IPv4 and IPv6 adjacency SIDs",
+		in: []byte{
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x0A,
+			// Sub-TLV length
+			0x0E,
+			// Adjacency SID, F, V and L flags set, label 16000.
+			31, 5, 0xB0, 0x00, 0x00, 0x3E, 0x80,
+			// Adjacency SID, V and L flags set, weight 10, label 16001.
+			31, 5, 0x30, 0x0A, 0x00, 0x3E, 0x81,
+		},
+		want: []AdjacencySID{{
+			Neighbor: "1920.0000.2001.00",
+			Value:    16000,
+			Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+				oc.OpenconfigIsis_AdjacencySid_Flags_ADDRESS_FAMILY,
+				oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+				oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+			},
+			IPv6: true,
+		}, {
+			Neighbor: "1920.0000.2001.00",
+			Value:    16001,
+			Weight:   10,
+			Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+				oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+				oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+			},
+		}},
+	}, {
+		name: "neighbor without adjacency SIDs",
+		in:   []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x0A, 0x00},
+	}}
+
+	for _, tt := range tests {
+		lsp := &oc.Lsp{}
+		if err := ParseTLV(lsp, 22, tt.in); err != nil {
+			t.Fatalf("%s: ParseTLV(22, %v): got unexpected error: %v", tt.name, tt.in, err)
+		}
+
+		if diff := pretty.Compare(AdjacencySIDs(lsp), tt.want); diff != "" {
+			t.Errorf("%s: AdjacencySIDs(%v): did not get expected SIDs, diff(-got,+want):\n%s", tt.name, lsp, diff)
+		}
+	}
+
+	if got := AdjacencySIDs(nil); got != nil {
+		t.Errorf("AdjacencySIDs(nil): got: %v, want: nil", got)
+	}
+}
+
+func TestLinkLatency(t *testing.T) {
+	tests := []struct {
+		name   string
+		in     []byte
+		want   LinkLatencyInfo
+		wantOK bool
+	}{{
+		name: "all delay sub-TLVs",
+		in: []byte{
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x0A,
+			// Sub-TLV length
+			0x15,
+			// Unidirectional link delay, 1000us.
+			33, 4, 0x00, 0x00, 0x03, 0xE8,
+			// Min/max unidirectional link delay, A flag set, 900us to 1200us.
+			34, 7, 0x80, 0x00, 0x03, 0x84, 0x00, 0x04, 0xB0,
+			// Unidirectional delay variation, 50us.
+			35, 4, 0x00, 0x00, 0x00, 0x32,
+		},
+		want: LinkLatencyInfo{
+			Delay:                1000,
+			HasDelay:             true,
+			MinDelay:             900,
+			MaxDelay:             1200,
+			HasMinMaxDelay:       true,
+			MinMaxDelayAnomalous: true,
+			Jitter:               50,
+			HasJitter:            true,
+		},
+		wantOK: true,
+	}, {
+		name: "only delay variation",
+		in: []byte{
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x0A,
+			0x06,
+			35, 4, 0x00, 0x00, 0x00, 0x32,
+		},
+		want: LinkLatencyInfo{
+			Jitter:    50,
+			HasJitter: true,
+		},
+		wantOK: true,
+	}, {
+		name: "no delay sub-TLVs",
+		in:   []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x0A, 0x00},
+	}}
+
+	for _, tt := range tests {
+		lsp := &oc.Lsp{}
+		if err := ParseTLV(lsp, 22, tt.in); err != nil {
+			t.Fatalf("%s: ParseTLV(22, %v): got unexpected error: %v", tt.name, tt.in, err)
+		}
+		inst := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability().GetNeighbor("1920.0000.2001.00").GetInstance(0)
+
+		got, ok := LinkLatency(inst)
+		if ok != tt.wantOK {
+			t.Errorf("%s: LinkLatency(%v): got unexpected ok, got: %v, want: %v", tt.name, inst, ok, tt.wantOK)
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: LinkLatency(%v): did not get expected latency, diff(-got,+want):\n%s", tt.name, inst, diff)
+		}
+	}
+}
+
+func TestPrefixDelta(t *testing.T) {
+	// v4LSP returns an LSP advertising the supplied IPv4 prefixes with
+	// their metrics in the extended IPv4 reachability TLV.
+	v4LSP := func(metrics map[string]uint32) *oc.Lsp {
+		r := &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+			Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{},
+		}
+		for pfx, m := range metrics {
+			r.Prefix[pfx] = &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+				Prefix: ygot.String(pfx),
+				Metric: ygot.Uint32(m),
+			}
+		}
+		return &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+					Type:                     oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+					ExtendedIpv4Reachability: r,
+				},
+			},
+		}
+	}
+
+	v4 := func(pfx string, metric uint32) PrefixInfo {
+		return PrefixInfo{
+			Prefix: pfx,
+			TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+			Metric: metric,
+		}
+	}
+
+	upDownLSP := v4LSP(map[string]uint32{"192.0.2.0/24": 10})
+	upDownLSP.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY].ExtendedIpv4Reachability.Prefix["192.0.2.0/24"].UpDown = ygot.Bool(true)
+
+	tests := []struct {
+		name        string
+		inPrev      *oc.Lsp
+		inCur       *oc.Lsp
+		wantAdded   []PrefixInfo
+		wantRemoved []PrefixInfo
+		wantChanged []PrefixInfo
+	}{{
+		name:   "added, removed and metric changed",
+		inPrev: v4LSP(map[string]uint32{"192.0.2.0/24": 10, "198.51.100.0/24": 20}),
+		inCur:  v4LSP(map[string]uint32{"192.0.2.0/24": 30, "203.0.113.0/24": 40}),
+		wantAdded: []PrefixInfo{
+			v4("203.0.113.0/24", 40),
+		},
+		wantRemoved: []PrefixInfo{
+			v4("198.51.100.0/24", 20),
+		},
+		wantChanged: []PrefixInfo{
+			v4("192.0.2.0/24", 30),
+		},
+	}, {
+		name:   "flag changed",
+		inPrev: v4LSP(map[string]uint32{"192.0.2.0/24": 10}),
+		inCur:  upDownLSP,
+		wantChanged: []PrefixInfo{{
+			Prefix: "192.0.2.0/24",
+			TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+			Metric: 10,
+			UpDown: true,
+		}},
+	}, {
+		name:   "unchanged",
+		inPrev: v4LSP(map[string]uint32{"192.0.2.0/24": 10}),
+		inCur:  v4LSP(map[string]uint32{"192.0.2.0/24": 10}),
+	}, {
+		name:  "nil previous LSP",
+		inCur: v4LSP(map[string]uint32{"192.0.2.0/24": 10, "198.51.100.0/24": 20}),
+		wantAdded: []PrefixInfo{
+			v4("192.0.2.0/24", 10),
+			v4("198.51.100.0/24", 20),
+		},
+	}, {
+		name:   "nil current LSP",
+		inPrev: v4LSP(map[string]uint32{"192.0.2.0/24": 10}),
+		wantRemoved: []PrefixInfo{
+			v4("192.0.2.0/24", 10),
+		},
+	}}
+
+	for _, tt := range tests {
+		added, removed, changed := PrefixDelta(tt.inPrev, tt.inCur)
+		if diff := pretty.Compare(added, tt.wantAdded); diff != "" {
+			t.Errorf("%s: PrefixDelta(...): did not get expected added prefixes, diff(-got,+want):\n%s", tt.name, diff)
+		}
+		if diff := pretty.Compare(removed, tt.wantRemoved); diff != "" {
+			t.Errorf("%s: PrefixDelta(...): did not get expected removed prefixes, diff(-got,+want):\n%s", tt.name, diff)
+		}
+		if diff := pretty.Compare(changed, tt.wantChanged); diff != "" {
+			t.Errorf("%s: PrefixDelta(...): did not get expected changed prefixes, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestIPv6InterfaceAddressScope(t *testing.T) {
+	lsp := &oc.Lsp{
+		Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+			oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES: {
+				Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES,
+				Ipv6InterfaceAddresses: &oc.Lsp_Tlv_Ipv6InterfaceAddresses{
+					Address: []string{
+						"2001:db8::1",
+						"fe80::1",
+						"2001:0db8:0000:0000:0000:0000:0000:0002",
+						"fe80:0000:0000:0000:0000:0000:0000:0002",
+						"not-an-address",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		inLSP         *oc.Lsp
+		wantGlobal    []string
+		wantLinkLocal []string
+	}{{
+		name:          "global and link-local addresses",
+		inLSP:         lsp,
+		wantGlobal:    []string{"2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0002"},
+		wantLinkLocal: []string{"fe80::1", "fe80:0000:0000:0000:0000:0000:0000:0002"},
+	}, {
+		name:  "no IPv6 interface address TLV",
+		inLSP: &oc.Lsp{},
+	}, {
+		name: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		if diff := pretty.Compare(GlobalIPv6InterfaceAddresses(tt.inLSP), tt.wantGlobal); diff != "" {
+			t.Errorf("%s: GlobalIPv6InterfaceAddresses(%v): did not get expected addresses, diff(-got,+want):\n%s", tt.name, tt.inLSP, diff)
+		}
+		if diff := pretty.Compare(LinkLocalIPv6InterfaceAddresses(tt.inLSP), tt.wantLinkLocal); diff != "" {
+			t.Errorf("%s: LinkLocalIPv6InterfaceAddresses(%v): did not get expected addresses, diff(-got,+want):\n%s", tt.name, tt.inLSP, diff)
+		}
+	}
+}
+
+func TestCommonAreaAddresses(t *testing.T) {
+	areaLSP := func(addrs ...string) *oc.Lsp {
+		return &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES,
+					AreaAddress: &oc.Lsp_Tlv_AreaAddress{
+						Address: addrs,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		inA  *oc.Lsp
+		inB  *oc.Lsp
+		want []string
+	}{{
+		name: "identical areas",
+		inA:  areaLSP("49.0001"),
+		inB:  areaLSP("49.0001"),
+		want: []string{"49.0001"},
+	}, {
+		name: "overlapping areas",
+		inA:  areaLSP("49.0001", "49.0002", "49.0003"),
+		inB:  areaLSP("49.0003", "49.0004", "49.0001"),
+		want: []string{"49.0001", "49.0003"},
+	}, {
+		name: "duplicate area in a",
+		inA:  areaLSP("49.0001", "49.0001"),
+		inB:  areaLSP("49.0001"),
+		want: []string{"49.0001"},
+	}, {
+		name: "disjoint areas",
+		inA:  areaLSP("49.0001", "49.0002"),
+		inB:  areaLSP("49.0003"),
+	}, {
+		name: "a has no area address TLV",
+		inA:  &oc.Lsp{},
+		inB:  areaLSP("49.0001"),
+	}, {
+		name: "b has no area address TLV",
+		inA:  areaLSP("49.0001"),
+		inB:  &oc.Lsp{},
+	}, {
+		name: "nil LSPs",
+	}}
+
+	for _, tt := range tests {
+		if diff := pretty.Compare(CommonAreaAddresses(tt.inA, tt.inB), tt.want); diff != "" {
+			t.Errorf("%s: CommonAreaAddresses(%v, %v): did not get expected areas, diff(-got,+want):\n%s", tt.name, tt.inA, tt.inB, diff)
+		}
+	}
+}
+
+func TestUnknownNLPIDs(t *testing.T) {
+	lspHeader := []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03}
+
+	tests := []struct {
+		name             string
+		inTLVs           []byte
+		want             []uint8
+		wantErrSubstring string
+	}{{
+		name:   "known NLPIDs",
+		inTLVs: []byte{129, 2, 0xCC, 0x8E},
+	}, {
+		name:   "CLNP and IPv4",
+		inTLVs: []byte{129, 2, 0x81, 0xCC},
+		want:   []uint8{0x81},
+	}, {
+		name:   "unknown NLPIDs in multiple TLVs",
+		inTLVs: []byte{129, 2, 0x81, 0xCC, 137, 2, 'r', '1', 129, 1, 0x42},
+		want:   []uint8{0x81, 0x42},
+	}, {
+		name:             "truncated TLV",
+		inTLVs:           []byte{129, 2, 0x81},
+		wantErrSubstring: "invalid TLVs",
+	}}
+
+	for _, tt := range tests {
+		in := append(append([]byte{}, lspHeader...), tt.inTLVs...)
+		got, err := UnknownNLPIDs(in, 0)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: UnknownNLPIDs(%v): did not get expected error, %s", tt.name, in, diff)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: UnknownNLPIDs(%v): did not get expected NLPIDs, diff(-got,+want):\n%s", tt.name, in, diff)
+		}
+
+		lsp, ok, err := ISISBytesToLSP(in, 0)
+		if !ok || err != nil {
+			t.Errorf("%s: ISISBytesToLSP(%v): could not parse LSP, ok: %v, err: %v", tt.name, in, ok, err)
+			continue
+		}
+		if u := lsp.GetUndefinedTlv(nlpidTLVType); u != nil {
+			t.Errorf("%s: ISISBytesToLSP(%v): got unexpected undefined NLPID TLV: %v", tt.name, in, u)
+		}
+	}
+}
+
+func TestClassifyLSP(t *testing.T) {
+	tests := []struct {
+		name  string
+		inLSP *oc.Lsp
+		want  LSPClass
+	}{{
+		name:  "level 1 router",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00"), IsType: ygot.Uint8(1)},
+		want:  Level1Router,
+	}, {
+		name:  "level 2 router",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-01"), IsType: ygot.Uint8(3)},
+		want:  Level2Router,
+	}, {
+		name:  "pseudonode",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.02-00"), IsType: ygot.Uint8(3)},
+		want:  Pseudonode,
+	}, {
+		name:  "unused IS type",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00"), IsType: ygot.Uint8(2)},
+		want:  LSPClassUnknown,
+	}, {
+		name:  "missing IS type",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")},
+		want:  LSPClassUnknown,
+	}, {
+		name:  "invalid LSP ID",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.zz-00"), IsType: ygot.Uint8(3)},
+		want:  LSPClassUnknown,
+	}, {
+		name: "nil LSP",
+		want: LSPClassUnknown,
+	}}
+
+	for _, tt := range tests {
+		if got := ClassifyLSP(tt.inLSP); got != tt.want {
+			t.Errorf("%s: ClassifyLSP(%v): did not get expected class, got: %v, want: %v", tt.name, tt.inLSP, got, tt.want)
+		}
+	}
+
+	// The IS type and LSP ID are parsed from the LSP header.
+	lsp, _, err := ISISBytesToLSP(exampleLSP2, 0)
+	if err != nil {
+		t.Fatalf("ISISBytesToLSP(exampleLSP2): got unexpected error: %v", err)
+	}
+	if got := ClassifyLSP(lsp); got != Pseudonode {
+		t.Errorf("ClassifyLSP(exampleLSP2): did not get expected class, got: %v, want: %v", got, Pseudonode)
+	}
+}
+
+func TestOriginatingSystemID(t *testing.T) {
+	tests := []struct {
+		name  string
+		inLSP *oc.Lsp
+		want  string
+	}{{
+		name:  "router LSP",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")},
+		want:  "0000.4000.ce39",
+	}, {
+		name:  "pseudonode LSP",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39.02-01")},
+		want:  "0000.4000.ce39",
+	}, {
+		name:  "invalid LSP ID",
+		inLSP: &oc.Lsp{LspId: ygot.String("0000.4000.ce39")},
+	}, {
+		name:  "missing LSP ID",
+		inLSP: &oc.Lsp{},
+	}, {
+		name: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		if got := OriginatingSystemID(tt.inLSP); got != tt.want {
+			t.Errorf("%s: OriginatingSystemID(%v): did not get expected system ID, got: %s, want: %s", tt.name, tt.inLSP, got, tt.want)
+		}
+	}
+}
+
+func TestRouteIntents(t *testing.T) {
+	lsp, _, err := ISISBytesToLSP(exampleLSP3, 0)
+	if err != nil {
+		t.Fatalf("ISISBytesToLSP(exampleLSP3): got unexpected error: %v", err)
+	}
+
+	got := map[string]RouteIntent{}
+	for _, ri := range RouteIntents(lsp) {
+		got[ri.Prefix] = ri
+	}
+
+	for _, want := range []RouteIntent{
+		{Prefix: "10.244.168.9/32", SystemID: "0000.4000.ce3a", Metric: 0},
+		{Prefix: "192.168.200.48/31", SystemID: "0000.4000.ce3a", Metric: 12010},
+		{Prefix: "192.168.201.32/27", SystemID: "0000.4000.ce3a", Metric: 30},
+		{Prefix: "2001::4860:192:168:200:8/127", SystemID: "0000.4000.ce3a", Metric: 10},
+	} {
+		if g, ok := got[want.Prefix]; !ok || g != want {
+			t.Errorf("RouteIntents(exampleLSP3): did not get expected intent for %s, got: %+v, want: %+v", want.Prefix, g, want)
+		}
+	}
+
+	if got := RouteIntents(nil); got != nil {
+		t.Errorf("RouteIntents(nil): got: %v, want: nil", got)
+	}
+}
+
+func TestTopologyViews(t *testing.T) {
+	type tlv struct {
+		tlvType uint8
+		value   []byte
+	}
+
+	tests := []struct {
+		name   string
+		inTLVs []tlv
+		want   []TopologyView
+	}{{
+		name: "standard and MT reachability with hostname",
+		inTLVs: []tlv{{
+			tlvType: 137,
+			value:   []byte{'r', 't', 'r', '1'},
+		}, {
+			// Multi-Topology TLV, MT IDs 0 and 2.
+			tlvType: 229,
+			value:   []byte{0x00, 0x00, 0x00, 0x02},
+		}, {
+			// Extended IPv4 reachability, 192.0.2.0/24 with metric 10.
+			tlvType: 135,
+			value:   []byte{0x00, 0x00, 0x00, 0x0A, 0x18, 192, 0, 2},
+		}, {
+			// MT IPv4 reachability in MT ID 3, 198.51.100.0/24 with
+			// metric 20.
+			tlvType: 235,
+			value:   []byte{0x00, 0x03, 0x00, 0x00, 0x00, 0x14, 0x18, 198, 51, 100},
+		}},
+		want: []TopologyView{{
+			MTID:     0,
+			SystemID: "0000.4000.ce39",
+			Hostname: "rtr1",
+			Prefixes: []PrefixInfo{{
+				Prefix: "192.0.2.0/24",
+				TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+				Metric: 10,
+			}},
+		}, {
+			MTID:     2,
+			SystemID: "0000.4000.ce39",
+			Hostname: "rtr1",
+		}, {
+			MTID:     3,
+			SystemID: "0000.4000.ce39",
+			Hostname: "rtr1",
+			Prefixes: []PrefixInfo{{
+				Prefix: "198.51.100.0/24",
+				TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY,
+				Metric: 20,
+			}},
+		}},
+	}, {
+		name: "MT prefix with metric exceeding MAX_PATH_METRIC",
+		inTLVs: []tlv{{
+			// MT IPv4 reachability in MT ID 2, 198.51.100.0/24 with
+			// metric 0xFE000001.
+			tlvType: 235,
+			value:   []byte{0x00, 0x02, 0xFE, 0x00, 0x00, 0x01, 0x18, 198, 51, 100},
+		}},
+		want: []TopologyView{{
+			MTID:     2,
+			SystemID: "0000.4000.ce39",
+			Prefixes: []PrefixInfo{{
+				Prefix:   "198.51.100.0/24",
+				TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY,
+				Metric:   0xFE000001,
+				DoNotUse: true,
+			}},
+		}},
+	}, {
+		name: "hostname only",
+		inTLVs: []tlv{{
+			tlvType: 137,
+			value:   []byte{'r', 't', 'r', '1'},
+		}},
+	}}
+
+	for _, tt := range tests {
+		lsp := &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")}
+		for _, r := range tt.inTLVs {
+			if err := ParseTLV(lsp, r.tlvType, r.value); err != nil {
+				t.Fatalf("%s: ParseTLV(%d, %v): got unexpected error: %v", tt.name, r.tlvType, r.value, err)
+			}
+		}
+
+		if diff := pretty.Compare(TopologyViews(lsp), tt.want); diff != "" {
+			t.Errorf("%s: TopologyViews(%v): did not get expected views, diff(-got,+want):\n%s", tt.name, lsp, diff)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// aslaSubTLVType is the type of the application-specific link attributes
+// sub-TLV of the IS reachability TLVs, defined in RFC8919.
+const aslaSubTLVType uint8 = 16
+
+// aslaSRLGTLVType is the type of the application-specific SRLG TLV, defined
+// in RFC8919.
+const aslaSRLGTLVType uint8 = 238
+
+// ApplicationSpecificLinkAttributes is the contents of an application-specific
+// link attributes (ASLA) sub-TLV, defined in RFC8919. It describes a set of
+// link attributes, and the applications that they apply to.
+type ApplicationSpecificLinkAttributes struct {
+	// Legacy indicates that the L-flag is set, such that the attributes
+	// for the applications are advertised using the legacy sub-TLVs of the
+	// IS reachability TLV rather than within the ASLA sub-TLV.
+	Legacy bool
+	// RSVPTE, SRPolicy and LFA indicate whether the corresponding bit is
+	// set in the standard application identifier bit mask.
+	RSVPTE   bool
+	SRPolicy bool
+	LFA      bool
+	// StandardApplicationMask is the standard application identifier bit
+	// mask (SABM).
+	StandardApplicationMask []byte
+	// UserDefinedApplicationMask is the user-defined application identifier
+	// bit mask (UDABM).
+	UserDefinedApplicationMask []byte
+	// Attributes stores the link attributes sub-sub-TLVs that are enclosed
+	// within the ASLA sub-TLV, using the same representation as the
+	// sub-TLVs of an Extended IS Reachability neighbor.
+	Attributes *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance
+}
+
+// ApplicationSpecificSRLG is the contents of an application-specific SRLG
+// TLV (type 238), defined in RFC8919. It describes the shared risk link groups
+// that a link belongs to, and the applications that they apply to.
+type ApplicationSpecificSRLG struct {
+	// Legacy indicates that the L-flag is set, such that the SRLGs for the
+	// applications are advertised using the legacy SRLG TLV (138).
+	Legacy bool
+	// RSVPTE, SRPolicy and LFA indicate whether the corresponding bit is
+	// set in the standard application identifier bit mask.
+	RSVPTE   bool
+	SRPolicy bool
+	LFA      bool
+	// StandardApplicationMask is the standard application identifier bit
+	// mask (SABM).
+	StandardApplicationMask []byte
+	// UserDefinedApplicationMask is the user-defined application identifier
+	// bit mask (UDABM).
+	UserDefinedApplicationMask []byte
+	// NeighborID is the system ID and pseudonode number of the neighbor on
+	// the link, in the format xxxx.yyyy.zzzz.aa.
+	NeighborID string
+	// Flags is the flags field of the TLV.
+	Flags uint8
+	// LinkIdentifiers stores the link identifier sub-TLVs, which identify
+	// the link by its local and remote identifiers or addresses, using the
+	// same representation as the sub-TLVs of an Extended IS Reachability
+	// neighbor.
+	LinkIdentifiers *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance
+	// SRLGs is the list of SRLG values.
+	SRLGs []uint32
+}
+
+// parseApplicationBitMasks parses the application identifier bit masks that
+// are found at the start of b, encoded as:
+//
+//	1 octet - L-flag and SABM length
+//	1 octet - reserved bit and UDABM length
+//	0-8 octets - SABM
+//	0-8 octets - UDABM
+//
+// It returns the value of the L-flag, the SABM and the UDABM. Returns an error
+// if the bit mask lengths are invalid, or overrun b.
+func parseApplicationBitMasks(b []byte) (bool, []byte, []byte, error) {
+	if len(b) < 2 {
+		return false, nil, nil, fmt.Errorf("invalid length: %d", len(b))
+	}
+
+	sabmLen := int(b[0] &^ bit0)
+	udabmLen := int(b[1] &^ bit0)
+	if sabmLen > 8 || udabmLen > 8 {
+		return false, nil, nil, fmt.Errorf("invalid application identifier bit mask length, SABM: %d, UDABM: %d", sabmLen, udabmLen)
+	}
+
+	if 2+sabmLen+udabmLen > len(b) {
+		return false, nil, nil, fmt.Errorf("application identifier bit masks overrun length, SABM: %d, UDABM: %d, length: %d", sabmLen, udabmLen, len(b))
+	}
+
+	return b[0]&bit0 != 0, append([]byte{}, b[2:2+sabmLen]...), append([]byte{}, b[2+sabmLen:2+sabmLen+udabmLen]...), nil
+}
+
+// standardApplications returns whether the RSVP-TE, SR-Policy and LFA bits
+// are set in the standard application identifier bit mask supplied.
+func standardApplications(sabm []byte) (bool, bool, bool) {
+	if len(sabm) == 0 {
+		return false, false, false
+	}
+	return sabm[0]&bit0 != 0, sabm[0]&bit1 != 0, sabm[0]&bit2 != 0
+}
+
+// parseASLASubTLV parses the application-specific link attributes sub-TLV
+// (type 16) of the IS reachability TLVs. The sub-TLV is encoded as:
+//
+//	1 octet - L-flag and SABM length
+//	1 octet - reserved bit and UDABM length
+//	0-8 octets - SABM
+//	0-8 octets - UDABM
+//	Link attribute sub-sub-TLVs
+//
+// The link attributes are parsed according to the receiver's options, and
+// those of unknown type are recorded as per addUnknownSubTLVs. Returns an
+// error if the bit mask lengths overrun the sub-TLV, or the link attributes
+// cannot be parsed.
+func (i *isisLSP) parseASLASubTLV(r *rawTLV) (*ApplicationSpecificLinkAttributes, error) {
+	legacy, sabm, udabm, err := parseApplicationBitMasks(r.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application-specific link attributes sub-TLV: %v", err)
+	}
+
+	a := &ApplicationSpecificLinkAttributes{
+		Legacy:                     legacy,
+		StandardApplicationMask:    sabm,
+		UserDefinedApplicationMask: udabm,
+		Attributes:                 &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+	}
+	a.RSVPTE, a.SRPolicy, a.LFA = standardApplications(sabm)
+
+	subTLVs, err := TLVBytesToTLVs(r.Value[2+len(sabm)+len(udabm):])
+	if err != nil {
+		return nil, fmt.Errorf("invalid link attributes in application-specific link attributes sub-TLV: %v", err)
+	}
+
+	unknown, err := i.decodeExtendedISReachSubTLVs(a.Attributes, subTLVs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link attributes in application-specific link attributes sub-TLV: %v", err)
+	}
+	i.addUnknownSubTLVs(unknown)
+
+	return a, nil
+}
+
+// appendASLASubTLV stores the ASLA sub-TLV r within the undefined sub-TLV of
+// type 16 of the Extended IS Reachability neighbor instance n. RFC8919 allows
+// an ASLA sub-TLV to be advertised for each set of applications, whereas the
+// OpenConfig model stores a single undefined sub-TLV of each type, hence the
+// value of the undefined sub-TLV is the concatenation of each ASLA sub-TLV of
+// the neighbor in its wire format, including its type and length.
+func appendASLASubTLV(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, r *rawTLV) {
+	u := n.GetOrCreateUndefinedSubtlv(aslaSubTLVType)
+	u.Value = append(append(u.Value, r.Type, r.Length), r.Value...)
+	u.Length = ygot.Uint8(uint8(len(u.Value)))
+}
+
+// aslaSubTLVs returns the ASLA sub-TLVs that are stored by appendASLASubTLV
+// within the Extended IS Reachability neighbor instance n, in the order in
+// which they were advertised. Returns an error if the stored sub-TLVs cannot
+// be extracted.
+func aslaSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) ([]*rawTLV, error) {
+	u := n.GetUndefinedSubtlv(aslaSubTLVType)
+	if u == nil {
+		return nil, nil
+	}
+	return TLVBytesToTLVs(u.Value)
+}
+
+// ApplicationSpecificAttributes returns the application-specific link
+// attributes that are advertised for the Extended IS Reachability neighbor
+// instance supplied, with an entry for each ASLA sub-TLV in the order in
+// which they were advertised. Since an ASLA sub-TLV may be advertised for each
+// set of applications, the undefined sub-TLV of type 16 of the instance does
+// not hold the value of a single ASLA sub-TLV, but rather the concatenation of
+// each ASLA sub-TLV in its wire format, including its type and length, from
+// which they are decoded. LSPToISISBytes serialises them as separate sub-TLVs.
+// Returns nil if the instance does not have an ASLA sub-TLV, or an error if
+// it cannot be decoded.
+func ApplicationSpecificAttributes(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) ([]*ApplicationSpecificLinkAttributes, error) {
+	subTLVs, err := aslaSubTLVs(n)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application-specific link attributes sub-TLVs: %v", err)
+	}
+
+	var attrs []*ApplicationSpecificLinkAttributes
+	for _, s := range subTLVs {
+		a, err := (&isisLSP{}).parseASLASubTLV(s)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs, nil
+}
+
+// parseASLASRLGTLV parses the value of the application-specific SRLG TLV
+// (type 238) defined in RFC8919. The TLV is encoded as:
+//
+//	1 octet - L-flag and SABM length
+//	1 octet - reserved bit and UDABM length
+//	0-8 octets - SABM
+//	0-8 octets - UDABM
+//	7 octets - neighbor system ID and pseudonode number
+//	1 octet - flags
+//	1 octet - length of link identifier sub-TLVs
+//	Link identifier sub-TLVs
+//	4 octets per SRLG value
+//
+// Returns an error if the fields overrun the TLV, the link identifiers cannot
+// be parsed, or the SRLG values are not a multiple of 4 octets.
+func parseASLASRLGTLV(b []byte) (*ApplicationSpecificSRLG, error) {
+	legacy, sabm, udabm, err := parseApplicationBitMasks(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid application-specific SRLG TLV: %v", err)
+	}
+
+	x := 2 + len(sabm) + len(udabm)
+	if len(b) < x+9 {
+		return nil, fmt.Errorf("invalid application-specific SRLG TLV, insufficient data for neighbor ID, flags and sub-TLV length, length: %d", len(b))
+	}
+
+	a := &ApplicationSpecificSRLG{
+		Legacy:                     legacy,
+		StandardApplicationMask:    sabm,
+		UserDefinedApplicationMask: udabm,
+		NeighborID:                 canonicalHexString(b[x : x+7]),
+		Flags:                      b[x+7],
+		LinkIdentifiers:            &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+	}
+	a.RSVPTE, a.SRPolicy, a.LFA = standardApplications(sabm)
+
+	subTLVLen := int(b[x+8])
+	x += 9
+	if len(b) < x+subTLVLen {
+		return nil, fmt.Errorf("link identifier sub-TLVs overrun application-specific SRLG TLV, sub-TLV length: %d, remaining: %d", subTLVLen, len(b)-x)
+	}
+
+	subTLVs, err := TLVBytesToTLVs(b[x : x+subTLVLen])
+	if err != nil {
+		return nil, fmt.Errorf("invalid link identifiers in application-specific SRLG TLV: %v", err)
+	}
+	if err := parseExtendedISReachSubTLVs(a.LinkIdentifiers, subTLVs); err != nil {
+		return nil, fmt.Errorf("invalid link identifiers in application-specific SRLG TLV: %v", err)
+	}
+
+	srlgs := b[x+subTLVLen:]
+	if len(srlgs)%4 != 0 {
+		return nil, fmt.Errorf("SRLG values overrun application-specific SRLG TLV, length: %d", len(srlgs))
+	}
+	for j := 0; j < len(srlgs); j += 4 {
+		a.SRLGs = append(a.SRLGs, binary.BigEndian.Uint32(srlgs[j:j+4]))
+	}
+
+	return a, nil
+}
+
+// ApplicationSpecificSRLGs takes an input slice of bytes that contain an IS-IS
+// LSP starting at the LSP ID field, discarding the first offset bytes, and
+// returns the contents of each application-specific SRLG TLV (238) within the
+// LSP, in the order in which they are advertised. Returns an error if the
+// TLVs cannot be extracted, or an application-specific SRLG TLV cannot be
+// decoded.
+func ApplicationSpecificSRLGs(lspBytes []byte, offset int) ([]*ApplicationSpecificSRLG, error) {
+	var srlgs []*ApplicationSpecificSRLG
+	err := ParseTLVStream(lspBytes, offset, func(tlvType uint8, value []byte) error {
+		if tlvType != aslaSRLGTLVType {
+			return nil
+		}
+		a, err := parseASLASRLGTLV(value)
+		if err != nil {
+			return err
+		}
+		srlgs = append(srlgs, a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return srlgs, nil
+}
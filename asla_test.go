@@ -0,0 +1,391 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// srPolicyAdminGroupASLA is an ASLA sub-TLV value that applies to SR-Policy,
+// enclosing an administrative group sub-sub-TLV.
+var srPolicyAdminGroupASLA = []byte{
+	0x01, // L-flag clear, SABM length 1
+	0x00, // UDABM length 0
+	0x40, // SABM: S-bit (SR-Policy)
+	3, 4, // admin group sub-sub-TLV, length 4
+	0, 0, 0, 5, // admin group value
+}
+
+// rsvpTESRLGTLV is an application-specific SRLG TLV value that applies to
+// RSVP-TE, carrying two SRLG values for a numbered link.
+var rsvpTESRLGTLV = []byte{
+	0x01,                                     // L-flag clear, SABM length 1
+	0x00,                                     // UDABM length 0
+	0x80,                                     // SABM: R-bit (RSVP-TE)
+	0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, // neighbor system ID and pseudonode
+	0x00,               // flags
+	12,                 // link identifier sub-TLV length
+	6, 4, 192, 0, 2, 1, // IPv4 interface address
+	8, 4, 192, 0, 2, 2, // IPv4 neighbor address
+	0, 0, 0, 10, // SRLG value
+	0, 0, 1, 44, // SRLG value
+}
+
+// rsvpTESRLG is the result of parsing rsvpTESRLGTLV.
+var rsvpTESRLG = &ApplicationSpecificSRLG{
+	RSVPTE:                     true,
+	StandardApplicationMask:    []byte{0x80},
+	UserDefinedApplicationMask: []byte{},
+	NeighborID:                 "1920.0000.2001.00",
+	LinkIdentifiers: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+		Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+			oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS: {
+				Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS,
+				Ipv4InterfaceAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4InterfaceAddress{
+					Address: []string{"192.0.2.1"},
+				},
+			},
+			oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS: {
+				Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS,
+				Ipv4NeighborAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4NeighborAddress{
+					Address: []string{"192.0.2.2"},
+				},
+			},
+		},
+	},
+	SRLGs: []uint32{10, 300},
+}
+
+func TestParseASLASubTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    *ApplicationSpecificLinkAttributes
+		wantErr bool
+	}{{
+		name: "SR-Policy with admin group",
+		in:   srPolicyAdminGroupASLA,
+		want: &ApplicationSpecificLinkAttributes{
+			SRPolicy:                   true,
+			StandardApplicationMask:    []byte{0x40},
+			UserDefinedApplicationMask: []byte{},
+			Attributes: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+				Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
+						AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
+							AdminGroup: []uint32{5},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "legacy flag with all standard applications and UDABM",
+		in:   []byte{0x81, 0x02, 0xE0, 0xAB, 0xCD},
+		want: &ApplicationSpecificLinkAttributes{
+			Legacy:                     true,
+			RSVPTE:                     true,
+			SRPolicy:                   true,
+			LFA:                        true,
+			StandardApplicationMask:    []byte{0xE0},
+			UserDefinedApplicationMask: []byte{0xAB, 0xCD},
+			Attributes:                 &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+		},
+	}, {
+		name: "zero length SABM",
+		in:   []byte{0x00, 0x00},
+		want: &ApplicationSpecificLinkAttributes{
+			StandardApplicationMask:    []byte{},
+			UserDefinedApplicationMask: []byte{},
+			Attributes:                 &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+		},
+	}, {
+		name:    "too short",
+		in:      []byte{0x01},
+		wantErr: true,
+	}, {
+		name:    "SABM overruns sub-TLV",
+		in:      []byte{0x04, 0x00, 0x40, 0x00},
+		wantErr: true,
+	}, {
+		name:    "UDABM overruns sub-TLV",
+		in:      []byte{0x01, 0x02, 0x40, 0xAB},
+		wantErr: true,
+	}, {
+		name:    "SABM length too long",
+		in:      append([]byte{0x09, 0x00}, make([]byte, 9)...),
+		wantErr: true,
+	}, {
+		name:    "invalid enclosed sub-sub-TLV",
+		in:      []byte{0x01, 0x00, 0x40, 3, 2, 0, 0},
+		wantErr: true,
+	}, {
+		name:    "truncated enclosed sub-sub-TLV",
+		in:      []byte{0x01, 0x00, 0x40, 3, 4, 0, 0},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := (&isisLSP{}).parseASLASubTLV(&rawTLV{Type: aslaSubTLVType, Length: uint8(len(tt.in)), Value: tt.in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseASLASubTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: parseASLASubTLV(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, tt.in, diff)
+		}
+	}
+
+	// Link attributes of unknown type are recorded in the receiver's parse
+	// statistics.
+	stats := &ParseStats{}
+	i := &isisLSP{opts: parseOptions{stats: stats}, tlvType: 22}
+	in := []byte{0x01, 0x00, 0x40, 200, 1, 0x00}
+	if _, err := i.parseASLASubTLV(&rawTLV{Type: aslaSubTLVType, Length: uint8(len(in)), Value: in}); err != nil {
+		t.Fatalf("parseASLASubTLV(%v): got unexpected error: %v", in, err)
+	}
+	if got := stats.UnknownSubTLVs[22]; got != 1 {
+		t.Errorf("parseASLASubTLV(%v): did not get expected unknown sub-TLV count, got: %d, want: 1", in, got)
+	}
+}
+
+func TestParseASLASRLGTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    *ApplicationSpecificSRLG
+		wantErr bool
+	}{{
+		name: "RSVP-TE with two SRLGs",
+		in:   rsvpTESRLGTLV,
+		want: rsvpTESRLG,
+	}, {
+		name: "legacy flag with UDABM, no link identifiers and no SRLGs",
+		in: []byte{
+			0x81, 0x01, 0x20, 0xAB,
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x01,
+			0x01,
+			0,
+		},
+		want: &ApplicationSpecificSRLG{
+			Legacy:                     true,
+			LFA:                        true,
+			StandardApplicationMask:    []byte{0x20},
+			UserDefinedApplicationMask: []byte{0xAB},
+			NeighborID:                 "1920.0000.2001.01",
+			Flags:                      0x01,
+			LinkIdentifiers:            &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+		},
+	}, {
+		name:    "missing bit mask lengths",
+		in:      []byte{0x01},
+		wantErr: true,
+	}, {
+		name:    "SABM overruns TLV",
+		in:      []byte{0x02, 0x00, 0x80},
+		wantErr: true,
+	}, {
+		name:    "truncated neighbor ID",
+		in:      []byte{0x01, 0x00, 0x80, 0x19, 0x20, 0x00, 0x00},
+		wantErr: true,
+	}, {
+		name:    "link identifiers overrun TLV",
+		in:      []byte{0x01, 0x00, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 6, 6, 4, 192, 0},
+		wantErr: true,
+	}, {
+		name:    "invalid link identifier",
+		in:      []byte{0x01, 0x00, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 5, 6, 3, 192, 0, 2},
+		wantErr: true,
+	}, {
+		name:    "truncated SRLG value",
+		in:      []byte{0x01, 0x00, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 0},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := parseASLASRLGTLV(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseASLASRLGTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: parseASLASRLGTLV(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, tt.in, diff)
+		}
+	}
+}
+
+func TestApplicationSpecificAttributes(t *testing.T) {
+	// rsvpTEAdminGroupASLA is an ASLA sub-TLV value that applies to
+	// RSVP-TE, enclosing an administrative group sub-sub-TLV.
+	rsvpTEAdminGroupASLA := []byte{0x01, 0x00, 0x80, 3, 4, 0, 0, 0, 6}
+
+	adminGroup := func(g uint32) *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance {
+		a := &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{}
+		a.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP).GetOrCreateAdminGroup().AdminGroup = []uint32{g}
+		return a
+	}
+
+	tests := []struct {
+		name      string
+		inSubTLVs []*rawTLV
+		want      []*ApplicationSpecificLinkAttributes
+		// wantLength is the expected length of the stored undefined
+		// sub-TLV, if one is expected.
+		wantLength uint8
+		wantErr    bool
+	}{{
+		name: "SR-Policy ASLA",
+		inSubTLVs: []*rawTLV{{
+			Type:   aslaSubTLVType,
+			Length: uint8(len(srPolicyAdminGroupASLA)),
+			Value:  srPolicyAdminGroupASLA,
+		}},
+		want: []*ApplicationSpecificLinkAttributes{{
+			SRPolicy:                   true,
+			StandardApplicationMask:    []byte{0x40},
+			UserDefinedApplicationMask: []byte{},
+			Attributes:                 adminGroup(5),
+		}},
+		wantLength: uint8(2 + len(srPolicyAdminGroupASLA)),
+	}, {
+		name: "SR-Policy and RSVP-TE ASLAs",
+		inSubTLVs: []*rawTLV{{
+			Type:   aslaSubTLVType,
+			Length: uint8(len(srPolicyAdminGroupASLA)),
+			Value:  srPolicyAdminGroupASLA,
+		}, {
+			Type:   aslaSubTLVType,
+			Length: uint8(len(rsvpTEAdminGroupASLA)),
+			Value:  rsvpTEAdminGroupASLA,
+		}},
+		want: []*ApplicationSpecificLinkAttributes{{
+			SRPolicy:                   true,
+			StandardApplicationMask:    []byte{0x40},
+			UserDefinedApplicationMask: []byte{},
+			Attributes:                 adminGroup(5),
+		}, {
+			RSVPTE:                     true,
+			StandardApplicationMask:    []byte{0x80},
+			UserDefinedApplicationMask: []byte{},
+			Attributes:                 adminGroup(6),
+		}},
+		wantLength: uint8(4 + len(srPolicyAdminGroupASLA) + len(rsvpTEAdminGroupASLA)),
+	}, {
+		name: "no ASLA",
+	}, {
+		name: "UDABM overruns sub-TLV",
+		inSubTLVs: []*rawTLV{{
+			Type:   aslaSubTLVType,
+			Length: 3,
+			Value:  []byte{0x00, 0x02, 0xAB},
+		}},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		inst := &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{}
+		err := parseExtendedISReachSubTLVs(inst, tt.inSubTLVs)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseExtendedISReachSubTLVs(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.inSubTLVs, err, tt.wantErr)
+			continue
+		}
+
+		got, err := ApplicationSpecificAttributes(inst)
+		if err != nil {
+			t.Errorf("%s: ApplicationSpecificAttributes(%v): got unexpected error: %v", tt.name, inst, err)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: ApplicationSpecificAttributes(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, inst, diff)
+		}
+
+		if tt.wantLength == 0 {
+			continue
+		}
+		if u := inst.GetUndefinedSubtlv(aslaSubTLVType); u == nil || u.Length == nil || *u.Length != tt.wantLength {
+			t.Errorf("%s: parseExtendedISReachSubTLVs(%v): did not store ASLA sub-TLVs, got: %v, want length: %d", tt.name, tt.inSubTLVs, u, tt.wantLength)
+		}
+	}
+}
+
+func TestApplicationSpecificSRLGs(t *testing.T) {
+	lspHeader := []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03}
+
+	tests := []struct {
+		name    string
+		inTLVs  []byte
+		want    []*ApplicationSpecificSRLG
+		wantErr bool
+	}{{
+		name:   "RSVP-TE SRLGs",
+		inTLVs: append([]byte{aslaSRLGTLVType, uint8(len(rsvpTESRLGTLV))}, rsvpTESRLGTLV...),
+		want:   []*ApplicationSpecificSRLG{rsvpTESRLG},
+	}, {
+		name: "two application-specific SRLG TLVs",
+		inTLVs: appendByteSlice(
+			[]byte{aslaSRLGTLVType, uint8(len(rsvpTESRLGTLV))}, rsvpTESRLGTLV,
+			[]byte{137, 2, 'r', '1'},
+			[]byte{aslaSRLGTLVType, 12, 0x01, 0x00, 0x40, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, 0x00, 0},
+		),
+		want: []*ApplicationSpecificSRLG{rsvpTESRLG, {
+			SRPolicy:                   true,
+			StandardApplicationMask:    []byte{0x40},
+			UserDefinedApplicationMask: []byte{},
+			NeighborID:                 "1920.0000.2002.00",
+			LinkIdentifiers:            &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{},
+		}},
+	}, {
+		name:   "no application-specific SRLGs",
+		inTLVs: []byte{137, 2, 'r', '1'},
+	}, {
+		name:    "SRLG overruns TLV",
+		inTLVs:  []byte{aslaSRLGTLVType, 14, 0x01, 0x00, 0x80, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 10},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		in := append(append([]byte{}, lspHeader...), tt.inTLVs...)
+		got, err := ApplicationSpecificSRLGs(in, 0)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: ApplicationSpecificSRLGs(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, in, err, tt.wantErr)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: ApplicationSpecificSRLGs(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, in, diff)
+		}
+
+		// The TLV cannot be represented in the OpenConfig model, and is
+		// hence stored as an undefined TLV.
+		if tt.want == nil {
+			continue
+		}
+		lsp, ok, err := ISISBytesToLSP(in, 0)
+		if !ok || err != nil {
+			t.Errorf("%s: ISISBytesToLSP(%v): could not parse LSP, ok: %v, err: %v", tt.name, in, ok, err)
+			continue
+		}
+		if lsp.GetUndefinedTlv(aslaSRLGTLVType) == nil {
+			t.Errorf("%s: ISISBytesToLSP(%v): did not store application-specific SRLG TLV as an undefined TLV", tt.name, in)
+		}
+	}
+}
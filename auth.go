@@ -0,0 +1,96 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// authenticationTLVType is the IS-IS TLV type of the authentication TLV.
+const authenticationTLVType uint8 = 10
+
+// Authentication is the contents of the authentication TLV of an LSP.
+type Authentication struct {
+	// Type is the authentication type, e.g., 1 for a cleartext password,
+	// 54 for HMAC-MD5 and 3 for generic cryptographic authentication.
+	Type uint8
+	// ValueLength is the length of the authentication value, such that it
+	// is retained if the caller redacts Value.
+	ValueLength int
+	// Value is the authentication value, which is not interpreted. For a
+	// cleartext password it contains the password, and hence may need to
+	// be redacted by the caller.
+	Value []byte
+}
+
+// LSPAuthentication takes an input slice of bytes that contain an IS-IS LSP
+// starting at the LSP ID field, discarding the first offset bytes, and returns
+// the authentication type and value carried in its authentication TLV, or nil
+// if the LSP does not carry authentication. Since the authentication value may
+// contain a cleartext password, it is not stored in the OpenConfig model by
+// ISISBytesToLSP, and is instead retrieved from the LSP bytes. Returns an
+// error if the TLVs of the LSP cannot be extracted.
+func LSPAuthentication(lspBytes []byte, offset int) (*Authentication, error) {
+	v, err := firstTLVValue(lspBytes, offset, authenticationTLVType)
+	if err != nil || len(v) == 0 {
+		return nil, err
+	}
+	return &Authentication{
+		Type:        v[0],
+		ValueLength: len(v) - 1,
+		Value:       append([]byte{}, v[1:]...),
+	}, nil
+}
+
+// GenericCryptoAuth is the contents of an authentication TLV that uses the
+// generic cryptographic authentication defined in RFC5310.
+type GenericCryptoAuth struct {
+	// KeyID is the key ID used to select the key and algorithm that
+	// generated the digest.
+	KeyID uint16
+	// Digest is the authentication data. Its length depends on the
+	// algorithm selected by the key ID, and hence it is stored as-is.
+	Digest []byte
+}
+
+// parseGenericCryptoAuth parses the authentication value of an authentication
+// TLV with the generic cryptographic authentication type - i.e., the contents
+// of the TLV following the authentication type byte. It is encoded as a 2-byte
+// key ID followed by the digest. Returns an error if the value is too short to
+// contain a key ID.
+func parseGenericCryptoAuth(b []byte) (*GenericCryptoAuth, error) {
+	if len(b) < 2 {
+		return nil, fmt.Errorf("invalid length generic cryptographic authentication value, %d < 2", len(b))
+	}
+
+	return &GenericCryptoAuth{
+		KeyID:  binary.BigEndian.Uint16(b[0:2]),
+		Digest: append([]byte{}, b[2:]...),
+	}, nil
+}
+
+// GenericCryptoAuthentication takes an input slice of bytes that contain an
+// IS-IS LSP starting at the LSP ID field, discarding the first offset bytes,
+// and returns the generic cryptographic authentication (RFC5310) carried in
+// its authentication TLV. Returns nil if the LSP does not carry generic
+// cryptographic authentication, or an error if it cannot be decoded.
+func GenericCryptoAuthentication(lspBytes []byte, offset int) (*GenericCryptoAuth, error) {
+	v, err := firstTLVValue(lspBytes, offset, authenticationTLVType)
+	if err != nil || len(v) == 0 || v[0] != authTypeGenericCrypto {
+		return nil, err
+	}
+	return parseGenericCryptoAuth(v[1:])
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+// authLSP returns an LSP, starting at the LSP ID field, that contains an
+// authentication TLV with the value supplied, or no TLVs if it is nil.
+func authLSP(value []byte) []byte {
+	b := []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03}
+	if value == nil {
+		return b
+	}
+	return append(append(b, authenticationTLVType, uint8(len(value))), value...)
+}
+
+func TestGenericCryptoAuthentication(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   []byte
+		want    *GenericCryptoAuth
+		wantErr bool
+	}{{
+		name: "HMAC-SHA256 digest",
+		inTLV: append([]byte{3, 0x00, 0x2A}, []byte{
+			0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+			16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
+		}...),
+		want: &GenericCryptoAuth{
+			KeyID: 42,
+			Digest: []byte{
+				0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+				16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
+			},
+		},
+	}, {
+		name:  "empty digest",
+		inTLV: []byte{3, 0x01, 0x00},
+		want: &GenericCryptoAuth{
+			KeyID:  256,
+			Digest: []byte{},
+		},
+	}, {
+		name:    "truncated key ID",
+		inTLV:   []byte{3, 0x01},
+		wantErr: true,
+	}, {
+		name:  "other authentication type",
+		inTLV: []byte{42, 0x01, 0x02},
+	}, {
+		name: "no authentication",
+	}}
+
+	for _, tt := range tests {
+		in := authLSP(tt.inTLV)
+		got, err := GenericCryptoAuthentication(in, 0)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: GenericCryptoAuthentication(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, in, err, tt.wantErr)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: GenericCryptoAuthentication(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, in, diff)
+		}
+	}
+}
+
+func TestLSPAuthentication(t *testing.T) {
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		want             *Authentication
+		wantErrSubstring string
+	}{{
+		name:    "cleartext",
+		inBytes: authLSP([]byte{1, 'p', 'a', 's', 's'}),
+		want: &Authentication{
+			Type:        1,
+			ValueLength: 4,
+			Value:       []byte("pass"),
+		},
+	}, {
+		name:    "HMAC-MD5",
+		inBytes: authLSP([]byte{54, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}),
+		want: &Authentication{
+			Type:        54,
+			ValueLength: 16,
+			Value:       []byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		},
+	}, {
+		name:    "unknown type",
+		inBytes: authLSP([]byte{42, 0xFF}),
+		want: &Authentication{
+			Type:        42,
+			ValueLength: 1,
+			Value:       []byte{0xFF},
+		},
+	}, {
+		name:    "zero-length value",
+		inBytes: authLSP([]byte{1}),
+		want: &Authentication{
+			Type:  1,
+			Value: []byte{},
+		},
+	}, {
+		name:    "no authentication",
+		inBytes: authLSP(nil),
+	}, {
+		name:             "truncated LSP",
+		inBytes:          authLSP(nil)[:10],
+		wantErrSubstring: "need at least 15 bytes",
+	}}
+
+	for _, tt := range tests {
+		got, err := LSPAuthentication(tt.inBytes, 0)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: LSPAuthentication(%v): did not get expected error, %s", tt.name, tt.inBytes, diff)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: LSPAuthentication(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, tt.inBytes, diff)
+		}
+	}
+}
+
+func TestAuthenticationNotRendered(t *testing.T) {
+	in := authLSP([]byte{1, 'p', 'a', 's', 's'})
+	lsp, ok, err := ISISBytesToLSP(in, 0)
+	if !ok || err != nil {
+		t.Fatalf("ISISBytesToLSP(%v): could not parse LSP, ok: %v, err: %v", in, ok, err)
+	}
+
+	ns, err := RenderNotifications(lsp, ISISRenderArgs{NetworkInstance: "DEFAULT", ProtocolInstance: "15169", Level: 2})
+	if err != nil {
+		t.Fatalf("RenderNotifications(%v): got unexpected error: %v", lsp, err)
+	}
+	for p, v := range flattenNotifications(t, ns) {
+		if strings.Contains(fmt.Sprint(v), "pass") || strings.Contains(p, "undefined-tlv") {
+			t.Errorf("RenderNotifications(%v): got unexpected leaf containing authentication value, %s: %v", lsp, p, v)
+		}
+	}
+}
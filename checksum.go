@@ -0,0 +1,60 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import "fmt"
+
+// lspChecksumOffset is the offset of the checksum field within the portion of
+// the LSP that is covered by the checksum - i.e., the LSP starting at the LSP
+// ID.
+const lspChecksumOffset = 12
+
+// LSPChecksum computes the checksum of the LSP supplied, which starts at the
+// LSP ID field, as is parsed by ISISBytesToLSP. The contents of the checksum
+// field of the LSP are ignored, such that the value returned can be written
+// into the checksum field of a serialised LSP. Returns an error if the LSP is
+// too short to contain the checksum field.
+func LSPChecksum(lsp []byte) (uint16, error) {
+	if len(lsp) < lspChecksumOffset+2 {
+		return 0, fmt.Errorf("invalid LSP, length %d is too short to contain a checksum", len(lsp))
+	}
+	return fletcherChecksum(lsp, lspChecksumOffset), nil
+}
+
+// fletcherChecksum computes the ISO 8473 Fletcher checksum of b, where the
+// 2-byte checksum field is at the specified offset within b. The contents of
+// the checksum field are treated as zero. The value returned is that which
+// should be written into the checksum field, such that the checksum of b
+// verifies.
+func fletcherChecksum(b []byte, offset int) uint16 {
+	var c0, c1 int
+	for x, v := range b {
+		if x == offset || x == offset+1 {
+			v = 0
+		}
+		c0 = (c0 + int(v)) % 255
+		c1 = (c1 + c0) % 255
+	}
+
+	cx := ((len(b)-offset-1)*c0 - c1) % 255
+	if cx <= 0 {
+		cx += 255
+	}
+	cy := 510 - c0 - cx
+	if cy > 255 {
+		cy -= 255
+	}
+	return uint16(cx)<<8 | uint16(cy)
+}
@@ -0,0 +1,57 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestFletcherChecksum(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+	}{{
+		name: "lab example",
+		in:   exampleLSP1,
+	}, {
+		name: "detailed example",
+		in:   exampleLSP2,
+	}, {
+		name: "larger PDU",
+		in:   exampleLSP3,
+	}}
+
+	for _, tt := range tests {
+		want := binary.BigEndian.Uint16(tt.in[lspChecksumOffset : lspChecksumOffset+2])
+		if got := fletcherChecksum(tt.in, lspChecksumOffset); got != want {
+			t.Errorf("%s: fletcherChecksum(...): got: %#04x, want: %#04x", tt.name, got, want)
+		}
+	}
+}
+
+func TestLSPChecksum(t *testing.T) {
+	got, err := LSPChecksum(exampleLSP1)
+	if err != nil {
+		t.Fatalf("LSPChecksum(exampleLSP1): got unexpected error: %v", err)
+	}
+	if want := uint16(0x277f); got != want {
+		t.Errorf("LSPChecksum(exampleLSP1): got: %#04x, want: %#04x", got, want)
+	}
+
+	if _, err := LSPChecksum(exampleLSP1[:lspChecksumOffset+1]); err == nil {
+		t.Errorf("LSPChecksum(<truncated LSP>): did not get expected error")
+	}
+}
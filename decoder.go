@@ -0,0 +1,76 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// Decoder parses IS-IS LSPs in the same manner as ISISBytesToLSP, reusing its
+// internal storage between calls such that the allocations made for each LSP
+// are reduced when a large number of LSPs are parsed. Each LSP that is returned
+// is independent of the Decoder, and of any other LSP that it has returned. A
+// Decoder is not safe for concurrent use; a Decoder should be created for each
+// goroutine that parses LSPs.
+type Decoder struct {
+	// opts is the set of options that control how each LSP is parsed.
+	opts parseOptions
+	// i is the parser state, which is reset for each LSP.
+	i isisLSP
+	// scratch is the storage for the TLVs of the LSP being parsed.
+	scratch tlvScratch
+	// isReachInstances is the map of extended IS reachability instance
+	// IDs used by the parser, which is cleared for each LSP.
+	isReachInstances map[string]uint64
+}
+
+// NewDecoder returns a Decoder which parses LSPs using the ParseOptions
+// supplied.
+func NewDecoder(opts ...ParseOption) *Decoder {
+	d := &Decoder{}
+	for _, o := range opts {
+		o(&d.opts)
+	}
+	return d
+}
+
+// Decode parses the IS-IS LSP contained in lspBytes, discarding the first
+// offset bytes, and returns the OpenConfig model of the LSP. The return values
+// are as described by ISISBytesToLSP.
+func (d *Decoder) Decode(lspBytes []byte, offset int) (*oc.Lsp, bool, error) {
+	for k := range d.isReachInstances {
+		delete(d.isReachInstances, k)
+	}
+
+	d.i = isisLSP{
+		LSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{},
+		},
+		opts:             d.opts,
+		isReachInstances: d.isReachInstances,
+	}
+	lsp, ok, err := d.i.parse(lspBytes, offset, &d.scratch)
+
+	// Retain the map of instance IDs if it was created by the parser, and
+	// release the references to the LSP that has been parsed, such that it
+	// is not kept alive by the Decoder.
+	d.isReachInstances = d.i.isReachInstances
+	for x := range d.scratch.raw {
+		d.scratch.raw[x] = rawTLV{}
+	}
+	d.i = isisLSP{}
+
+	return lsp, ok, err
+}
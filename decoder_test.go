@@ -0,0 +1,85 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name             string
+		inOpts           []ParseOption
+		inBytes          [][]byte
+		wantErrSubstring string
+	}{{
+		name:    "example LSPs",
+		inBytes: [][]byte{exampleLSP1, exampleLSP2, exampleLSP3, exampleLSP1, exampleLSP3},
+	}, {
+		name:    "example LSPs with options",
+		inOpts:  []ParseOption{WithChecksumCheck(true), WithExpandIPv6(true)},
+		inBytes: [][]byte{exampleLSP3, exampleLSP1},
+	}, {
+		name:             "short LSP",
+		inBytes:          [][]byte{{0x0, 0x0}},
+		wantErrSubstring: "need at least 15 bytes",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(tt.inOpts...)
+
+			var got []*oc.Lsp
+			for _, in := range tt.inBytes {
+				lsp, _, err := d.Decode(in, 0)
+				if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+					t.Fatalf("d.Decode(%v): did not get expected error, %s", in, diff)
+				}
+				got = append(got, lsp)
+			}
+
+			// Compare each LSP once all have been decoded, such that
+			// an LSP that is modified by the decoding of a subsequent
+			// LSP is detected.
+			for x, in := range tt.inBytes {
+				want, _, _ := ISISBytesToLSP(in, 0, tt.inOpts...)
+				if diff := pretty.Compare(got[x], want); diff != "" {
+					t.Errorf("d.Decode(%v): did not get expected LSP at index %d, diff(-got,+want):\n%s", in, x, diff)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecoder(b *testing.B) {
+	b.Run("ISISBytesToLSP", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			ISISBytesToLSP(exampleLSP3, 0)
+		}
+	})
+
+	b.Run("Decoder", func(b *testing.B) {
+		d := NewDecoder()
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			d.Decode(exampleLSP3, 0)
+		}
+	})
+}
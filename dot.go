@@ -0,0 +1,87 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// DOTOption is an option that modifies the output of RenderDOT.
+type DOTOption func(*dotOptions)
+
+// dotOptions stores the options that modify the behaviour of RenderDOT.
+type dotOptions struct {
+	// prefixes specifies that the prefixes advertised in the LSP are
+	// included as leaf nodes.
+	prefixes bool
+}
+
+// WithDOTPrefixes specifies whether the prefixes advertised in the IP
+// reachability TLVs of the LSP are included in the output of RenderDOT, as
+// leaf nodes connected to the originating IS by an edge labelled with the
+// metric of the prefix.
+func WithDOTPrefixes(include bool) DOTOption {
+	return func(o *dotOptions) {
+		o.prefixes = include
+	}
+}
+
+// RenderDOT returns a Graphviz DOT fragment describing the LSP, consisting of
+// a node for the originating IS, labelled with its hostname where it is
+// advertised, and an edge to each neighbor in the IS reachability TLVs,
+// labelled with the metric of the adjacency. Nodes are identified by system ID
+// and pseudonode ID (xxxx.yyyy.zzzz.nn), such that the fragments generated for
+// each LSP in a database can be concatenated within a digraph statement to
+// describe the topology. Returns an error if the LSP does not have a valid LSP
+// ID.
+func RenderDOT(lsp *oc.Lsp, opts ...DOTOption) (string, error) {
+	var o dotOptions
+	for _, f := range opts {
+		f(&o)
+	}
+
+	if lsp == nil || lsp.LspId == nil {
+		return "", fmt.Errorf("cannot render LSP without LSP ID")
+	}
+	id, err := ParseLSPID(*lsp.LspId)
+	if err != nil {
+		return "", fmt.Errorf("cannot render LSP, %v", err)
+	}
+	node := strconv.Quote(fmt.Sprintf("%s.%02x", id.SystemID, id.PseudonodeID))
+
+	label := id.SystemID
+	if h := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetHostname(); h != nil && len(h.Hostname) != 0 {
+		label = h.Hostname[0] + "\n" + id.SystemID
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s [label=%s];\n", node, strconv.Quote(label))
+	for _, n := range ISReachability(lsp) {
+		fmt.Fprintf(&b, "%s -> %s [label=\"%d\"];\n", node, strconv.Quote(n.SystemID), n.Metric)
+	}
+
+	if o.prefixes {
+		for _, p := range Prefixes(lsp) {
+			pfx := strconv.Quote(p.Prefix)
+			fmt.Fprintf(&b, "%s [shape=box];\n", pfx)
+			fmt.Fprintf(&b, "%s -> %s [label=\"%d\", style=dashed];\n", node, pfx, p.Metric)
+		}
+	}
+	return b.String(), nil
+}
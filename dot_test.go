@@ -0,0 +1,95 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestRenderDOT(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+	lsp3, ok, err := ISISBytesToLSP(exampleLSP3, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP3): could not parse LSP: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		inOpts           []DOTOption
+		want             string
+		wantErrSubstring string
+	}{{
+		name:  "example LSP",
+		inLSP: lsp,
+		want: `"0000.4000.ce39.00" [label="re0-pr05.sql88\n0000.4000.ce39"];
+"0000.4000.ce39.00" -> "0000.4000.ce39.02" [label="30"];
+`,
+	}, {
+		name:   "example LSP with prefixes",
+		inLSP:  lsp,
+		inOpts: []DOTOption{WithDOTPrefixes(true)},
+		want: `"0000.4000.ce39.00" [label="re0-pr05.sql88\n0000.4000.ce39"];
+"0000.4000.ce39.00" -> "0000.4000.ce39.02" [label="30"];
+"10.244.168.31/32" [shape=box];
+"0000.4000.ce39.00" -> "10.244.168.31/32" [label="0", style=dashed];
+"192.168.201.32/27" [shape=box];
+"0000.4000.ce39.00" -> "192.168.201.32/27" [label="30", style=dashed];
+"2001:4860:c0a8:c920::/64" [shape=box];
+"0000.4000.ce39.00" -> "2001:4860:c0a8:c920::/64" [label="30", style=dashed];
+"2607:f8b0::3:4000:ce39/128" [shape=box];
+"0000.4000.ce39.00" -> "2607:f8b0::3:4000:ce39/128" [label="0", style=dashed];
+`,
+	}, {
+		name:  "example LSP with multiple neighbors",
+		inLSP: lsp3,
+		want: `"0000.4000.ce3a.00" [label="re0-bb07.sql88\n0000.4000.ce3a"];
+"0000.4000.ce3a.00" -> "0000.4000.ce39.02" [label="30"];
+"0000.4000.ce3a.00" -> "0000.4000.ce3c.00" [label="10"];
+"0000.4000.ce3a.00" -> "0000.4000.d5b8.00" [label="12010"];
+"0000.4000.ce3a.00" -> "0000.4000.d5be.00" [label="10"];
+`,
+	}, {
+		name:  "pseudonode without hostname",
+		inLSP: &oc.Lsp{LspId: ygot.String("1920.0000.2001.01-00")},
+		want: `"1920.0000.2001.01" [label="1920.0000.2001"];
+`,
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "without LSP ID",
+	}, {
+		name:             "invalid LSP ID",
+		inLSP:            &oc.Lsp{LspId: ygot.String("1920.0000.2001.01")},
+		wantErrSubstring: "cannot render LSP",
+	}}
+
+	for _, tt := range tests {
+		got, err := RenderDOT(tt.inLSP, tt.inOpts...)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: RenderDOT(...): did not get expected error, %s", tt.name, diff)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: RenderDOT(...): did not get expected output, got:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+}
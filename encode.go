@@ -0,0 +1,1109 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+const (
+	// tlvHeaderLen is the length of the type and length fields of a TLV
+	// or sub-TLV.
+	tlvHeaderLen int = 2
+	// maxTLVValueLen is the maximum length of the value of a TLV or
+	// sub-TLV, since the length is encoded in a single octet.
+	maxTLVValueLen int = 255
+)
+
+// ValidateEncodable checks whether the TLVs of the LSP supplied can be
+// serialised to their IS-IS wire format by LSPToISISBytes. It reports each TLV
+// or sub-TLV that cannot be serialised, including those whose encoding is not
+// supported, and entries that cannot be described by a single-octet length.
+// TLVs whose contents exceed 255 bytes are split by LSPToISISBytes, and hence
+// are not reported. Returns nil if no problems are found.
+func ValidateEncodable(lsp *oc.Lsp) error {
+	if lsp == nil {
+		return fmt.Errorf("nil LSP")
+	}
+	_, err := encodeTLVs(lsp)
+	return err
+}
+
+// adjSIDValueLen returns the length of the SID value of an adjacency SID
+// sub-TLV, which is a 3-byte label when the value and local flags are set,
+// and a 4-byte index otherwise.
+func adjSIDValueLen(isValue, isLocal bool) int {
+	if isValue && isLocal {
+		return 3
+	}
+	return 4
+}
+
+// maxWideMetric is the maximum value of the 3-byte default metric of the
+// extended IS reachability TLV.
+const maxWideMetric uint32 = 0xFFFFFF
+
+// encodedSubTLV is a sub-TLV that has been serialised to its wire format.
+type encodedSubTLV struct {
+	subTLVType uint8
+	value      []byte
+}
+
+// appendSubTLVs appends the sub-TLVs in subs to b, in order, and returns the
+// resulting slice.
+func appendSubTLVs(b []byte, subs []encodedSubTLV) []byte {
+	for _, s := range subs {
+		b = append(b, s.subTLVType, uint8(len(s.value)))
+		b = append(b, s.value...)
+	}
+	return b
+}
+
+// ipv4AddressBytes returns the 4-byte representation of the IPv4 address a.
+func ipv4AddressBytes(a string) ([]byte, error) {
+	ip := net.ParseIP(a).To4()
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IPv4 address %s", a)
+	}
+	return ip, nil
+}
+
+// ipv6AddressBytes returns the 16-byte representation of the IPv6 address a.
+func ipv6AddressBytes(a string) ([]byte, error) {
+	ip := net.ParseIP(a)
+	if ip == nil || ip.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address %s", a)
+	}
+	return ip.To16(), nil
+}
+
+// appendUint24 appends v to b as a 3-byte value, as used for metrics, delays
+// and loss within sub-TLVs. Returns an error if v does not fit into 3 bytes.
+func appendUint24(b []byte, v uint32) ([]byte, error) {
+	if v > maxWideMetric {
+		return nil, fmt.Errorf("value %d exceeds maximum of %d", v, maxWideMetric)
+	}
+	return append(b, uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+// anomalousFlagByte returns the flag byte of the RFC8570 delay and loss
+// sub-TLVs, in which the anomalous (A) flag is the most significant bit.
+func anomalousFlagByte(a *bool) uint8 {
+	if boolValue(a) {
+		return bit0
+	}
+	return 0
+}
+
+// adjSIDFlagByte returns the flag byte of an adjacency SID sub-TLV containing
+// the flags supplied, such that it is the inverse of adjSIDFlags.
+func adjSIDFlagByte(flags []oc.E_OpenconfigIsis_AdjacencySid_Flags) (b uint8, isValue, isLocal bool) {
+	for _, f := range flags {
+		switch f {
+		case oc.OpenconfigIsis_AdjacencySid_Flags_ADDRESS_FAMILY:
+			b |= bit0
+		case oc.OpenconfigIsis_AdjacencySid_Flags_BACKUP:
+			b |= bit1
+		case oc.OpenconfigIsis_AdjacencySid_Flags_VALUE:
+			b |= bit2
+			isValue = true
+		case oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL:
+			b |= bit3
+			isLocal = true
+		case oc.OpenconfigIsis_AdjacencySid_Flags_SET:
+			b |= bit4
+		}
+	}
+	return b, isValue, isLocal
+}
+
+// lanAdjSIDFlagByte returns the flag byte of a LAN adjacency SID sub-TLV
+// containing the flags supplied, such that it is the inverse of
+// lanAdjSIDFlags.
+func lanAdjSIDFlagByte(flags []oc.E_OpenconfigIsis_LanAdjacencySid_Flags) (b uint8, isValue, isLocal bool) {
+	for _, f := range flags {
+		switch f {
+		case oc.OpenconfigIsis_LanAdjacencySid_Flags_ADDRESS_FAMILY:
+			b |= bit0
+		case oc.OpenconfigIsis_LanAdjacencySid_Flags_BACKUP:
+			b |= bit1
+		case oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE:
+			b |= bit2
+			isValue = true
+		case oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL:
+			b |= bit3
+			isLocal = true
+		case oc.OpenconfigIsis_LanAdjacencySid_Flags_SET:
+			b |= bit4
+		}
+	}
+	return b, isValue, isLocal
+}
+
+// appendAdjSIDValue appends the SID value v of an adjacency SID to b, as a
+// 3-byte label when the value and local flags are set, and a 4-byte index
+// otherwise.
+func appendAdjSIDValue(b []byte, v uint32, isValue, isLocal bool) []byte {
+	if adjSIDValueLen(isValue, isLocal) == 3 {
+		return append(b, uint8(v>>16), uint8(v>>8), uint8(v))
+	}
+	return append(b, uint8(v>>24), uint8(v>>16), uint8(v>>8), uint8(v))
+}
+
+// extendedISReachSubTLVs serialises the sub-TLVs of the neighbor instance
+// inst of the extended IS reachability TLV. Bandwidths are stored as the raw
+// bytes of a float32, and are hence emitted verbatim. Returns an error if a
+// sub-TLV cannot be serialised.
+func extendedISReachSubTLVs(inst *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) ([]encodedSubTLV, error) {
+	var pErr errlist.List
+	var subs []encodedSubTLV
+	for st, s := range inst.Subtlv {
+		switch st {
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP:
+			for _, g := range s.GetAdminGroup().AdminGroup {
+				v := make([]byte, 4)
+				binary.BigEndian.PutUint32(v, g)
+				subs = append(subs, encodedSubTLV{3, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_ID:
+			v := make([]byte, 8)
+			binary.BigEndian.PutUint32(v[0:4], uint32Value(s.GetLinkId().Local))
+			binary.BigEndian.PutUint32(v[4:8], uint32Value(s.GetLinkId().Remote))
+			subs = append(subs, encodedSubTLV{4, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS:
+			for _, a := range s.GetIpv4InterfaceAddress().Address {
+				v, err := ipv4AddressBytes(a)
+				if err != nil {
+					pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+					continue
+				}
+				subs = append(subs, encodedSubTLV{6, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS:
+			for _, a := range s.GetIpv4NeighborAddress().Address {
+				v, err := ipv4AddressBytes(a)
+				if err != nil {
+					pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+					continue
+				}
+				subs = append(subs, encodedSubTLV{8, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH:
+			subs = append(subs, encodedSubTLV{9, s.GetMaxLinkBandwidth().Bandwidth})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH:
+			subs = append(subs, encodedSubTLV{10, s.GetMaxReservableLinkBandwidth().Bandwidth})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH:
+			subs = append(subs, encodedSubTLV{38, s.GetResidualBandwidth().Bandwidth})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_UNRESERVED_BANDWIDTH:
+			v := make([]byte, 0, 32)
+			for pri := uint8(0); pri < 8; pri++ {
+				p := s.SetupPriority[pri]
+				if p == nil || len(p.Bandwidth) != 4 {
+					pErr.Add(fmt.Errorf("sub-TLV %v: missing bandwidth at priority level %d", st, pri))
+					v = nil
+					break
+				}
+				v = append(v, p.Bandwidth...)
+			}
+			if v != nil {
+				subs = append(subs, encodedSubTLV{11, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID:
+			for _, a := range s.AdjacencySid {
+				fb, isValue, isLocal := adjSIDFlagByte(a.Flags)
+				v := []byte{fb, uint8Value(a.Weight)}
+				subs = append(subs, encodedSubTLV{31, appendAdjSIDValue(v, uint32Value(a.Value), isValue, isLocal)})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_LAN_SID:
+			for _, a := range s.LanAdjacencySid {
+				fb, isValue, isLocal := lanAdjSIDFlagByte(a.Flags)
+				nbr, err := canonicalHexToBytes(stringValue(a.NeighborId), defaultIDLength)
+				if err != nil {
+					pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+					continue
+				}
+				v := append([]byte{fb, uint8Value(a.Weight)}, nbr...)
+				subs = append(subs, encodedSubTLV{32, appendAdjSIDValue(v, uint32Value(a.Value), isValue, isLocal)})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS:
+			for _, a := range s.GetIpv6InterfaceAddress().Address {
+				v, err := ipv6AddressBytes(a)
+				if err != nil {
+					pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+					continue
+				}
+				subs = append(subs, encodedSubTLV{12, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS:
+			for _, a := range s.GetIpv6NeighborAddress().Address {
+				v, err := ipv6AddressBytes(a)
+				if err != nil {
+					pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+					continue
+				}
+				subs = append(subs, encodedSubTLV{13, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP:
+			words := s.GetExtendedAdminGroup().ExtendedAdminGroup
+			v := make([]byte, 4*len(words))
+			for x, w := range words {
+				binary.BigEndian.PutUint32(v[4*x:], w)
+			}
+			subs = append(subs, encodedSubTLV{14, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_TE_DEFAULT_METRIC:
+			v, err := appendUint24(nil, uint32Value(s.GetTeDefaultMetric().Metric))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{18, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE:
+			var fb uint8
+			for _, t := range s.GetLinkProtectionType().Type {
+				for _, p := range linkProtectionTypeBits {
+					if p.typ == t {
+						fb |= p.bit
+					}
+				}
+			}
+			subs = append(subs, encodedSubTLV{20, []byte{fb, 0}})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY:
+			d := s.GetLinkDelay()
+			if d == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing link delay", st))
+				continue
+			}
+			v, err := appendUint24([]byte{anomalousFlagByte(d.ABit)}, uint32Value(d.Delay))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{33, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY:
+			d := s.GetMinMaxLinkDelay()
+			if d == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing min/max link delay", st))
+				continue
+			}
+			v, err := appendUint24([]byte{anomalousFlagByte(d.ABit)}, uint32Value(d.MinDelay))
+			if err == nil {
+				v, err = appendUint24(v, uint32Value(d.MaxDelay))
+			}
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{34, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY_VARIATION:
+			v, err := appendUint24([]byte{0}, uint32Value(s.GetLinkDelayVariation().Delay))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{35, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS:
+			l := s.GetLinkLoss()
+			if l == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing link loss", st))
+				continue
+			}
+			v, err := appendUint24([]byte{anomalousFlagByte(l.ABit)}, uint32Value(l.LinkLoss))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{36, v})
+		default:
+			pErr.Add(fmt.Errorf("sub-TLV %v: encoding is not supported", st))
+		}
+	}
+	for st, u := range inst.UndefinedSubtlv {
+		if st == aslaSubTLVType {
+			continue
+		}
+		subs = append(subs, encodedSubTLV{st, u.Value})
+	}
+	aslas, err := aslaSubTLVs(inst)
+	if err != nil {
+		pErr.Add(fmt.Errorf("application-specific link attributes: %v", err))
+	}
+	for _, a := range aslas {
+		subs = append(subs, encodedSubTLV{a.Type, a.Value})
+	}
+
+	// The adjacency SIDs are keyed by their value, so sort the sub-TLVs
+	// such that the output is deterministic.
+	sortSubTLVs(subs)
+	return subs, pErr.Err()
+}
+
+// sortSubTLVs sorts subs by type, and then by value, such that sub-TLVs that
+// are generated from maps are serialised deterministically.
+func sortSubTLVs(subs []encodedSubTLV) {
+	sort.Slice(subs, func(i, j int) bool {
+		if subs[i].subTLVType != subs[j].subTLVType {
+			return subs[i].subTLVType < subs[j].subTLVType
+		}
+		return bytes.Compare(subs[i].value, subs[j].value) < 0
+	})
+}
+
+// encodeExtendedISReachabilityTLV serialises the extended IS reachability TLV
+// (type 22) to its IS-IS wire format, returning the values of the TLVs. Each
+// neighbor instance is encoded as a 7-byte neighbor ID, a 3-byte default
+// metric, a sub-TLV length and the sub-TLVs, with neighbors ordered by ID and
+// instances by their index. Since the value of a TLV is limited to 255 bytes,
+// the neighbor instances are split across as many TLVs as are required.
+// Returns an error if the TLV cannot be serialised, including where a metric
+// does not fit into 3 bytes.
+func encodeExtendedISReachabilityTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	r := t.GetExtendedIsReachability()
+	if r == nil {
+		return nil, fmt.Errorf("TLV does not contain extended IS reachability")
+	}
+
+	var nids []string
+	for nid := range r.Neighbor {
+		nids = append(nids, nid)
+	}
+	sort.Strings(nids)
+
+	var pErr errlist.List
+	var vals [][]byte
+	var b []byte
+	for _, nid := range nids {
+		id, err := canonicalHexToBytes(nid, defaultIDLength+1)
+		if err != nil {
+			pErr.Add(fmt.Errorf("neighbor %s: %v", nid, err))
+			continue
+		}
+
+		n := r.Neighbor[nid]
+		var insts []uint64
+		for x := range n.Instance {
+			insts = append(insts, x)
+		}
+		sort.Slice(insts, func(i, j int) bool { return insts[i] < insts[j] })
+
+		for _, x := range insts {
+			inst := n.Instance[x]
+			m := uint32Value(inst.Metric)
+			if m > maxWideMetric {
+				pErr.Add(fmt.Errorf("neighbor %s instance %d: metric %d exceeds maximum of %d", nid, x, m, maxWideMetric))
+				continue
+			}
+
+			subs, err := extendedISReachSubTLVs(inst)
+			if err != nil {
+				pErr.Add(fmt.Errorf("neighbor %s instance %d: %v", nid, x, err))
+				continue
+			}
+			sb := appendSubTLVs(nil, subs)
+			if l := len(id) + 4 + len(sb); l > maxTLVValueLen {
+				pErr.Add(fmt.Errorf("neighbor %s instance %d: serialised length %d exceeds %d bytes", nid, x, l, maxTLVValueLen))
+				continue
+			}
+
+			if len(b)+len(id)+4+len(sb) > maxTLVValueLen {
+				vals = append(vals, b)
+				b = nil
+			}
+			b = append(b, id...)
+			b = append(b, uint8(m>>16), uint8(m>>8), uint8(m), uint8(len(sb)))
+			b = append(b, sb...)
+		}
+	}
+	if b != nil {
+		vals = append(vals, b)
+	}
+	return vals, pErr.Err()
+}
+
+// tlvEncoder describes how a TLV in the OpenConfig model is serialised to its
+// IS-IS wire format.
+type tlvEncoder struct {
+	// tlvType is the type of the TLV on the wire.
+	tlvType uint8
+	// encode returns the values of the TLVs that the TLV is serialised to,
+	// each of which is at most 255 bytes.
+	encode func(*oc.Lsp_Tlv) ([][]byte, error)
+}
+
+// tlvEncoders is the set of TLVs that are serialised by LSPToISISBytes.
+var tlvEncoders = map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]tlvEncoder{
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES:             {1, encodeAreaAddressTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY:   {22, encodeExtendedISReachabilityTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID:                      {129, encodeNLPIDTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_INTERFACE_ADDRESSES:   {132, encodeIPv4InterfaceAddressesTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID:          {134, encodeIPv4TERouterIDTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {135, encodeExtendedIPReachTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME:               {137, encodeDynamicNameTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID:          {140, encodeIPv6TERouterIDTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES:   {232, encodeIPv6InterfaceAddressesTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY:          {236, encodeIPv6ReachabilityTLV},
+	oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY:          {242, encodeCapabilityTLV},
+}
+
+// LSPToISISBytes serialises the LSP supplied to the format that is parsed by
+// ISISBytesToLSP, starting at the LSP ID field, such that it is the inverse of
+// ISISBytesToLSP for the contents that are stored within the LSP. Since a
+// single undefined TLV of each type is stored, where an LSP contained repeated
+// TLVs of a type that is not parsed, only the first instance is serialised;
+// UndefinedTLVs can be used to retrieve every instance. The LSP ID, sequence number, checksum and flags are followed
+// by the TLVs that are in tlvEncoders, and any undefined TLVs, ordered by TLV
+// type. The checksum is copied from the LSP rather than being calculated, and
+// is hence a placeholder that can be replaced with the value returned by
+// LSPChecksum. Where the contents of a TLV exceed the 255 bytes that can be
+// described by its length, they are split across multiple TLVs. Returns an
+// error if the LSP contains a TLV that cannot be serialised.
+func LSPToISISBytes(lsp *oc.Lsp) ([]byte, error) {
+	if lsp == nil {
+		return nil, fmt.Errorf("nil LSP")
+	}
+
+	id, err := lspIDToBytes(stringValue(lsp.LspId))
+	if err != nil {
+		return nil, err
+	}
+
+	flags := uint8Value(lsp.IsType) & (bit6 | bit7)
+	for _, f := range lsp.Flags {
+		for _, fb := range lspFlagBits {
+			if fb.flag == f {
+				flags |= fb.bit
+			}
+		}
+	}
+
+	b := make([]byte, 0, maxTLVValueLen)
+	b = append(b, id...)
+	b = append(b, 0, 0, 0, 0, 0, 0, flags)
+	binary.BigEndian.PutUint32(b[8:12], uint32Value(lsp.SequenceNumber))
+	if lsp.Checksum != nil {
+		binary.BigEndian.PutUint16(b[lspChecksumOffset:lspChecksumOffset+2], *lsp.Checksum)
+	}
+
+	raw, err := encodeTLVs(lsp)
+	if err != nil {
+		return nil, err
+	}
+
+	tb, err := TLVsToTLVBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, tb...), nil
+}
+
+// encodeTLVs serialises the TLVs and undefined TLVs of the LSP supplied,
+// returning them ordered by TLV type. Returns an error describing each TLV
+// that cannot be serialised.
+func encodeTLVs(lsp *oc.Lsp) ([]*rawTLV, error) {
+	tlvs := map[uint8][][]byte{}
+	var pErr errlist.List
+	for t, tlv := range lsp.Tlv {
+		e, ok := tlvEncoders[t]
+		if !ok {
+			pErr.Add(fmt.Errorf("TLV %v: encoding is not supported", t))
+			continue
+		}
+		vals, err := e.encode(tlv)
+		if err != nil {
+			pErr.Add(fmt.Errorf("TLV %v: %v", t, err))
+			continue
+		}
+		tlvs[e.tlvType] = append(tlvs[e.tlvType], vals...)
+	}
+	for t, u := range lsp.UndefinedTlv {
+		if l := len(u.Value); l > maxTLVValueLen {
+			pErr.Add(fmt.Errorf("undefined TLV %d: serialised value length %d exceeds %d bytes", t, l, maxTLVValueLen))
+			continue
+		}
+		tlvs[t] = append(tlvs[t], u.Value)
+	}
+	if err := pErr.Err(); err != nil {
+		return nil, err
+	}
+
+	var types []int
+	for t := range tlvs {
+		types = append(types, int(t))
+	}
+	sort.Ints(types)
+	var raw []*rawTLV
+	for _, t := range types {
+		for _, v := range tlvs[uint8(t)] {
+			raw = append(raw, &rawTLV{Type: uint8(t), Value: v})
+		}
+	}
+	return raw, nil
+}
+
+// packTLVValues concatenates the serialised entries supplied, in order, into
+// as few TLV values as possible, starting a new value where appending an entry
+// would exceed 255 bytes. Returns an error if a single entry exceeds 255 bytes.
+func packTLVValues(entries [][]byte) ([][]byte, error) {
+	var vals [][]byte
+	var b []byte
+	for _, e := range entries {
+		if len(e) > maxTLVValueLen {
+			return nil, fmt.Errorf("entry serialised length %d exceeds %d bytes", len(e), maxTLVValueLen)
+		}
+		if len(b)+len(e) > maxTLVValueLen {
+			vals = append(vals, b)
+			b = nil
+		}
+		b = append(b, e...)
+	}
+	if b != nil {
+		vals = append(vals, b)
+	}
+	return vals, nil
+}
+
+// encodeAreaAddressTLV serialises the area addresses TLV (type 1). Each
+// address is encoded as a 1-byte length followed by the address.
+func encodeAreaAddressTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var entries [][]byte
+	for _, a := range t.GetAreaAddress().Address {
+		b, err := hex.DecodeString(strings.Replace(a, ".", "", -1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid area address %s: %v", a, err)
+		}
+		entries = append(entries, append([]byte{uint8(len(b))}, b...))
+	}
+	return packTLVValues(entries)
+}
+
+// encodeNLPIDTLV serialises the NLPID TLV (type 129), which has a single byte
+// per protocol.
+func encodeNLPIDTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var entries [][]byte
+	for _, n := range t.GetNlpid().Nlpid {
+		switch n {
+		case oc.OpenconfigIsis_Nlpid_Nlpid_IPV4:
+			entries = append(entries, []byte{0xCC})
+		case oc.OpenconfigIsis_Nlpid_Nlpid_IPV6:
+			entries = append(entries, []byte{0x8E})
+		default:
+			return nil, fmt.Errorf("unknown NLPID %v", n)
+		}
+	}
+	return packTLVValues(entries)
+}
+
+// encodeDynamicNameTLV serialises the dynamic name TLV (type 137), emitting
+// one TLV per hostname.
+func encodeDynamicNameTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var vals [][]byte
+	for _, h := range t.GetHostname().Hostname {
+		if len(h) > maxTLVValueLen {
+			return nil, fmt.Errorf("hostname %q length %d exceeds %d bytes", h, len(h), maxTLVValueLen)
+		}
+		vals = append(vals, []byte(h))
+	}
+	return vals, nil
+}
+
+// encodeIPv4InterfaceAddressesTLV serialises the IPv4 interface addresses
+// TLV (type 132), which has 4 bytes per address.
+func encodeIPv4InterfaceAddressesTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var entries [][]byte
+	for _, a := range t.GetIpv4InterfaceAddresses().Address {
+		b, err := ipv4AddressBytes(a)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, b)
+	}
+	return packTLVValues(entries)
+}
+
+// encodeIPv6InterfaceAddressesTLV serialises the IPv6 interface addresses
+// TLV (type 232), which has 16 bytes per address.
+func encodeIPv6InterfaceAddressesTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var entries [][]byte
+	for _, a := range t.GetIpv6InterfaceAddresses().Address {
+		b, err := ipv6AddressBytes(a)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, b)
+	}
+	return packTLVValues(entries)
+}
+
+// encodeIPv4TERouterIDTLV serialises the IPv4 TE router ID TLV (type 134),
+// emitting one TLV per router ID.
+func encodeIPv4TERouterIDTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var vals [][]byte
+	for _, id := range t.GetIpv4TeRouterId().RouterId {
+		b, err := ipv4AddressBytes(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, b)
+	}
+	return vals, nil
+}
+
+// encodeIPv6TERouterIDTLV serialises the IPv6 TE router ID TLV (type 140),
+// emitting one TLV per router ID.
+func encodeIPv6TERouterIDTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var vals [][]byte
+	for _, id := range t.GetIpv6TeRouterId().RouterId {
+		b, err := ipv6AddressBytes(id)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, b)
+	}
+	return vals, nil
+}
+
+// encodeCapabilityTLV serialises the router capability TLV (type 242),
+// emitting one TLV per capability, ordered by instance number. Each is encoded
+// as a 4-byte router ID, a flag byte and the sub-TLVs.
+func encodeCapabilityTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	var ids []uint32
+	for id := range t.Capability {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var pErr errlist.List
+	var vals [][]byte
+	for _, id := range ids {
+		c := t.Capability[id]
+		b, err := ipv4AddressBytes(stringValue(c.RouterId))
+		if err != nil {
+			pErr.Add(fmt.Errorf("capability %d: %v", id, err))
+			continue
+		}
+
+		var fb uint8
+		for _, f := range c.Flags {
+			switch f {
+			case oc.OpenconfigIsis_Capability_Flags_DOWN:
+				fb |= bit6
+			case oc.OpenconfigIsis_Capability_Flags_FLOOD:
+				fb |= bit7
+			}
+		}
+		b = append(b, fb)
+
+		subs, err := capabilitySubTLVs(c)
+		if err != nil {
+			pErr.Add(fmt.Errorf("capability %d: %v", id, err))
+			continue
+		}
+		b = appendSubTLVs(b, subs)
+		if len(b) > maxTLVValueLen {
+			pErr.Add(fmt.Errorf("capability %d: serialised length %d exceeds %d bytes", id, len(b), maxTLVValueLen))
+			continue
+		}
+		vals = append(vals, b)
+	}
+	return vals, pErr.Err()
+}
+
+// capabilitySubTLVs serialises the sub-TLVs of the router capability c. SRGB
+// labels are encoded as a 3-byte label where they fit into 20 bits, and as a
+// 4-byte index otherwise. Returns an error if a sub-TLV cannot be serialised.
+func capabilitySubTLVs(c *oc.Lsp_Tlv_Capability) ([]encodedSubTLV, error) {
+	var pErr errlist.List
+	var subs []encodedSubTLV
+	for st, s := range c.Subtlv {
+		switch st {
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_CAPABILITY:
+			srcap := s.GetSegmentRoutingCapability()
+			if srcap == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing SR capability", st))
+				continue
+			}
+			var fb uint8
+			for _, f := range srcap.Flags {
+				switch f {
+				case oc.OpenconfigIsis_SegmentRoutingCapability_Flags_IPV4_MPLS:
+					fb |= bit0
+				case oc.OpenconfigIsis_SegmentRoutingCapability_Flags_IPV6_MPLS:
+					fb |= bit1
+				}
+			}
+
+			var descrs []uint32
+			for x := range srcap.SrgbDescriptor {
+				descrs = append(descrs, x)
+			}
+			sort.Slice(descrs, func(i, j int) bool { return descrs[i] < descrs[j] })
+
+			const maxLabel = 1<<20 - 1
+			v := []byte{fb}
+			var err error
+			for _, x := range descrs {
+				d := srcap.SrgbDescriptor[x]
+				l, ok := d.Label.(*oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor_Label_Union_Uint32)
+				if !ok {
+					err = fmt.Errorf("SRGB descriptor %d: unsupported label %v", x, d.Label)
+					break
+				}
+				if v, err = appendUint24(v, uint32Value(d.Range)); err != nil {
+					err = fmt.Errorf("SRGB descriptor %d: range %v", x, err)
+					break
+				}
+				if l.Uint32 <= maxLabel {
+					v = append(v, 1, 3, uint8(l.Uint32>>16), uint8(l.Uint32>>8), uint8(l.Uint32))
+					continue
+				}
+				v = append(v, 1, 4, uint8(l.Uint32>>24), uint8(l.Uint32>>16), uint8(l.Uint32>>8), uint8(l.Uint32))
+			}
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{2, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_ALGORITHM:
+			algs := s.GetSegmentRoutingAlgorithms()
+			if algs == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing SR algorithms", st))
+				continue
+			}
+			var v []byte
+			for _, a := range algs.Algorithm {
+				switch a {
+				case oc.OpenconfigIsis_SegmentRoutingAlgorithms_Algorithm_SPF:
+					v = append(v, 0)
+				case oc.OpenconfigIsis_SegmentRoutingAlgorithms_Algorithm_STRICT_SPF:
+					v = append(v, 1)
+				}
+			}
+			subs = append(subs, encodedSubTLV{19, v})
+		default:
+			pErr.Add(fmt.Errorf("sub-TLV %v: encoding is not supported", st))
+		}
+	}
+	for st, u := range c.UndefinedSubtlv {
+		subs = append(subs, encodedSubTLV{st, u.Value})
+	}
+	sortSubTLVs(subs)
+	return subs, pErr.Err()
+}
+
+// encodePrefixSIDSubTLV serialises the Prefix SID sub-TLV p of the IP
+// reachability TLVs, such that it is the inverse of parsePrefixSIDSubTLV.
+func encodePrefixSIDSubTLV(p *prefixSIDSubTLV) encodedSubTLV {
+	var fb uint8
+	var isLabel bool
+	for _, f := range p.Flags {
+		switch f {
+		case oc.OpenconfigIsis_PrefixSid_Flags_READVERTISEMENT:
+			fb |= bit0
+		case oc.OpenconfigIsis_PrefixSid_Flags_NODE:
+			fb |= bit1
+		case oc.OpenconfigIsis_PrefixSid_Flags_NO_PHP:
+			fb |= bit2
+		case oc.OpenconfigIsis_PrefixSid_Flags_EXPLICIT_NULL:
+			fb |= bit3
+		case oc.OpenconfigIsis_PrefixSid_Flags_VALUE:
+			fb |= bit4
+			isLabel = true
+		case oc.OpenconfigIsis_PrefixSid_Flags_LOCAL:
+			fb |= bit5
+		}
+	}
+
+	v := []byte{fb, p.Algorithm}
+	if isLabel {
+		v = append(v, uint8(p.Value>>16), uint8(p.Value>>8), uint8(p.Value))
+	} else {
+		v = append(v, uint8(p.Value>>24), uint8(p.Value>>16), uint8(p.Value>>8), uint8(p.Value))
+	}
+	return encodedSubTLV{3, v}
+}
+
+// appendPrefixSubTLVs appends the sub-TLV length and sub-TLVs subs of an IP
+// reachability prefix to b. Returns an error if the sub-TLVs cannot be
+// described by a single-octet length.
+func appendPrefixSubTLVs(b []byte, subs []encodedSubTLV) ([]byte, error) {
+	sb := appendSubTLVs(nil, subs)
+	if len(sb) > maxTLVValueLen {
+		return nil, fmt.Errorf("serialised sub-TLVs length %d exceeds %d bytes", len(sb), maxTLVValueLen)
+	}
+	b = append(b, uint8(len(sb)))
+	return append(b, sb...), nil
+}
+
+// prefixSubTLVs serialises the sub-TLVs of the prefix p of the extended IP
+// reachability TLV. The IPv6 reachability TLV carries the same sub-TLVs, and
+// its prefixes are serialised following conversion by ipv6PrefixSubTLVs.
+// Tags are packed into as few sub-TLVs as possible. Returns an error if a
+// sub-TLV cannot be serialised.
+func prefixSubTLVs(p *oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix) ([]encodedSubTLV, error) {
+	var pErr errlist.List
+	var subs []encodedSubTLV
+	for st, s := range p.Subtlv {
+		switch st {
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG:
+			var tags [][]byte
+			for _, t := range s.GetTag().Tag32 {
+				v := make([]byte, 4)
+				binary.BigEndian.PutUint32(v, t)
+				tags = append(tags, v)
+			}
+			vals, err := packTLVValues(tags)
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			for _, v := range vals {
+				subs = append(subs, encodedSubTLV{1, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64:
+			var tags [][]byte
+			for _, t := range s.GetTag64().Tag64 {
+				v := make([]byte, 8)
+				binary.BigEndian.PutUint64(v, t)
+				tags = append(tags, v)
+			}
+			vals, err := packTLVValues(tags)
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			for _, v := range vals {
+				subs = append(subs, encodedSubTLV{2, v})
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID:
+			for _, sid := range s.PrefixSid {
+				subs = append(subs, encodePrefixSIDSubTLV(&prefixSIDSubTLV{
+					Algorithm: uint8Value(sid.Algorithm),
+					Value:     uint32Value(sid.Value),
+					Flags:     sid.Flags,
+				}))
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS:
+			var fb uint8
+			for _, f := range s.GetFlags().Flags {
+				for _, pf := range prefixAttributeFlagBits {
+					if pf.flag == f {
+						fb |= pf.bit
+					}
+				}
+			}
+			subs = append(subs, encodedSubTLV{4, []byte{fb}})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID:
+			rid := s.GetIpv4SourceRouterId()
+			if rid == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing router ID", st))
+				continue
+			}
+			v, err := ipv4AddressBytes(stringValue(rid.RouterId))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{11, v})
+		case oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID:
+			rid := s.GetIpv6SourceRouterId()
+			if rid == nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: missing router ID", st))
+				continue
+			}
+			v, err := ipv6AddressBytes(stringValue(rid.RouterId))
+			if err != nil {
+				pErr.Add(fmt.Errorf("sub-TLV %v: %v", st, err))
+				continue
+			}
+			subs = append(subs, encodedSubTLV{12, v})
+		default:
+			pErr.Add(fmt.Errorf("sub-TLV %v: encoding is not supported", st))
+		}
+	}
+	for st, u := range p.UndefinedSubtlv {
+		subs = append(subs, encodedSubTLV{st, u.Value})
+	}
+	sortSubTLVs(subs)
+	return subs, pErr.Err()
+}
+
+// ipv6PrefixSubTLVs returns the sub-TLVs of the prefix p of the IPv6
+// reachability TLV within a prefix of the extended IP reachability TLV, such
+// that they can be serialised by prefixSubTLVs. As per mtIPv4Prefix, the
+// OpenConfig model uses distinct, but identical, types for the sub-TLVs of the
+// two TLVs, such that they are converted rather than copied.
+func ipv6PrefixSubTLVs(p *oc.Lsp_Tlv_Ipv6Reachability_Prefix) *oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix {
+	ep := &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{}
+	for t, st := range p.Subtlv {
+		est := &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+			Type:               st.Type,
+			Flags:              (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Flags)(st.Flags),
+			Ipv4SourceRouterId: (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Ipv4SourceRouterId)(st.Ipv4SourceRouterId),
+			Ipv6SourceRouterId: (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(st.Ipv6SourceRouterId),
+			Tag:                (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Tag)(st.Tag),
+			Tag64:              (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Tag64)(st.Tag64),
+		}
+		for v, sid := range st.PrefixSid {
+			if est.PrefixSid == nil {
+				est.PrefixSid = map[uint32]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid{}
+			}
+			est.PrefixSid[v] = (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid)(sid)
+		}
+		if ep.Subtlv == nil {
+			ep.Subtlv = map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{}
+		}
+		ep.Subtlv[t] = est
+	}
+
+	for t, u := range p.UndefinedSubtlv {
+		if ep.UndefinedSubtlv == nil {
+			ep.UndefinedSubtlv = map[uint8]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_UndefinedSubtlv{}
+		}
+		ep.UndefinedSubtlv[t] = (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_UndefinedSubtlv)(u)
+	}
+
+	return ep
+}
+
+// encodeExtendedIPReachTLV serialises the extended IP reachability TLV (type
+// 135). Each prefix is encoded as a 4-byte metric, a control byte, the prefix,
+// and, where the prefix has sub-TLVs, a sub-TLV length and the sub-TLVs, with
+// prefixes ordered by their string representation.
+func encodeExtendedIPReachTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	r := t.GetExtendedIpv4Reachability()
+	if r == nil {
+		return nil, fmt.Errorf("TLV does not contain extended IPv4 reachability")
+	}
+
+	var pfxs []string
+	for pfx := range r.Prefix {
+		pfxs = append(pfxs, pfx)
+	}
+	sort.Strings(pfxs)
+
+	var pErr errlist.List
+	var entries [][]byte
+	for _, pfx := range pfxs {
+		ip, n, err := net.ParseCIDR(pfx)
+		if err != nil || ip.To4() == nil {
+			pErr.Add(fmt.Errorf("invalid IPv4 prefix %s", pfx))
+			continue
+		}
+		ones, _ := n.Mask.Size()
+
+		p := r.Prefix[pfx]
+		subs, err := prefixSubTLVs(p)
+		if err != nil {
+			pErr.Add(fmt.Errorf("prefix %s: %v", pfx, err))
+			continue
+		}
+
+		ctrl := uint8(ones)
+		if boolValue(p.UpDown) {
+			ctrl |= bit0
+		}
+		hasSubTLVs := len(subs) != 0 || boolValue(p.SBit)
+		if hasSubTLVs {
+			ctrl |= bit1
+		}
+
+		b := make([]byte, 4, 4+1+4+1)
+		binary.BigEndian.PutUint32(b, uint32Value(p.Metric))
+		b = append(b, ctrl)
+		b = append(b, ip.To4()[:(ones+7)/8]...)
+		if hasSubTLVs {
+			if b, err = appendPrefixSubTLVs(b, subs); err != nil {
+				pErr.Add(fmt.Errorf("prefix %s: %v", pfx, err))
+				continue
+			}
+		}
+		entries = append(entries, b)
+	}
+	if err := pErr.Err(); err != nil {
+		return nil, err
+	}
+	return packTLVValues(entries)
+}
+
+// encodeIPv6ReachabilityTLV serialises the IPv6 reachability TLV (type 236).
+// Each prefix is encoded as a 4-byte metric, a control byte, a prefix length
+// byte, the prefix, and, where the prefix has sub-TLVs, a sub-TLV length and
+// the sub-TLVs, with prefixes ordered by their string representation.
+func encodeIPv6ReachabilityTLV(t *oc.Lsp_Tlv) ([][]byte, error) {
+	r := t.GetIpv6Reachability()
+	if r == nil {
+		return nil, fmt.Errorf("TLV does not contain IPv6 reachability")
+	}
+
+	var pfxs []string
+	for pfx := range r.Prefix {
+		pfxs = append(pfxs, pfx)
+	}
+	sort.Strings(pfxs)
+
+	var pErr errlist.List
+	var entries [][]byte
+	for _, pfx := range pfxs {
+		ip, n, err := net.ParseCIDR(pfx)
+		if err != nil || ip.To4() != nil {
+			pErr.Add(fmt.Errorf("invalid IPv6 prefix %s", pfx))
+			continue
+		}
+		ones, _ := n.Mask.Size()
+
+		p := r.Prefix[pfx]
+		subs, err := prefixSubTLVs(ipv6PrefixSubTLVs(p))
+		if err != nil {
+			pErr.Add(fmt.Errorf("prefix %s: %v", pfx, err))
+			continue
+		}
+
+		var ctrl uint8
+		if boolValue(p.UpDown) {
+			ctrl |= bit0
+		}
+		if boolValue(p.XBit) {
+			ctrl |= bit1
+		}
+		hasSubTLVs := len(subs) != 0 || boolValue(p.SBit)
+		if hasSubTLVs {
+			ctrl |= bit2
+		}
+
+		b := make([]byte, 4, 4+2+16+1)
+		binary.BigEndian.PutUint32(b, uint32Value(p.Metric))
+		b = append(b, ctrl, uint8(ones))
+		b = append(b, ip.To16()[:(ones+7)/8]...)
+		if hasSubTLVs {
+			if b, err = appendPrefixSubTLVs(b, subs); err != nil {
+				pErr.Add(fmt.Errorf("prefix %s: %v", pfx, err))
+				continue
+			}
+		}
+		entries = append(entries, b)
+	}
+	if err := pErr.Err(); err != nil {
+		return nil, err
+	}
+	return packTLVValues(entries)
+}
@@ -0,0 +1,400 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+// manyPrefixLSP returns an LSP containing an extended IPv4 reachability TLV
+// with n /24 prefixes.
+func manyPrefixLSP(n int) *oc.Lsp {
+	l := &oc.Lsp{}
+	tlv := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY)
+	for i := 0; i < n; i++ {
+		p := fmt.Sprintf("10.%d.%d.0/24", i/256, i%256)
+		tlv.GetOrCreateExtendedIpv4Reachability().GetOrCreatePrefix(p).Metric = ygot.Uint32(10)
+	}
+	return l
+}
+
+func TestValidateEncodable(t *testing.T) {
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		wantErrSubstring string
+	}{{
+		name: "encodable LSP",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES,
+					AreaAddress: &oc.Lsp_Tlv_AreaAddress{
+						Address: []string{"49.0001"},
+					},
+				},
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+					Hostname: &oc.Lsp_Tlv_Hostname{
+						Hostname: []string{"router1"},
+					},
+				},
+			},
+		},
+	}, {
+		name:  "reachability TLV that fits in a single TLV",
+		inLSP: manyPrefixLSP(31),
+	}, {
+		name:  "reachability TLV that is split across TLVs",
+		inLSP: manyPrefixLSP(100),
+	}, {
+		name: "hostname exceeding 255 bytes",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+					Hostname: &oc.Lsp_Tlv_Hostname{
+						Hostname: []string{string(make([]byte, 256))},
+					},
+				},
+			},
+		},
+		wantErrSubstring: "TLV DYNAMIC_NAME: hostname",
+	}, {
+		name: "unsupported TLV",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY: {
+					Type:          oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY,
+					MultiTopology: &oc.Lsp_Tlv_MultiTopology{},
+				},
+			},
+		},
+		wantErrSubstring: "TLV MULTI_TOPOLOGY: encoding is not supported",
+	}, {
+		name: "unsupported sub-TLV",
+		inLSP: func() *oc.Lsp {
+			l := &oc.Lsp{}
+			inst := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("1920.0000.2001.00").GetOrCreateInstance(0)
+			inst.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_UTILIZED_BANDWIDTH)
+			return l
+		}(),
+		wantErrSubstring: "sub-TLV IS_REACHABILITY_UTILIZED_BANDWIDTH: encoding is not supported",
+	}, {
+		name: "sub-TLVs exceeding 255 bytes",
+		inLSP: func() *oc.Lsp {
+			l := &oc.Lsp{}
+			inst := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("1920.0000.2001.00").GetOrCreateInstance(0)
+			st := inst.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
+			for i := uint32(0); i < 40; i++ {
+				st.GetOrCreateAdjacencySid(i)
+			}
+			return l
+		}(),
+		wantErrSubstring: "neighbor 1920.0000.2001.00 instance 0: serialised length 331 exceeds 255 bytes",
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		err := ValidateEncodable(tt.inLSP)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ValidateEncodable(%v): did not get expected error, %s", tt.name, tt.inLSP, diff)
+		}
+	}
+}
+
+func TestEncodeExtendedISReachabilityTLV(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		inBytes []byte
+	}{{
+		name:    "vendor c example #1",
+		inBytes: exampleLSP1,
+	}, {
+		name:    "vendor c example #2",
+		inBytes: exampleLSP2,
+	}, {
+		name:    "vendor c example #3",
+		inBytes: exampleLSP3,
+	}, {
+		name: "neighbor with two ASLA sub-TLVs",
+		inBytes: appendByteSlice(
+			[]byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03},
+			[]byte{22, 33, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, 0, 0, 10, 22},
+			[]byte{aslaSubTLVType, 9}, srPolicyAdminGroupASLA,
+			[]byte{aslaSubTLVType, 9, 0x01, 0x00, 0x80, 3, 4, 0, 0, 0, 6},
+		),
+	}} {
+		want, ok, err := ISISBytesToLSP(tt.inBytes, 0)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+		wantTLV := want.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY)
+
+		vals, err := encodeExtendedISReachabilityTLV(wantTLV)
+		if err != nil {
+			t.Errorf("%s: encodeExtendedISReachabilityTLV(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		got := &oc.Lsp{}
+		for _, v := range vals {
+			if err := ParseTLV(got, 22, v); err != nil {
+				t.Errorf("%s: ParseTLV(22, %v): could not parse encoded TLV: %v", tt.name, v, err)
+			}
+		}
+
+		gotTLV := got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY)
+		if diff := pretty.Compare(gotTLV, wantTLV); diff != "" {
+			t.Errorf("%s: encodeExtendedISReachabilityTLV(...): did not round-trip TLV, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestEncodeExtendedISReachabilityTLVErrors(t *testing.T) {
+	metricLSP := &oc.Lsp{}
+	metricLSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("1920.0000.2001.00").GetOrCreateInstance(0).Metric = ygot.Uint32(0x1000000)
+
+	wideLSP := &oc.Lsp{}
+	inst := wideLSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("1920.0000.2001.00").GetOrCreateInstance(0)
+	inst.Metric = ygot.Uint32(0xFFFFFF)
+	inst.GetOrCreateUndefinedSubtlv(250).Value = make([]byte, 250)
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		wantErrSubstring string
+	}{{
+		name:             "metric exceeds 3 bytes",
+		inLSP:            metricLSP,
+		wantErrSubstring: "metric 16777216 exceeds maximum of 16777215",
+	}, {
+		name:             "sub-TLVs too long",
+		inLSP:            wideLSP,
+		wantErrSubstring: "serialised length 263 exceeds 255 bytes",
+	}}
+
+	for _, tt := range tests {
+		_, err := encodeExtendedISReachabilityTLV(tt.inLSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY))
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: encodeExtendedISReachabilityTLV(...): did not get expected error, %s", tt.name, diff)
+		}
+	}
+}
+
+func TestLSPToISISBytes(t *testing.T) {
+	// manyPrefixLSP has sufficient IPv4 prefixes that they must be split
+	// across multiple Extended IP Reachability TLVs.
+	manyPrefixLSP := &oc.Lsp{
+		LspId:          ygot.String("1920.0000.2001.00-00"),
+		SequenceNumber: ygot.Uint32(42),
+		Checksum:       ygot.Uint16(0x1234),
+		IsType:         ygot.Uint8(3),
+		Flags:          []oc.E_OpenconfigIsis_Lsp_Flags{oc.OpenconfigIsis_Lsp_Flags_OVERLOAD},
+	}
+	r := manyPrefixLSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetOrCreateExtendedIpv4Reachability()
+	for x := 0; x < 64; x++ {
+		pfx := fmt.Sprintf("192.0.2.%d/32", x)
+		r.GetOrCreatePrefix(pfx).Metric = ygot.Uint32(uint32(x))
+		r.Prefix[pfx].UpDown = ygot.Bool(false)
+		r.Prefix[pfx].SBit = ygot.Bool(false)
+	}
+
+	parseLSP := func(b []byte) *oc.Lsp {
+		lsp, ok, err := ISISBytesToLSP(b, 0)
+		if !ok || err != nil {
+			t.Fatalf("ISISBytesToLSP(...): could not parse LSP, ok: %v, err: %v", ok, err)
+		}
+		return lsp
+	}
+
+	lspHeader := []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03}
+	v6Addr := []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	// prefixSubTLVs is the set of sub-TLVs that are carried by both the
+	// extended IP reachability and IPv6 reachability TLVs.
+	prefixSubTLVs := appendByteSlice(
+		[]byte{1, 8, 0, 0, 0, 1, 0, 0, 0, 2},
+		[]byte{2, 8, 0, 0, 0, 0, 0, 0, 0, 3},
+		[]byte{4, 1, 0x80},
+		[]byte{11, 4, 192, 0, 2, 1},
+		[]byte{12, 16}, v6Addr,
+	)
+
+	tests := []struct {
+		name  string
+		inLSP *oc.Lsp
+		// wantTLVCount is the number of TLVs of each type that are
+		// expected in the serialised LSP, if it is to be checked.
+		wantTLVCount map[uint8]int
+	}{{
+		name:  "vendor c example #1",
+		inLSP: parseLSP(exampleLSP1),
+	}, {
+		name:  "vendor c example #2",
+		inLSP: parseLSP(exampleLSP2),
+	}, {
+		name:         "vendor c example #3",
+		inLSP:        parseLSP(exampleLSP3),
+		wantTLVCount: map[uint8]int{22: 2},
+	}, {
+		name:         "prefixes split across TLVs",
+		inLSP:        manyPrefixLSP,
+		wantTLVCount: map[uint8]int{135: 3},
+	}, {
+		name: "unknown NLPID omitted",
+		inLSP: parseLSP(appendByteSlice(
+			lspHeader,
+			[]byte{129, 2, 0xCC, 0x81},
+		)),
+		wantTLVCount: map[uint8]int{129: 1},
+	}, {
+		name: "decoded prefix sub-TLVs",
+		inLSP: parseLSP(appendByteSlice(
+			lspHeader,
+			[]byte{135, byte(9 + len(prefixSubTLVs)), 0, 0, 0, 10, 0x40 | 24, 192, 0, 2, byte(len(prefixSubTLVs))}, prefixSubTLVs,
+			[]byte{236, byte(15 + len(prefixSubTLVs)), 0, 0, 0, 10, 0x20, 64}, v6Addr[:8], []byte{byte(len(prefixSubTLVs))}, prefixSubTLVs,
+		)),
+		wantTLVCount: map[uint8]int{135: 1, 236: 1},
+	}, {
+		name: "decoded IS reachability sub-TLVs",
+		inLSP: parseLSP(appendByteSlice(
+			lspHeader,
+			[]byte{22, 93, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, 0, 0, 10, 82},
+			[]byte{12, 16}, v6Addr,
+			[]byte{13, 16}, v6Addr,
+			[]byte{14, 8, 0, 0, 0, 1, 0x80, 0, 0, 0},
+			[]byte{18, 3, 0, 0, 20},
+			[]byte{20, 2, 0x20, 0},
+			[]byte{33, 4, 0x80, 0, 0, 100},
+			[]byte{34, 7, 0, 0, 0, 50, 0, 0, 200},
+			[]byte{35, 4, 0, 0, 0, 5},
+			[]byte{36, 4, 0x80, 0, 0, 3},
+		)),
+		wantTLVCount: map[uint8]int{22: 1},
+	}, {
+		name: "IPv6 interface address and TE router ID",
+		inLSP: parseLSP(appendByteSlice(
+			lspHeader,
+			[]byte{140, 16}, v6Addr,
+			[]byte{232, 16}, v6Addr,
+		)),
+		wantTLVCount: map[uint8]int{140: 1, 232: 1},
+	}}
+
+	for _, tt := range tests {
+		b, err := LSPToISISBytes(tt.inLSP)
+		if err != nil {
+			t.Errorf("%s: LSPToISISBytes(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		gotCount := map[uint8]int{}
+		if err := ParseTLVStream(b, 0, func(tlvType uint8, _ []byte) error {
+			gotCount[tlvType]++
+			return nil
+		}); err != nil {
+			t.Errorf("%s: ParseTLVStream(LSPToISISBytes(...)): got unexpected error: %v", tt.name, err)
+			continue
+		}
+		for typ, n := range tt.wantTLVCount {
+			if gotCount[typ] != n {
+				t.Errorf("%s: LSPToISISBytes(...): did not get expected number of TLVs of type %d, got: %d, want: %d", tt.name, typ, gotCount[typ], n)
+			}
+		}
+
+		got, ok, err := ISISBytesToLSP(b, 0)
+		if !ok || err != nil {
+			t.Errorf("%s: ISISBytesToLSP(LSPToISISBytes(...)): could not parse serialised LSP, ok: %v, err: %v", tt.name, ok, err)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.inLSP); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(LSPToISISBytes(...)): did not round-trip LSP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+
+	// Only the first of repeated TLVs of an unknown type is stored, and
+	// hence serialised.
+	repeated := append(append([]byte{}, lspHeader...), 250, 1, 0x01, 250, 1, 0x02)
+	lsp, ok, err := ISISBytesToLSP(repeated, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(%v): could not parse LSP: %v", repeated, err)
+	}
+	if diff := errdiff.Substring(err, "duplicate undefined TLV of type 250"); diff != "" {
+		t.Errorf("ISISBytesToLSP(%v): did not get expected error, %s", repeated, diff)
+	}
+	if err := ValidateEncodable(lsp); err != nil {
+		t.Errorf("ValidateEncodable(%v): got unexpected error: %v", lsp, err)
+	}
+	b, err := LSPToISISBytes(lsp)
+	if err != nil {
+		t.Fatalf("LSPToISISBytes(%v): got unexpected error: %v", lsp, err)
+	}
+	if want := append(append([]byte{}, lspHeader...), 250, 1, 0x01); !bytes.Equal(b, want) {
+		t.Errorf("LSPToISISBytes(%v): did not get expected bytes, got: %v, want: %v", lsp, b, want)
+	}
+}
+
+func TestLSPToISISBytesErrors(t *testing.T) {
+	newLSP := func() *oc.Lsp {
+		return &oc.Lsp{
+			LspId:          ygot.String("1920.0000.2001.00-00"),
+			SequenceNumber: ygot.Uint32(1),
+		}
+	}
+
+	mtLSP := newLSP()
+	mtLSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY).GetOrCreateMultiTopology().GetOrCreateTopology(2)
+
+	nameLSP := newLSP()
+	nameLSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetOrCreateHostname().Hostname = []string{string(make([]byte, 256))}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		wantErrSubstring string
+	}{{
+		name:             "nil LSP",
+		wantErrSubstring: "nil LSP",
+	}, {
+		name:             "invalid LSP ID",
+		inLSP:            &oc.Lsp{LspId: ygot.String("1920.0000.2001.00")},
+		wantErrSubstring: "invalid",
+	}, {
+		name:             "unsupported TLV",
+		inLSP:            mtLSP,
+		wantErrSubstring: "encoding is not supported",
+	}, {
+		name:             "hostname too long",
+		inLSP:            nameLSP,
+		wantErrSubstring: "length 256 exceeds 255 bytes",
+	}}
+
+	for _, tt := range tests {
+		_, err := LSPToISISBytes(tt.inLSP)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: LSPToISISBytes(...): did not get expected error, %s", tt.name, diff)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+const (
+	// threeWayAdjacencyTLVType is the type of the point-to-point three-way
+	// adjacency TLV, defined in RFC5303.
+	threeWayAdjacencyTLVType uint8 = 240
+	// lanHelloHeaderLen is the length of the header of a LAN IS-IS Hello,
+	// consisting of the common header, circuit type (1 byte), source ID,
+	// holding time (2 bytes), PDU length (2 bytes), priority (1 byte) and
+	// LAN ID.
+	lanHelloHeaderLen = commonHeaderLen + 1 + defaultIDLength + 2 + 2 + 1 + defaultIDLength + 1
+	// p2pHelloHeaderLen is the length of the header of a point-to-point
+	// IS-IS Hello, consisting of the common header, circuit type (1 byte),
+	// source ID, holding time (2 bytes), PDU length (2 bytes) and local
+	// circuit ID (1 byte).
+	p2pHelloHeaderLen = commonHeaderLen + 1 + defaultIDLength + 2 + 2 + 1
+)
+
+// ThreeWayAdjacencyState is the state of a point-to-point adjacency that is
+// reported in the three-way adjacency TLV.
+type ThreeWayAdjacencyState uint8
+
+const (
+	// ThreeWayAdjacencyUp indicates that the adjacency is up.
+	ThreeWayAdjacencyUp ThreeWayAdjacencyState = 0
+	// ThreeWayAdjacencyInitializing indicates that the adjacency is
+	// initialising.
+	ThreeWayAdjacencyInitializing ThreeWayAdjacencyState = 1
+	// ThreeWayAdjacencyDown indicates that the adjacency is down.
+	ThreeWayAdjacencyDown ThreeWayAdjacencyState = 2
+)
+
+// String returns a human-readable name for the adjacency state.
+func (s ThreeWayAdjacencyState) String() string {
+	switch s {
+	case ThreeWayAdjacencyUp:
+		return "UP"
+	case ThreeWayAdjacencyInitializing:
+		return "INITIALIZING"
+	case ThreeWayAdjacencyDown:
+		return "DOWN"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(s))
+}
+
+// ThreeWayAdjacency is the contents of the point-to-point three-way adjacency
+// TLV (type 240) defined in RFC5303. Fields that are optional within the TLV
+// are nil or empty when they are not present.
+type ThreeWayAdjacency struct {
+	// State is the adjacency state of the sending system.
+	State ThreeWayAdjacencyState
+	// ExtendedLocalCircuitID is the extended local circuit ID of the
+	// sending system.
+	ExtendedLocalCircuitID *uint32
+	// NeighborSystemID is the system ID of the neighbor, in canonical
+	// format (i.e., xxxx.yyyy.zzzz).
+	NeighborSystemID string
+	// NeighborExtendedLocalCircuitID is the extended local circuit ID of
+	// the neighbor.
+	NeighborExtendedLocalCircuitID *uint32
+}
+
+// parseThreeWayAdjacencyTLV parses the point-to-point three-way adjacency TLV
+// (type 240) defined in RFC5303. The TLV consists of a 1-byte adjacency state,
+// optionally followed by a 4-byte extended local circuit ID, a 6-byte neighbor
+// system ID and a 4-byte neighbor extended local circuit ID - such that the
+// valid lengths of the TLV are 1, 5, 11 and 15 bytes. Returns an error if the
+// TLV is of any other length.
+func parseThreeWayAdjacencyTLV(r *rawTLV) (*ThreeWayAdjacency, error) {
+	switch len(r.Value) {
+	case 1, 5, 11, 15:
+	default:
+		return nil, fmt.Errorf("invalid length three-way adjacency TLV: %d", len(r.Value))
+	}
+
+	a := &ThreeWayAdjacency{
+		State: ThreeWayAdjacencyState(r.Value[0]),
+	}
+
+	if len(r.Value) >= 5 {
+		c := binary.BigEndian.Uint32(r.Value[1:5])
+		a.ExtendedLocalCircuitID = &c
+	}
+
+	if len(r.Value) >= 11 {
+		a.NeighborSystemID = fastCanonicalHexString(r.Value[5:11])
+	}
+
+	if len(r.Value) == 15 {
+		c := binary.BigEndian.Uint32(r.Value[11:15])
+		a.NeighborExtendedLocalCircuitID = &c
+	}
+
+	return a, nil
+}
+
+// Hello is the contents of an IS-IS Hello (IIH) PDU.
+type Hello struct {
+	// PDUType is the PDU type of the Hello, PDUTypeL1LANHello,
+	// PDUTypeL2LANHello or PDUTypeP2PHello.
+	PDUType uint8
+	// CircuitType is the circuit type of the sending system, where 1
+	// indicates level 1 only, 2 level 2 only, and 3 level 1 and 2.
+	CircuitType uint8
+	// SourceID is the system ID of the sending system, in the format
+	// xxxx.yyyy.zzzz.
+	SourceID string
+	// HoldingTime is the holding time of the adjacency, in seconds.
+	HoldingTime uint16
+	// PDULength is the length of the PDU, in bytes.
+	PDULength uint16
+	// Priority is the priority of the sending system for election as the
+	// designated IS. It is only set for LAN Hellos.
+	Priority uint8
+	// LANID is the LAN ID of the designated IS, in the format
+	// xxxx.yyyy.zzzz.nn. It is only set for LAN Hellos.
+	LANID string
+	// LocalCircuitID is the local circuit ID of the sending system. It is
+	// only set for point-to-point Hellos.
+	LocalCircuitID uint8
+	// AreaAddresses are the area addresses carried in the Area Addresses
+	// TLV (1).
+	AreaAddresses []string
+	// Protocols are the NLPIDs carried in the Protocols Supported TLV
+	// (129).
+	Protocols []oc.E_OpenconfigIsis_Nlpid_Nlpid
+	// IPv4InterfaceAddresses are the addresses carried in the IP Interface
+	// Address TLV (132).
+	IPv4InterfaceAddresses []string
+	// ThreeWayAdjacency is the contents of the three-way adjacency TLV
+	// (240), or nil if it is not present.
+	ThreeWayAdjacency *ThreeWayAdjacency
+}
+
+// ParseHello parses the IS-IS Hello PDU pdu, starting at the intradomain
+// routeing protocol discriminator of the common header. The fixed header
+// fields are extracted, along with the Area Addresses (1), Protocols
+// Supported (129), IP Interface Address (132) and three-way adjacency (240)
+// TLVs; other TLVs, such as padding, are ignored. The TLVs are decoded using
+// the same parsing as for LSPs. Returns an error, and a nil Hello, if the PDU
+// is not a Hello or its header is malformed. Where the header is valid, but
+// TLVs cannot be parsed, the Hello is returned along with an error.
+func ParseHello(pdu []byte) (*Hello, error) {
+	if len(pdu) < commonHeaderLen {
+		return nil, fmt.Errorf("invalid PDU, need at least %d bytes for common header, got %d bytes", commonHeaderLen, len(pdu))
+	}
+
+	if pdu[0] != isisIRPD {
+		return nil, fmt.Errorf("invalid PDU, unknown intradomain routeing protocol discriminator %#x", pdu[0])
+	}
+
+	h := &Hello{PDUType: pdu[4] & pduTypeMask}
+	var hdrLen int
+	switch h.PDUType {
+	case PDUTypeL1LANHello, PDUTypeL2LANHello:
+		hdrLen = lanHelloHeaderLen
+	case PDUTypeP2PHello:
+		hdrLen = p2pHelloHeaderLen
+	default:
+		return nil, fmt.Errorf("invalid PDU, PDU type %d is not a Hello", h.PDUType)
+	}
+
+	// The IDs are parsed assuming the default system ID length.
+	if idLen := pdu[3]; idLen != 0 && idLen != defaultIDLength {
+		return nil, fmt.Errorf("invalid PDU, unsupported system ID length %d", idLen)
+	}
+
+	if len(pdu) < hdrLen {
+		return nil, fmt.Errorf("invalid PDU, need at least %d bytes for header, got %d bytes", hdrLen, len(pdu))
+	}
+
+	// The circuit type is carried in the two least significant bits of
+	// the first byte after the common header.
+	h.CircuitType = pdu[8] & (bit6 | bit7)
+	h.SourceID = fastCanonicalHexString(pdu[9:15])
+	h.HoldingTime = binary.BigEndian.Uint16(pdu[15:17])
+	h.PDULength = binary.BigEndian.Uint16(pdu[17:19])
+
+	switch pduLen := int(h.PDULength); {
+	case pduLen < hdrLen:
+		return nil, fmt.Errorf("invalid PDU, declared PDU length of %d bytes is shorter than the header", pduLen)
+	case pduLen > len(pdu):
+		return nil, fmt.Errorf("invalid PDU, declared PDU length of %d bytes exceeds %d bytes supplied", pduLen, len(pdu))
+	}
+
+	if h.PDUType == PDUTypeP2PHello {
+		h.LocalCircuitID = pdu[19]
+	} else {
+		// The priority is carried in the 7 least significant bits.
+		h.Priority = pdu[19] &^ bit0
+		h.LANID = fastCanonicalHexString(pdu[20:27])
+	}
+
+	// The TLVs are decoded into an LSP such that the existing TLV parsing
+	// is reused, and then copied into the Hello.
+	var pErr errlist.List
+	lsp := &oc.Lsp{}
+	err := walkTLVs(pdu[hdrLen:h.PDULength], func(tlvType uint8, value []byte) error {
+		switch tlvType {
+		case 1, 129, 132:
+			pErr.Add(ParseTLV(lsp, tlvType, value))
+		case threeWayAdjacencyTLVType:
+			a, err := parseThreeWayAdjacencyTLV(&rawTLV{Type: tlvType, Length: uint8(len(value)), Value: value})
+			if err != nil {
+				pErr.Add(err)
+				return nil
+			}
+			h.ThreeWayAdjacency = a
+		}
+		return nil
+	})
+	if err != nil {
+		pErr.Add(fmt.Errorf("invalid TLVs in Hello: %v", err))
+	}
+
+	if a := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES).GetAreaAddress(); a != nil {
+		h.AreaAddresses = a.Address
+	}
+	if n := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID).GetNlpid(); n != nil {
+		h.Protocols = n.Nlpid
+	}
+	if a := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_INTERFACE_ADDRESSES).GetIpv4InterfaceAddresses(); a != nil {
+		h.IPv4InterfaceAddresses = a.Address
+	}
+
+	return h, pErr.Err()
+}
@@ -0,0 +1,210 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestParseThreeWayAdjacencyTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		want    *ThreeWayAdjacency
+		wantErr bool
+	}{{
+		name: "full 15-byte form",
+		inTLV: &rawTLV{
+			Type:   240,
+			Length: 15,
+			Value: []byte{
+				0,                      // state: up
+				0x00, 0x00, 0x01, 0x02, // extended local circuit ID
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x01, // neighbor system ID
+				0x80, 0x00, 0x00, 0x2A, // neighbor extended local circuit ID
+			},
+		},
+		want: &ThreeWayAdjacency{
+			State:                          ThreeWayAdjacencyUp,
+			ExtendedLocalCircuitID:         ygot.Uint32(258),
+			NeighborSystemID:               "1920.0000.2001",
+			NeighborExtendedLocalCircuitID: ygot.Uint32(2147483690),
+		},
+	}, {
+		name: "11-byte form without neighbor circuit ID",
+		inTLV: &rawTLV{
+			Type:   240,
+			Length: 11,
+			Value:  []byte{1, 0x00, 0x00, 0x00, 0x01, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01},
+		},
+		want: &ThreeWayAdjacency{
+			State:                  ThreeWayAdjacencyInitializing,
+			ExtendedLocalCircuitID: ygot.Uint32(1),
+			NeighborSystemID:       "1920.0000.2001",
+		},
+	}, {
+		name: "5-byte form",
+		inTLV: &rawTLV{
+			Type:   240,
+			Length: 5,
+			Value:  []byte{2, 0x00, 0x00, 0x00, 0x07},
+		},
+		want: &ThreeWayAdjacency{
+			State:                  ThreeWayAdjacencyDown,
+			ExtendedLocalCircuitID: ygot.Uint32(7),
+		},
+	}, {
+		name: "state only",
+		inTLV: &rawTLV{
+			Type:   240,
+			Length: 1,
+			Value:  []byte{0},
+		},
+		want: &ThreeWayAdjacency{
+			State: ThreeWayAdjacencyUp,
+		},
+	}, {
+		name: "invalid length",
+		inTLV: &rawTLV{
+			Type:   240,
+			Length: 7,
+			Value:  []byte{0, 0, 0, 0, 1, 0x19, 0x20},
+		},
+		wantErr: true,
+	}, {
+		name: "empty",
+		inTLV: &rawTLV{
+			Type: 240,
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := parseThreeWayAdjacencyTLV(tt.inTLV)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseThreeWayAdjacencyTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.inTLV, err, tt.wantErr)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: parseThreeWayAdjacencyTLV(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestParseHello(t *testing.T) {
+	// hello returns a Hello PDU with the header hdr following the common
+	// header, and the TLVs supplied, with the PDU length set.
+	hello := func(pduType uint8, hdr []byte, tlvs ...byte) []byte {
+		pdu := append([]byte{0x83, uint8(commonHeaderLen + len(hdr)), 1, 0, pduType, 1, 0, 0}, hdr...)
+		pdu = append(pdu, tlvs...)
+		binary.BigEndian.PutUint16(pdu[17:19], uint16(len(pdu)))
+		return pdu
+	}
+
+	lanHdr := []byte{
+		0x02,                               // circuit type: level 2
+		0x19, 0x20, 0x00, 0x00, 0x20, 0x01, // source ID
+		0x00, 0x1e, // holding time
+		0x00, 0x00, // PDU length
+		0x40,                                     // priority
+		0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x01, // LAN ID
+	}
+
+	p2pHdr := []byte{
+		0x03,                               // circuit type: level 1 and 2
+		0x19, 0x20, 0x00, 0x00, 0x20, 0x01, // source ID
+		0x00, 0x09, // holding time
+		0x00, 0x00, // PDU length
+		0x01, // local circuit ID
+	}
+
+	tests := []struct {
+		name             string
+		in               []byte
+		want             *Hello
+		wantErrSubstring string
+	}{{
+		name: "LAN IIH",
+		in: hello(PDUTypeL2LANHello, lanHdr,
+			1, 4, 3, 0x49, 0x00, 0x01, // area addresses
+			129, 2, 0xcc, 0x8e, // protocols supported
+			132, 4, 192, 0, 2, 1, // IP interface address
+			8, 3, 0, 0, 0, // padding
+		),
+		want: &Hello{
+			PDUType:                PDUTypeL2LANHello,
+			CircuitType:            2,
+			SourceID:               "1920.0000.2001",
+			HoldingTime:            30,
+			PDULength:              48,
+			Priority:               64,
+			LANID:                  "1920.0000.2002.01",
+			AreaAddresses:          []string{"49.0001"},
+			Protocols:              []oc.E_OpenconfigIsis_Nlpid_Nlpid{oc.OpenconfigIsis_Nlpid_Nlpid_IPV4, oc.OpenconfigIsis_Nlpid_Nlpid_IPV6},
+			IPv4InterfaceAddresses: []string{"192.0.2.1"},
+		},
+	}, {
+		name: "P2P IIH",
+		in: hello(PDUTypeP2PHello, p2pHdr,
+			1, 4, 3, 0x49, 0x00, 0x01, // area addresses
+			129, 1, 0xcc, // protocols supported
+			240, 5, 0, 0x00, 0x00, 0x00, 0x07, // three-way adjacency
+		),
+		want: &Hello{
+			PDUType:        PDUTypeP2PHello,
+			CircuitType:    3,
+			SourceID:       "1920.0000.2001",
+			HoldingTime:    9,
+			PDULength:      36,
+			LocalCircuitID: 1,
+			AreaAddresses:  []string{"49.0001"},
+			Protocols:      []oc.E_OpenconfigIsis_Nlpid_Nlpid{oc.OpenconfigIsis_Nlpid_Nlpid_IPV4},
+			ThreeWayAdjacency: &ThreeWayAdjacency{
+				State:                  ThreeWayAdjacencyUp,
+				ExtendedLocalCircuitID: ygot.Uint32(7),
+			},
+		},
+	}, {
+		name:             "LSP PDU type",
+		in:               WrapAsStandardPDU(exampleLSP1, PDUTypeL2LSP, 0),
+		wantErrSubstring: "PDU type 20 is not a Hello",
+	}, {
+		name:             "truncated LAN header",
+		in:               hello(PDUTypeL1LANHello, lanHdr)[:20],
+		wantErrSubstring: "need at least 27 bytes",
+	}, {
+		name:             "truncated TLVs",
+		in:               hello(PDUTypeP2PHello, p2pHdr, 1, 4, 3, 0x49, 0x00, 0x01)[:24],
+		wantErrSubstring: "exceeds 24 bytes supplied",
+	}}
+
+	for _, tt := range tests {
+		got, err := ParseHello(tt.in)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ParseHello(...): did not get expected error, %s", tt.name, diff)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: ParseHello(...): did not get expected Hello, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
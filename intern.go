@@ -0,0 +1,115 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"sync"
+)
+
+// StringInterner is a cache of strings that is shared between parses of LSPs,
+// such that identical strings (e.g., hostnames, neighbor system IDs and
+// prefixes) that occur in many LSPs share the same backing storage. It is
+// intended for use by collectors that hold a complete LSDB in memory. A
+// StringInterner is safe for concurrent use.
+type StringInterner struct {
+	mu sync.Mutex
+	// strs is the set of interned strings, keyed by the kind and raw bytes
+	// of the value from which the string was formatted.
+	strs map[string]string
+}
+
+// NewStringInterner returns a new, empty, StringInterner.
+func NewStringInterner() *StringInterner {
+	return &StringInterner{strs: map[string]string{}}
+}
+
+// Len returns the number of distinct strings that are held by the interner.
+func (s *StringInterner) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.strs)
+}
+
+// WithStringInterner specifies a StringInterner that is used to deduplicate
+// the strings within the parsed LSP. The same interner should be supplied
+// to the parse of each LSP within an LSDB. The parsed LSP is identical to one
+// that is parsed without an interner.
+func WithStringInterner(s *StringInterner) ParseOption {
+	return func(o *parseOptions) {
+		o.interner = s
+	}
+}
+
+// internKind identifies the format of an interned string, such that the same
+// raw bytes formatted in different ways are interned separately.
+type internKind uint8
+
+const (
+	internHostname internKind = iota
+	internAreaAddress
+	internSystemID
+	internIPv4Prefix
+	internIPv6Prefix
+	internExpandedIPv6Prefix
+)
+
+// maxInternKeyLen is the length of the buffer that is allocated on the stack
+// when looking up an interned string. It is sufficient for all keys other
+// than long hostnames.
+const maxInternKeyLen = 32
+
+// intern returns the string that corresponds to the raw bytes b of the
+// specified kind, and the additional discriminator aux (e.g., a prefix
+// length). If the string has not previously been interned, it is created by
+// calling format. Since the lookup is performed using the raw bytes, format is
+// only called, and hence the string is only allocated, for the first
+// occurrence of each value. Returns an error if format does.
+func (s *StringInterner) intern(kind internKind, b []byte, aux uint8, format func() (string, error)) (string, error) {
+	var buf [maxInternKeyLen]byte
+	key := append(buf[:0], uint8(kind), aux)
+	key = append(key, b...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if str, ok := s.strs[string(key)]; ok {
+		return str, nil
+	}
+
+	str, err := format()
+	if err != nil {
+		return "", err
+	}
+	s.strs[string(key)] = str
+	return str, nil
+}
+
+// intern returns the string for the raw bytes b of the specified kind, using
+// the interner specified in the receiver's options. If no interner has been
+// specified, the string is created by calling format.
+func (i *isisLSP) intern(kind internKind, b []byte, aux uint8, format func() (string, error)) (string, error) {
+	if i.opts.interner == nil {
+		return format()
+	}
+	return i.opts.interner.intern(kind, b, aux, format)
+}
+
+// internSystemID returns the canonical string form of the system or LSP ID b,
+// using the interner specified in the receiver's options.
+func (i *isisLSP) internSystemID(b []byte) string {
+	s, _ := i.intern(internSystemID, b, 0, func() (string, error) {
+		return fastCanonicalHexString(b), nil
+	})
+	return s
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+func TestStringInterner(t *testing.T) {
+	tests := []struct {
+		name    string
+		inBytes []byte
+		inOpts  []ParseOption
+	}{{
+		name:    "lab example",
+		inBytes: exampleLSP1,
+	}, {
+		name:    "lab example with expanded IPv6",
+		inBytes: exampleLSP1,
+		inOpts:  []ParseOption{WithExpandIPv6(true)},
+	}, {
+		name:    "detailed example",
+		inBytes: exampleLSP2,
+	}, {
+		name:    "larger PDU",
+		inBytes: exampleLSP3,
+	}}
+
+	for _, tt := range tests {
+		want, _, wantErr := ISISBytesToLSP(tt.inBytes, 0, tt.inOpts...)
+
+		si := NewStringInterner()
+		opts := append([]ParseOption{WithStringInterner(si)}, tt.inOpts...)
+
+		first, _, err := ISISBytesToLSP(tt.inBytes, 0, opts...)
+		if (err == nil) != (wantErr == nil) {
+			t.Errorf("%s: ISISBytesToLSP(..., WithStringInterner): did not get expected error, got: %v, want: %v", tt.name, err, wantErr)
+		}
+		if diff := pretty.Compare(first, want); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(..., WithStringInterner): interning changed parsed LSP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		n := si.Len()
+		if n == 0 {
+			t.Errorf("%s: ISISBytesToLSP(..., WithStringInterner): no strings were interned", tt.name)
+		}
+
+		second, _, _ := ISISBytesToLSP(tt.inBytes, 0, opts...)
+		if diff := pretty.Compare(second, first); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(..., WithStringInterner): second parse differs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		// The second parse should find every string in the interner.
+		if got := si.Len(); got != n {
+			t.Errorf("%s: ISISBytesToLSP(..., WithStringInterner): second parse interned new strings, got: %d, want: %d", tt.name, got, n)
+		}
+	}
+}
+
+func TestStringInternerNeighborID(t *testing.T) {
+	si := NewStringInterner()
+	const wantNID = "0000.4000.ce3b.00"
+
+	var nids []string
+	for x := 0; x < 2; x++ {
+		lsp, _, _ := ISISBytesToLSP(exampleLSP2, 0, WithStringInterner(si))
+		for nid := range lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability().Neighbor {
+			if nid == wantNID {
+				nids = append(nids, nid)
+			}
+		}
+	}
+
+	if len(nids) != 2 {
+		t.Fatalf("did not get neighbor %s in both parses, got: %v", wantNID, nids)
+	}
+
+	if nids[0] != nids[1] {
+		t.Errorf("did not get equal neighbor IDs from two parses, got: %s and %s", nids[0], nids[1])
+	}
+}
+
+func BenchmarkStringInterner(b *testing.B) {
+	b.Run("no interning", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			ISISBytesToLSP(exampleLSP3, 0)
+		}
+	})
+
+	b.Run("interning", func(b *testing.B) {
+		si := NewStringInterner()
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			ISISBytesToLSP(exampleLSP3, 0, WithStringInterner(si))
+		}
+	})
+}
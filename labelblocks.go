@@ -0,0 +1,261 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/openconfig/gnmi/errlist"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// srlbSubTLVType is the type of the Segment Routing Local Block (SRLB)
+// sub-TLV of the Router Capability TLV (242), defined in RFC8667.
+const srlbSubTLVType uint8 = 22
+
+// LabelRange is a contiguous block of MPLS labels.
+type LabelRange struct {
+	// Start is the first label within the block.
+	Start uint32
+	// End is the last label within the block.
+	End uint32
+}
+
+// Size returns the number of labels within the block.
+func (l LabelRange) Size() uint32 {
+	return l.End - l.Start + 1
+}
+
+// newLabelRange returns the LabelRange that starts at start, and contains
+// size labels. Returns an error if the range is empty, or exceeds the 20-bit
+// MPLS label space.
+func newLabelRange(start, size uint32) (LabelRange, error) {
+	const maxLabel = 1<<20 - 1
+	if size == 0 {
+		return LabelRange{}, fmt.Errorf("invalid label range starting at %d, range is empty", start)
+	}
+	if start > maxLabel || size-1 > maxLabel-start {
+		return LabelRange{}, fmt.Errorf("invalid label range starting at %d with size %d, exceeds the MPLS label space", start, size)
+	}
+	return LabelRange{Start: start, End: start + size - 1}, nil
+}
+
+// parseSRLBSubTLV parses the Segment Routing Local Block sub-TLV (type 22) of
+// the Router Capability TLV, defined in RFC8667. The encoding of the sub-TLV
+// is parallel to the SR Capabilities sub-TLV:
+//
+//	1 octet - flags
+//	Repeated descriptor entries consisting of:
+//	  3 octets - range
+//	  SID/Label sub-TLV (type 1), containing a 3-octet first label
+//
+// Returns the label ranges within the SRLB, or an error if the sub-TLV is
+// invalid.
+func parseSRLBSubTLV(r *rawTLV) ([]LabelRange, error) {
+	if len(r.Value) < 1 {
+		return nil, fmt.Errorf("invalid length SRLB sub-TLV: %d", len(r.Value))
+	}
+
+	ranges := []LabelRange{}
+	for x := 1; x < len(r.Value); x += 8 {
+		if len(r.Value) < x+5 {
+			return nil, fmt.Errorf("invalid length of SRLB descriptor entry, overflows sub-TLV length")
+		}
+
+		if sidlType := r.Value[x+3]; sidlType != 1 {
+			return nil, fmt.Errorf("invalid SID/Label sub-TLV type in SRLB descriptor: %d", sidlType)
+		}
+
+		// The SRLB consists of labels, and hence the SID/Label sub-TLV must
+		// contain a 3-octet label.
+		if sidlLen := r.Value[x+4]; sidlLen != 3 {
+			return nil, fmt.Errorf("invalid length SRLB start: %d", sidlLen)
+		}
+
+		if len(r.Value) < x+8 {
+			return nil, fmt.Errorf("invalid length of SRLB descriptor entry, overflows sub-TLV length")
+		}
+
+		size, err := binaryToUint32([]byte{0, r.Value[x], r.Value[x+1], r.Value[x+2]})
+		if err != nil {
+			return nil, err
+		}
+
+		start, err := binaryToUint32([]byte{0, r.Value[x+5], r.Value[x+6], r.Value[x+7]})
+		if err != nil {
+			return nil, err
+		}
+
+		lr, err := newLabelRange(start, size)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, lr)
+	}
+	return ranges, nil
+}
+
+// LabelBlocks returns the Segment Routing Global Block (SRGB) and Segment
+// Routing Local Block (SRLB) label ranges that are advertised in the Router
+// Capability TLVs of the supplied LSP. The SRGB is extracted from the SR
+// Capabilities sub-TLV, and the SRLB from the undefined SRLB sub-TLV.
+// Ranges are returned in the order in which they are advertised. Returns an
+// error for each descriptor from which a concrete label range cannot be
+// determined.
+func LabelBlocks(lsp *oc.Lsp) (srgb, srlb []LabelRange, err error) {
+	tlv := lsp.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY)
+	if tlv == nil {
+		return nil, nil, nil
+	}
+
+	var insts []uint32
+	for n := range tlv.Capability {
+		insts = append(insts, n)
+	}
+	sort.Slice(insts, func(x, y int) bool { return insts[x] < insts[y] })
+
+	var pErr errlist.List
+	for _, n := range insts {
+		c := tlv.Capability[n]
+
+		g, err := srgbRanges(c.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_CAPABILITY).GetSegmentRoutingCapability())
+		if err != nil {
+			pErr.Add(err)
+		}
+		srgb = append(srgb, g...)
+
+		if u := c.GetUndefinedSubtlv(srlbSubTLVType); u != nil {
+			l, err := parseSRLBSubTLV(&rawTLV{Type: srlbSubTLVType, Length: uint8(len(u.Value)), Value: u.Value})
+			if err != nil {
+				pErr.Add(err)
+			}
+			srlb = append(srlb, l...)
+		}
+	}
+
+	return srgb, srlb, pErr.Err()
+}
+
+// srgbRanges returns the label ranges that are described by the SRGB
+// descriptors of the SR Capabilities sub-TLV s, ordered by descriptor.
+func srgbRanges(s *oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability) ([]LabelRange, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	var descrs []uint32
+	for n := range s.SrgbDescriptor {
+		descrs = append(descrs, n)
+	}
+	sort.Slice(descrs, func(x, y int) bool { return descrs[x] < descrs[y] })
+
+	var pErr errlist.List
+	var ranges []LabelRange
+	for _, n := range descrs {
+		d := s.SrgbDescriptor[n]
+		lbl, ok := d.Label.(*oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor_Label_Union_Uint32)
+		if !ok || d.Range == nil {
+			pErr.Add(fmt.Errorf("invalid SRGB descriptor %d, label or range is not specified", n))
+			continue
+		}
+
+		lr, err := newLabelRange(lbl.Uint32, *d.Range)
+		if err != nil {
+			pErr.Add(err)
+			continue
+		}
+		ranges = append(ranges, lr)
+	}
+	return ranges, pErr.Err()
+}
+
+// SRGBConflict describes a pair of systems whose Segment Routing Global
+// Blocks (SRGBs) contain overlapping label ranges.
+type SRGBConflict struct {
+	// SystemIDs are the system IDs of the two systems, in sorted order.
+	SystemIDs [2]string
+	// Ranges are the overlapping SRGB ranges of the systems, in the same
+	// order as SystemIDs.
+	Ranges [2]LabelRange
+	// Overlap is the range of labels that is common to both ranges.
+	Overlap LabelRange
+}
+
+// DetectSRGBConflicts returns the overlaps between the SRGB label ranges that
+// are advertised by different systems within the supplied LSPs. The LSPs of a
+// system are identified by their originating system ID, such that ranges
+// advertised by a single system, including in different fragments, are not
+// compared with each other. Ranges that cannot be decoded are ignored.
+// Conflicts are sorted by system IDs, and then by the start of each range.
+func DetectSRGBConflicts(lsps []*oc.Lsp) []SRGBConflict {
+	srgbs := map[string][]LabelRange{}
+	for _, lsp := range lsps {
+		id := OriginatingSystemID(lsp)
+		if id == "" {
+			continue
+		}
+		// Ranges that cannot be decoded are not returned by LabelBlocks,
+		// and hence the error can be ignored.
+		g, _, _ := LabelBlocks(lsp)
+		srgbs[id] = append(srgbs[id], g...)
+	}
+
+	var ids []string
+	for id := range srgbs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var conflicts []SRGBConflict
+	for x := 0; x < len(ids); x++ {
+		for y := x + 1; y < len(ids); y++ {
+			for _, a := range srgbs[ids[x]] {
+				for _, b := range srgbs[ids[y]] {
+					if a.Start > b.End || b.Start > a.End {
+						continue
+					}
+					o := LabelRange{Start: a.Start, End: a.End}
+					if b.Start > o.Start {
+						o.Start = b.Start
+					}
+					if b.End < o.End {
+						o.End = b.End
+					}
+					conflicts = append(conflicts, SRGBConflict{
+						SystemIDs: [2]string{ids[x], ids[y]},
+						Ranges:    [2]LabelRange{a, b},
+						Overlap:   o,
+					})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		ci, cj := conflicts[i], conflicts[j]
+		if ci.SystemIDs != cj.SystemIDs {
+			if ci.SystemIDs[0] != cj.SystemIDs[0] {
+				return ci.SystemIDs[0] < cj.SystemIDs[0]
+			}
+			return ci.SystemIDs[1] < cj.SystemIDs[1]
+		}
+		if ci.Ranges[0].Start != cj.Ranges[0].Start {
+			return ci.Ranges[0].Start < cj.Ranges[0].Start
+		}
+		return ci.Ranges[1].Start < cj.Ranges[1].Start
+	})
+	return conflicts
+}
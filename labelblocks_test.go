@@ -0,0 +1,233 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestLabelBlocks(t *testing.T) {
+	// lspHeader is the LSP ID, sequence number, checksum and flags of the
+	// LSPs used in the test.
+	lspHeader := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+
+	// srgb is an SR Capabilities sub-TLV with an SRGB of 8000 labels
+	// starting at 16000.
+	srgb := []byte{2, 9, 0xC0, 0x00, 0x1F, 0x40, 1, 3, 0x00, 0x3E, 0x80}
+	// srlb is an SRLB sub-TLV with a block of 1000 labels starting at
+	// 15000.
+	srlb := []byte{22, 9, 0x00, 0x00, 0x03, 0xE8, 1, 3, 0x00, 0x3A, 0x98}
+
+	capTLV := func(subTLVs ...[]byte) []byte {
+		v := []byte{192, 0, 2, 1, 0}
+		for _, s := range subTLVs {
+			v = append(v, s...)
+		}
+		return append([]byte{242, uint8(len(v))}, v...)
+	}
+
+	tests := []struct {
+		name             string
+		inTLVs           []byte
+		inLSP            *oc.Lsp
+		wantSRGB         []LabelRange
+		wantSRLB         []LabelRange
+		wantParseErr     bool
+		wantErrSubstring string
+	}{{
+		name:     "SRGB and SRLB",
+		inTLVs:   capTLV(srgb, srlb),
+		wantSRGB: []LabelRange{{Start: 16000, End: 23999}},
+		wantSRLB: []LabelRange{{Start: 15000, End: 15999}},
+	}, {
+		name:     "multiple SRLB ranges",
+		inTLVs:   capTLV(srgb, []byte{22, 17, 0x00, 0x00, 0x00, 0x64, 1, 3, 0x00, 0x3A, 0x98, 0x00, 0x00, 0x0A, 1, 3, 0x01, 0x86, 0xA0}),
+		wantSRGB: []LabelRange{{Start: 16000, End: 23999}},
+		wantSRLB: []LabelRange{
+			{Start: 15000, End: 15099},
+			{Start: 100000, End: 100009},
+		},
+	}, {
+		name:     "SRGB only",
+		inTLVs:   capTLV(srgb),
+		wantSRGB: []LabelRange{{Start: 16000, End: 23999}},
+	}, {
+		name:         "SRLB with index",
+		inTLVs:       capTLV(srgb, []byte{22, 10, 0x00, 0x00, 0x03, 0xE8, 1, 4, 0x00, 0x00, 0x3A, 0x98}),
+		wantSRGB:     []LabelRange{{Start: 16000, End: 23999}},
+		wantParseErr: true,
+	}, {
+		name:   "no capability TLV",
+		inTLVs: []byte{137, 2, 'r', '1'},
+	}, {
+		name: "SRGB exceeding label space",
+		inLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY: {
+					Capability: map[uint32]*oc.Lsp_Tlv_Capability{
+						0: {
+							Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Capability_Subtlv{
+								oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_CAPABILITY: {
+									SegmentRoutingCapability: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability{
+										SrgbDescriptor: map[uint32]*oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor{
+											0: {
+												Label: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor_Label_Union_Uint32{Uint32: 1048000},
+												Range: ygot.Uint32(1000),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		wantErrSubstring: "exceeds the MPLS label space",
+	}}
+
+	for _, tt := range tests {
+		lsp := tt.inLSP
+		if lsp == nil {
+			var err error
+			lsp, _, err = ISISBytesToLSP(append(append([]byte{}, lspHeader...), tt.inTLVs...), 0)
+			if (err != nil) != tt.wantParseErr {
+				t.Errorf("%s: ISISBytesToLSP(...): got unexpected error status, got: %v, wantErr: %v", tt.name, err, tt.wantParseErr)
+				continue
+			}
+		}
+
+		gotSRGB, gotSRLB, err := LabelBlocks(lsp)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: LabelBlocks(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if diff := pretty.Compare(gotSRGB, tt.wantSRGB); diff != "" {
+			t.Errorf("%s: LabelBlocks(...): did not get expected SRGB, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		if diff := pretty.Compare(gotSRLB, tt.wantSRLB); diff != "" {
+			t.Errorf("%s: LabelBlocks(...): did not get expected SRLB, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestParseSRLBSubTLV(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               []byte
+		want             []LabelRange
+		wantErrSubstring string
+	}{{
+		name: "single range",
+		in:   []byte{0x00, 0x00, 0x03, 0xE8, 1, 3, 0x00, 0x3A, 0x98},
+		want: []LabelRange{{Start: 15000, End: 15999}},
+	}, {
+		name: "flags only",
+		in:   []byte{0x00},
+		want: []LabelRange{},
+	}, {
+		name:             "SID/Label sub-TLV with short length",
+		in:               []byte{0x00, 0x00, 0x03, 0xE8, 1, 2, 0x3A, 0x98},
+		wantErrSubstring: "invalid length SRLB start: 2",
+	}, {
+		name:             "SID/Label sub-TLV overflowing the sub-TLV",
+		in:               []byte{0x00, 0x00, 0x03, 0xE8, 1, 3, 0x00, 0x3A},
+		wantErrSubstring: "overflows sub-TLV length",
+	}, {
+		name:             "invalid SID/Label sub-TLV type",
+		in:               []byte{0x00, 0x00, 0x03, 0xE8, 2, 3, 0x00, 0x3A, 0x98},
+		wantErrSubstring: "invalid SID/Label sub-TLV type",
+	}, {
+		name:             "empty sub-TLV",
+		in:               []byte{},
+		wantErrSubstring: "invalid length SRLB sub-TLV",
+	}}
+
+	for _, tt := range tests {
+		got, err := parseSRLBSubTLV(&rawTLV{Type: srlbSubTLVType, Length: uint8(len(tt.in)), Value: tt.in})
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: parseSRLBSubTLV(%v): did not get expected error, %s", tt.name, tt.in, diff)
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: parseSRLBSubTLV(%v): did not get expected ranges, diff(-got,+want):\n%s", tt.name, tt.in, diff)
+		}
+	}
+}
+
+func TestLabelRangeSize(t *testing.T) {
+	if got, want := (LabelRange{Start: 16000, End: 23999}).Size(), uint32(8000); got != want {
+		t.Errorf("LabelRange.Size(): got: %d, want: %d", got, want)
+	}
+}
+
+func TestDetectSRGBConflicts(t *testing.T) {
+	// srgbLSP returns an LSP originated by the system with the last octet
+	// sysID, advertising an SRGB of size labels starting at start.
+	srgbLSP := func(sysID byte, start, size uint32) *oc.Lsp {
+		b := []byte{0, 0, 0x40, 0, 0xce, sysID, 0, 0, 0, 0, 0, 1, 0, 0, 0x03,
+			242, 16, 192, 0, 2, sysID, 0,
+			2, 9, 0xC0, byte(size >> 16), byte(size >> 8), byte(size), 1, 3, byte(start >> 16), byte(start >> 8), byte(start),
+		}
+		lsp, _, err := ISISBytesToLSP(b, 0)
+		if err != nil {
+			t.Fatalf("ISISBytesToLSP(%v): got unexpected error: %v", b, err)
+		}
+		return lsp
+	}
+
+	tests := []struct {
+		name  string
+		inLSP []*oc.Lsp
+		want  []SRGBConflict
+	}{{
+		name:  "overlapping SRGBs",
+		inLSP: []*oc.Lsp{srgbLSP(0x39, 16000, 8000), srgbLSP(0x3a, 20000, 8000)},
+		want: []SRGBConflict{{
+			SystemIDs: [2]string{"0000.4000.ce39", "0000.4000.ce3a"},
+			Ranges:    [2]LabelRange{{Start: 16000, End: 23999}, {Start: 20000, End: 27999}},
+			Overlap:   LabelRange{Start: 20000, End: 23999},
+		}},
+	}, {
+		name:  "identical SRGBs",
+		inLSP: []*oc.Lsp{srgbLSP(0x3a, 16000, 8000), srgbLSP(0x39, 16000, 8000)},
+		want: []SRGBConflict{{
+			SystemIDs: [2]string{"0000.4000.ce39", "0000.4000.ce3a"},
+			Ranges:    [2]LabelRange{{Start: 16000, End: 23999}, {Start: 16000, End: 23999}},
+			Overlap:   LabelRange{Start: 16000, End: 23999},
+		}},
+	}, {
+		name:  "non-overlapping SRGBs",
+		inLSP: []*oc.Lsp{srgbLSP(0x39, 16000, 8000), srgbLSP(0x3a, 24000, 8000)},
+	}, {
+		name:  "same system in multiple LSPs",
+		inLSP: []*oc.Lsp{srgbLSP(0x39, 16000, 8000), srgbLSP(0x39, 20000, 8000)},
+	}, {
+		name:  "LSP without SRGB",
+		inLSP: []*oc.Lsp{srgbLSP(0x39, 16000, 8000), {LspId: ygot.String("0000.4000.ce3a.00-00")}},
+	}}
+
+	for _, tt := range tests {
+		if diff := pretty.Compare(DetectSRGBConflicts(tt.inLSP), tt.want); diff != "" {
+			t.Errorf("%s: DetectSRGBConflicts(...): did not get expected conflicts, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
@@ -18,6 +18,7 @@
 package lsdbparse
 
 import (
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -44,6 +45,309 @@ type isisLSP struct {
 	// rawTLVs is the set of the TLVs that are included within the
 	// LSP as raw bytes.
 	rawTLVs []*rawTLV
+	// opts is the set of options that control how the LSP is parsed.
+	opts parseOptions
+	// tlvType is the type of the TLV that is currently being processed.
+	tlvType uint8
+	// isReachInstances stores the next instance ID to be allocated for
+	// each neighbor in the extended IS reachability TLV, keyed by the
+	// neighbor ID.
+	isReachInstances map[string]uint64
+	// strictErrs stores the errors that are only reported when parsing
+	// strictly, such as unknown sub-TLVs.
+	strictErrs []error
+}
+
+// parseOptions stores the options that modify the behaviour of ISISBytesToLSP.
+type parseOptions struct {
+	// expandIPv6 specifies that IPv6 addresses should be stored in their
+	// fully expanded form, rather than zero-compressed.
+	expandIPv6 bool
+	// checkRouterIDs specifies that a warning should be returned when the
+	// IPv4 TE router ID and Router Capability router ID disagree.
+	checkRouterIDs bool
+	// interner is the cache used to deduplicate strings between LSPs. If
+	// nil, strings are not interned.
+	interner *StringInterner
+	// hostnameMode specifies how multiple Dynamic Hostname TLVs are stored.
+	hostnameMode HostnameMode
+	// rawTLVs specifies whether TLVs with types in the range rawTLVLo to
+	// rawTLVHi, inclusive, are stored as undefined TLVs without parsing.
+	rawTLVs            bool
+	rawTLVLo, rawTLVHi uint8
+	// maxLSPBytes is the maximum length of LSP that is parsed. If zero,
+	// the length of the LSP is not limited.
+	maxLSPBytes int
+	// checkSeqNum specifies that a warning should be returned when the
+	// sequence number of the LSP is zero.
+	checkSeqNum bool
+	// tlvTimings, if non-nil, accumulates the time taken to decode each
+	// TLV type.
+	tlvTimings map[uint8]time.Duration
+	// stats, if non-nil, accumulates statistics about the parsed LSP.
+	stats *ParseStats
+	// checkUnusableMetric specifies that a warning should be returned for
+	// each extended IPv4 prefix advertised with the unusable metric.
+	checkUnusableMetric bool
+	// remainingLifetime, if non-nil, is the remaining lifetime of the LSP,
+	// which is not included in the bytes supplied to ISISBytesToLSP.
+	remainingLifetime *uint16
+	// strictPDULength specifies that an error is returned when the input
+	// does not match the PDU length field of the LSP header.
+	strictPDULength bool
+	// timestampTLV specifies that TLVs of type timestampTLVType carry a
+	// vendor timestamp, and are stored as undefined TLVs without parsing.
+	timestampTLV     bool
+	timestampTLVType uint8
+	// checkChecksum specifies that a warning should be returned when the
+	// checksum of the LSP does not match its contents.
+	checkChecksum bool
+	// fatalTLVTypes is the set of TLV types for which non-fatal errors
+	// are treated as fatal.
+	fatalTLVTypes map[uint8]bool
+	// strict specifies that non-fatal errors, and unknown sub-TLVs, are
+	// treated as fatal.
+	strict bool
+	// discardUnknownTLVs specifies that TLVs of types that are not
+	// supported are discarded, rather than stored as undefined TLVs.
+	discardUnknownTLVs bool
+}
+
+// isRawTLV returns true if TLVs of type t should be stored as undefined TLVs
+// without being parsed.
+func (o *parseOptions) isRawTLV(t uint8) bool {
+	if o.timestampTLV && t == o.timestampTLVType {
+		return true
+	}
+	return o.rawTLVs && t >= o.rawTLVLo && t <= o.rawTLVHi
+}
+
+// isUndefinedTLV returns true if TLVs of type t, with contents value, are
+// stored as undefined TLVs by ISISBytesToLSP.
+func (o *parseOptions) isUndefinedTLV(t uint8, value []byte) bool {
+	if o.isRawTLV(t) {
+		return true
+	}
+	if _, ok := processTLVMap[t]; !ok {
+		return !o.discardUnknownTLVs
+	}
+	return t == multiTopologyTLVType && mtOverloadAndAttached(value)
+}
+
+// ParseOption is an option that modifies the behaviour of ISISBytesToLSP.
+type ParseOption func(*parseOptions)
+
+// WithExpandIPv6 specifies whether IPv6 addresses and prefixes are stored in
+// their fully expanded form (e.g., 2001:0db8:0000:0000:0000:0000:0000:0001)
+// rather than Go's canonical zero-compressed form (e.g., 2001:db8::1), which
+// is used by default.
+func WithExpandIPv6(expand bool) ParseOption {
+	return func(o *parseOptions) {
+		o.expandIPv6 = expand
+	}
+}
+
+// WithRouterIDConsistencyCheck specifies whether the IPv4 TE router ID (TLV
+// 134) is checked against the router ID advertised in the Router Capability
+// TLV (242). Where both are present and they disagree, a non-fatal error is
+// returned, since a mismatch indicates a configuration or decode problem.
+func WithRouterIDConsistencyCheck(check bool) ParseOption {
+	return func(o *parseOptions) {
+		o.checkRouterIDs = check
+	}
+}
+
+// HostnameMode specifies how the hostnames of an LSP that contains multiple
+// Dynamic Hostname TLVs (137) are stored.
+type HostnameMode int
+
+const (
+	// HostnameKeepAll stores the hostname from every Dynamic Hostname TLV,
+	// in the order in which they appear in the LSP. It is the default.
+	HostnameKeepAll HostnameMode = iota
+	// HostnameFirst stores only the hostname from the first Dynamic
+	// Hostname TLV in the LSP.
+	HostnameFirst
+	// HostnameLast stores only the hostname from the last Dynamic Hostname
+	// TLV in the LSP.
+	HostnameLast
+)
+
+// WithHostnameMode specifies how multiple Dynamic Hostname TLVs are stored.
+// RFC5301 expects a single hostname per system, such that multiple TLVs
+// typically indicate concatenated fragments or an encoding error. Regardless
+// of the mode, a non-fatal error is returned when more than one distinct
+// hostname is found. By default, all hostnames are kept.
+func WithHostnameMode(m HostnameMode) ParseOption {
+	return func(o *parseOptions) {
+		o.hostnameMode = m
+	}
+}
+
+// WithRawTLVRange specifies a range of TLV types, lo to hi inclusive, that are
+// expected within the LSP but are opaque, such as experimental or vendor
+// private TLVs. TLVs with types in the range are stored as undefined TLVs
+// without being parsed, and without an error being returned, unless parsing
+// strictly. TLV types outside of the range are handled as normal.
+func WithRawTLVRange(lo, hi uint8) ParseOption {
+	return func(o *parseOptions) {
+		o.rawTLVs = true
+		o.rawTLVLo, o.rawTLVHi = lo, hi
+	}
+}
+
+// WithMaxLSPBytes specifies the maximum length, in bytes, of the LSP that is
+// parsed, excluding any bytes that are discarded by the offset. Longer inputs
+// are rejected before any processing is performed. IS-IS LSPs are limited to
+// the originating system's LSP buffer size, typically 1492 bytes, such that a
+// much larger input is suspect. By default, or where n is not positive, the
+// length is not limited.
+func WithMaxLSPBytes(n int) ParseOption {
+	return func(o *parseOptions) {
+		o.maxLSPBytes = n
+	}
+}
+
+// WithSequenceNumberCheck specifies whether a non-fatal error is returned when
+// the sequence number of the LSP is zero. Valid sequence numbers begin at 1,
+// such that a zero sequence number typically indicates that the LSP is corrupt,
+// or that the offset supplied does not align with the start of the LSP ID.
+func WithSequenceNumberCheck(check bool) ParseOption {
+	return func(o *parseOptions) {
+		o.checkSeqNum = check
+	}
+}
+
+// WithUnusableMetricCheck specifies whether a non-fatal error is returned for
+// each prefix in the Extended IPv4 Reachability TLV (135) that is advertised
+// with a metric larger than MAX_PATH_METRIC (0xFE000000, RFC5305). Such
+// prefixes are advertised, but must not be used for SPF, and are stored with
+// their metric unchanged.
+func WithUnusableMetricCheck(check bool) ParseOption {
+	return func(o *parseOptions) {
+		o.checkUnusableMetric = check
+	}
+}
+
+// WithChecksumCheck specifies whether a non-fatal error is returned when the
+// checksum stored in the LSP does not match the checksum computed over its
+// contents, as is the case for an LSP that has been corrupted. The checksum
+// is computed over the bytes supplied to ISISBytesToLSP from the LSP ID. As
+// per ISO10589, the checksum of a purge is not checked, since the contents of
+// a purge are removed, other than TLVs such as authentication (RFC5304) that
+// are retained, and its checksum is not required to be recomputed. An LSP is
+// only known to be a purge when its remaining lifetime is supplied, such as
+// by ISISPDUToLSP.
+func WithChecksumCheck(check bool) ParseOption {
+	return func(o *parseOptions) {
+		o.checkChecksum = check
+	}
+}
+
+// WithFatalTLVTypes specifies TLV types for which any non-fatal error that is
+// encountered whilst parsing the TLV is treated as fatal, such that
+// ISISBytesToLSP stops parsing and does not return the LSP. Errors in TLVs of
+// other types remain non-fatal. By default, no TLV types are fatal.
+func WithFatalTLVTypes(types ...uint8) ParseOption {
+	return func(o *parseOptions) {
+		if o.fatalTLVTypes == nil {
+			o.fatalTLVTypes = map[uint8]bool{}
+		}
+		for _, t := range types {
+			o.fatalTLVTypes[t] = true
+		}
+	}
+}
+
+// WithStrictParsing specifies whether ISISBytesToLSP treats the LSP as invalid
+// when any error is encountered, rather than returning the partially parsed
+// LSP along with the errors. When parsing strictly, a sub-TLV of a type that
+// is not supported is also an error, as is a repeated TLV within a range
+// specified by WithRawTLVRange that cannot be stored. By default, only errors that prevent any
+// parsing of the LSP are fatal, and unsupported sub-TLVs are stored as
+// undefined sub-TLVs.
+func WithStrictParsing(strict bool) ParseOption {
+	return func(o *parseOptions) {
+		o.strict = strict
+	}
+}
+
+// WithDiscardUnknownTLVs specifies whether TLVs of types that are not supported
+// by the parser are discarded, rather than stored as undefined TLVs within the
+// LSP, which is the default. TLVs that are stored as undefined TLVs due to
+// WithRawTLVRange or WithTimestampTLV are not discarded.
+func WithDiscardUnknownTLVs(discard bool) ParseOption {
+	return func(o *parseOptions) {
+		o.discardUnknownTLVs = discard
+	}
+}
+
+// WithRemainingLifetime specifies the remaining lifetime, in seconds, of the
+// LSP that is being parsed. Since the remaining lifetime field precedes the
+// LSP ID, it is not parsed by ISISBytesToLSP, and can instead be supplied such
+// that it is stored in the returned LSP. A remaining lifetime of 0, which
+// indicates that the LSP has been purged, is stored, and can be distinguished
+// from a remaining lifetime that was not supplied.
+func WithRemainingLifetime(secs uint16) ParseOption {
+	return func(o *parseOptions) {
+		o.remainingLifetime = ygot.Uint16(secs)
+	}
+}
+
+// WithTLVTimings specifies a map into which the time taken to decode each TLV
+// of the LSP is accumulated, keyed by TLV type. Where an LSP contains more
+// than one TLV of a type, the sum of their decode times is recorded. It is
+// intended for performance investigation, and does not change the LSP that
+// is returned. The map is written without synchronisation, and hence must
+// not be shared between concurrent calls to ISISBytesToLSP.
+func WithTLVTimings(timings map[uint8]time.Duration) ParseOption {
+	return func(o *parseOptions) {
+		o.tlvTimings = timings
+	}
+}
+
+// ParseStats contains statistics that are accumulated whilst parsing LSPs,
+// and is populated by supplying the WithParseStats option.
+type ParseStats struct {
+	// UnknownSubTLVs is the number of sub-TLVs of unknown type that were
+	// encountered, keyed by the type of the TLV that contains them.
+	UnknownSubTLVs map[uint8]int
+}
+
+// WithParseStats specifies a ParseStats into which statistics about the LSP
+// are accumulated, such that a single ParseStats can be used to summarise the
+// contents of many LSPs. As per WithTLVTimings, the statistics are written
+// without synchronisation, and hence must not be shared between concurrent
+// calls to ISISBytesToLSP.
+func WithParseStats(s *ParseStats) ParseOption {
+	return func(o *parseOptions) {
+		o.stats = s
+	}
+}
+
+// WithStrictPDULength specifies whether ISISBytesToLSP rejects input that
+// does not match the PDU length field of the LSP header, such as a buffer with
+// trailing padding or a second PDU after the LSP. The PDU length is only
+// available when the input is a standard IS-IS PDU, i.e., the offset supplied
+// is at least LSPIDOffset, otherwise this option has no effect. By default,
+// all bytes after the LSP header are parsed as TLVs.
+func WithStrictPDULength(strict bool) ParseOption {
+	return func(o *parseOptions) {
+		o.strictPDULength = strict
+	}
+}
+
+// WithTimestampTLV specifies that TLVs of type t are a vendor-specific TLV that
+// carries the time at which the LSP was captured. The TLV is stored as an
+// undefined TLV without being parsed, such that its contents are preserved
+// regardless of their format. The timestamp can be retrieved using
+// LSPTimestamp, or used for rendered notifications by setting the TimestampTLV
+// field of ISISRenderArgs.
+func WithTimestampTLV(t uint8) ParseOption {
+	return func(o *parseOptions) {
+		o.timestampTLV = true
+		o.timestampTLVType = t
+	}
 }
 
 // newISISLSP is a helper function that creates an internal isisLSP
@@ -65,8 +369,11 @@ func newISISLSP() *isisLSP {
 func ISISBytesToLSPIDSeqNum(lspBytes []byte, offset int) (string, uint32, error) {
 	lspBytes = lspBytes[offset:]
 
-	if len(lspBytes) < 16 {
-		return "", 0, fmt.Errorf("invalid LSP data provided, need at least 16 bytes, got %d bytes", len(lspBytes))
+	// The fixed portion of the LSP consists of the LSP ID (8 bytes), sequence
+	// number (4 bytes), checksum (2 bytes) and flags (1 byte). An LSP with no
+	// TLVs, such as a purge, consists of only these fields.
+	if len(lspBytes) < 15 {
+		return "", 0, fmt.Errorf("invalid LSP data provided, need at least 15 bytes, got %d bytes", len(lspBytes))
 	}
 	lspid := fmt.Sprintf("%s-%s", canonicalHexString(lspBytes[0:7]), canonicalHexString([]byte{lspBytes[7]}))
 	seq, err := binaryToUint32(lspBytes[8:12])
@@ -87,7 +394,31 @@ func ISISBytesToLSPIDSeqNum(lspBytes []byte, offset int) (string, uint32, error)
 // populated indicating that the LSP's contents were not completely succesfully parsed.
 // This function is specifically for Cisco IOS XR devices, since it handles the case
 // where a number of fields of the LSP are not included within the byte slice.
-func ISISBytesToLSP(lspBytes []byte, offset int) (*oc.Lsp, bool, error) {
+// ISISPDUToLSP should be used to parse a complete PDU captured from the wire.
+// The ParseOptions supplied modify the behaviour of the parsing.
+func ISISBytesToLSP(lspBytes []byte, offset int, opts ...ParseOption) (*oc.Lsp, bool, error) {
+	i := newISISLSP()
+	for _, o := range opts {
+		o(&i.opts)
+	}
+	return i.parse(lspBytes, offset, nil)
+}
+
+// parse parses the LSP contained in lspBytes, discarding the first offset
+// bytes, into the LSP stored in i, as described by ISISBytesToLSP. If scratch
+// is non-nil, it is used to store the TLVs of the LSP, rather than allocating
+// new storage.
+func (i *isisLSP) parse(lspBytes []byte, offset int, scratch *tlvScratch) (*oc.Lsp, bool, error) {
+	if n := i.opts.maxLSPBytes; n > 0 && len(lspBytes)-offset > n {
+		return nil, false, fmt.Errorf("invalid LSP data provided, length %d exceeds maximum of %d bytes", len(lspBytes)-offset, n)
+	}
+
+	if i.opts.strictPDULength && offset >= LSPIDOffset {
+		if err := checkPDULength(lspBytes, offset); err != nil {
+			return nil, false, err
+		}
+	}
+
 	lspid, seq, err := ISISBytesToLSPIDSeqNum(lspBytes, offset)
 	if err != nil {
 		return nil, false, err
@@ -100,21 +431,42 @@ func ISISBytesToLSP(lspBytes []byte, offset int) (*oc.Lsp, bool, error) {
 		return nil, false, err
 	}
 
-	tlvs, err := TLVBytesToTLVs(lspBytes[15:])
+	// The values of the TLVs, and their sub-TLVs, are sub-slices of the
+	// bytes that they are parsed from, and may be retained in the model.
+	// Copy the TLVs once here, such that the LSP does not refer to the
+	// caller's buffer.
+	tlvs, err := scratch.tlvs(append([]byte(nil), lspBytes[15:]...))
 	if err != nil {
 		return nil, false, fmt.Errorf("invalid TLVs in LSP: %v", err)
 	}
 
-	i := newISISLSP()
 	i.LSP.LspId = ygot.String(lspid)
 	i.LSP.SequenceNumber = ygot.Uint32(seq)
 	i.LSP.Checksum = ygot.Uint16(uint16(checksum))
 	i.LSP.Flags = parseLSPFlags(lspBytes[14])
+	// The IS type is carried in the two least significant bits of the
+	// flags field.
+	i.LSP.IsType = ygot.Uint8(lspBytes[14] & (bit6 | bit7))
+	if l := i.opts.remainingLifetime; l != nil {
+		i.LSP.RemainingLifetime = ygot.Uint16(*l)
+	}
 
 	i.rawTLVs = tlvs
 
 	var pErr errlist.List
+	if i.opts.checkSeqNum && seq == 0 {
+		pErr.Add(fmt.Errorf("invalid sequence number 0 in LSP %s", lspid))
+	}
+	if i.opts.checkChecksum && !IsPurgedLSP(i.LSP) {
+		if want := fletcherChecksum(lspBytes, lspChecksumOffset); uint16(checksum) != want {
+			pErr.Add(fmt.Errorf("invalid checksum in LSP %s, got: %#04x, computed: %#04x", lspid, checksum, want))
+		}
+	}
+
 	if err := i.processTLVs(); err != nil {
+		if e, ok := err.(*fatalTLVError); ok {
+			return nil, false, e
+		}
 		if e, ok := err.(errlist.Error); ok {
 			pErr.Add(e.Errors()...)
 		} else {
@@ -126,9 +478,163 @@ func ISISBytesToLSP(lspBytes []byte, offset int) (*oc.Lsp, bool, error) {
 	// model.
 	//pErr.Add(i.LSP.Validate().(util.Errors))
 
+	if i.opts.strict {
+		pErr.Add(i.strictErrs...)
+		if err := pErr.Err(); err != nil {
+			return nil, false, err
+		}
+	}
+
 	return i.LSP, true, pErr.Err()
 }
 
+// ISISBytesToLSPOpts specifies the behaviour of ISISBytesToLSPWithOpts. The
+// zero value parses an LSP starting at the LSP ID field, without validating
+// its checksum, and stores TLVs of unsupported types as undefined TLVs, as per
+// ISISBytesToLSP.
+type ISISBytesToLSPOpts struct {
+	// Offset is the number of bytes prior to the LSP ID field that are
+	// discarded.
+	Offset int
+	// ValidateChecksum specifies that an error is returned when the
+	// checksum of the LSP does not match its contents, as per
+	// WithChecksumCheck.
+	ValidateChecksum bool
+	// Strict specifies that the LSP is not returned when any error is
+	// encountered, as per WithStrictParsing.
+	Strict bool
+	// DiscardUnknown specifies that TLVs of unsupported types are discarded,
+	// rather than being stored as undefined TLVs within the LSP, as per
+	// WithDiscardUnknownTLVs.
+	DiscardUnknown bool
+}
+
+// ISISBytesToLSPWithOpts takes an input slice of bytes that contain an IS-IS
+// LSP and parses it as per ISISBytesToLSP, with the behaviour specified by
+// opts. The ParseOptions supplied are applied after those derived from opts,
+// and hence take precedence over them.
+func ISISBytesToLSPWithOpts(lspBytes []byte, opts ISISBytesToLSPOpts, popts ...ParseOption) (*oc.Lsp, bool, error) {
+	o := []ParseOption{
+		WithChecksumCheck(opts.ValidateChecksum),
+		WithStrictParsing(opts.Strict),
+		WithDiscardUnknownTLVs(opts.DiscardUnknown),
+	}
+	return ISISBytesToLSP(lspBytes, opts.Offset, append(o, popts...)...)
+}
+
+// ParseTLV parses a single TLV, of type tlvType, with contents value, and adds
+// its contents to the existing LSP supplied. It allows an LSP to be decoded one
+// TLV at a time, for example, when it is being reconstructed from a partial
+// capture. TLVs are stored as they are by ISISBytesToLSP, such that TLV types
+// that are not supported are stored as undefined TLVs within the LSP unless
+// WithDiscardUnknownTLVs is supplied. The ParseOptions supplied modify the
+// behaviour of the parsing. Returns an error if the TLV cannot be parsed.
+func ParseTLV(lsp *oc.Lsp, tlvType uint8, value []byte, opts ...ParseOption) error {
+	if lsp == nil {
+		return fmt.Errorf("cannot parse TLV into nil LSP")
+	}
+
+	if len(value) > 255 {
+		return fmt.Errorf("invalid TLV value length %d, must be less than 256 bytes", len(value))
+	}
+
+	i := &isisLSP{LSP: lsp}
+	for _, o := range opts {
+		o(&i.opts)
+	}
+
+	r := &rawTLV{
+		Type:   tlvType,
+		Length: uint8(len(value)),
+		Value:  value,
+	}
+
+	return i.processTLV(r)
+}
+
+// ParseTLVStream takes an input slice of bytes that contain an IS-IS LSP starting
+// at the LSP ID field, discarding the first offset bytes, and calls fn with the
+// type and value of each TLV within the LSP in turn. Unlike ISISBytesToLSP, it
+// does not construct the OpenConfig model for the LSP, and hence can be used
+// where only a small number of fields are required from each LSP. The value
+// supplied to fn is a sub-slice of lspBytes, and must be copied if it is to be
+// retained after fn returns. Returns an error if the TLVs cannot be extracted,
+// or the first error that is returned by fn, at which point parsing stops.
+func ParseTLVStream(lspBytes []byte, offset int, fn func(tlvType uint8, value []byte) error) error {
+	if offset < 0 || offset > len(lspBytes) {
+		return fmt.Errorf("invalid offset %d for LSP data of length %d", offset, len(lspBytes))
+	}
+	lspBytes = lspBytes[offset:]
+
+	if len(lspBytes) < 15 {
+		return fmt.Errorf("invalid LSP data provided, need at least 15 bytes, got %d bytes", len(lspBytes))
+	}
+
+	// Errors returned by fn are returned to the caller unmodified, such that
+	// they can be distinguished from errors in the framing of the TLVs.
+	var fnErr error
+	err := walkTLVs(lspBytes[15:], func(tlvType uint8, value []byte) error {
+		fnErr = fn(tlvType, value)
+		return fnErr
+	})
+	switch {
+	case fnErr != nil:
+		return fnErr
+	case err != nil:
+		return fmt.Errorf("invalid TLVs in LSP: %v", err)
+	}
+	return nil
+}
+
+// firstTLVValue returns the value of the first TLV of type t within the IS-IS
+// LSP contained in lspBytes, starting at the LSP ID field, discarding the first
+// offset bytes. The value is a sub-slice of lspBytes. Returns nil if the LSP
+// does not contain a TLV of type t, or an error if the TLVs cannot be
+// extracted.
+func firstTLVValue(lspBytes []byte, offset int, t uint8) ([]byte, error) {
+	var v []byte
+	err := ParseTLVStream(lspBytes, offset, func(tlvType uint8, value []byte) error {
+		if tlvType == t && v == nil {
+			v = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// UndefinedTLVs takes an input slice of bytes that contain an IS-IS LSP
+// starting at the LSP ID field, discarding the first offset bytes, and returns
+// the values of the TLVs that ISISBytesToLSP stores as undefined TLVs, keyed
+// by TLV type. Since the OpenConfig model stores only a single undefined TLV
+// of each type, it can be used to retrieve every instance of TLVs, such as
+// vendor-private TLVs, that are repeated within the LSP. Values are returned
+// in the order in which they appear in the LSP. The ParseOptions supplied
+// modify the set of TLVs that are considered undefined, as they do for
+// ISISBytesToLSP. Returns an error if
+// the TLVs cannot be extracted.
+func UndefinedTLVs(lspBytes []byte, offset int, opts ...ParseOption) (map[uint8][][]byte, error) {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	tlvs := map[uint8][][]byte{}
+	err := ParseTLVStream(lspBytes, offset, func(tlvType uint8, value []byte) error {
+		if !o.isUndefinedTLV(tlvType, value) {
+			return nil
+		}
+		tlvs[tlvType] = append(tlvs[tlvType], append([]byte{}, value...))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tlvs, nil
+}
+
 // ISISRenderArgs provides the arguments to the RenderNotifications functions,
 // and provides the context for outputting an IS-IS LSP.
 type ISISRenderArgs struct {
@@ -143,22 +649,120 @@ type ISISRenderArgs struct {
 	// UsePathElem specifies whether gNMI paths using the PathElem field should be
 	// produced.
 	UsePathElem bool
+	// ExpandIPv6 specifies whether IPv6 addresses and prefixes should be
+	// rendered in their fully expanded form, rather than Go's canonical
+	// zero-compressed form.
+	ExpandIPv6 bool
+	// TimestampTLV, if non-zero, is the type of a vendor timestamp TLV, as
+	// registered using WithTimestampTLV. If the LSP contains a timestamp TLV
+	// whose contents can be decoded, its timestamp is used for the generated
+	// notifications in place of Timestamp.
+	TimestampTLV uint8
+	// Delete specifies that, rather than the contents of the LSP, a single
+	// notification is generated that deletes the path of the LSP, such
+	// that an LSP that has been purged or withdrawn can be removed from a
+	// cache. The path that is deleted is the prefix used for the updates
+	// that are otherwise generated.
+	Delete bool
 }
 
 // RenderNotifications takes an input IS-IS LSP and outputs the gNMI Notifications that
 // represent the contents of the supplied LSP. The ISISRenderArgs struct provided gives
 // the context for the generation. Returns a set of gNMI notifications, or an error.
 func RenderNotifications(lsp *oc.Lsp, args ISISRenderArgs) ([]*gnmipb.Notification, error) {
+	lsp, prefix, ts, err := prepareRender(lsp, args)
+	if err != nil {
+		return nil, err
+	}
+	return renderStruct(lsp, prefix, ts, args)
+}
+
+// RenderTLVNotifications takes an input IS-IS LSP and outputs the gNMI
+// Notifications that represent the contents of the TLV of type tlvType within
+// it, such that only the subtree of the LSP that corresponds to the TLV is
+// rendered. The prefix of each notification is the path of the TLV, and the
+// updates are otherwise the same as those generated by RenderNotifications for
+// the TLV. If Delete is set in args, the path of the TLV, rather than the LSP,
+// is deleted. The ISISRenderArgs struct provided gives the context for the
+// generation. Returns an error if the TLV is not present within the LSP.
+func RenderTLVNotifications(lsp *oc.Lsp, tlvType oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE, args ISISRenderArgs) ([]*gnmipb.Notification, error) {
+	lsp, prefix, ts, err := prepareRender(lsp, args)
+	if err != nil {
+		return nil, err
+	}
+
+	tlv := lsp.GetTlv(tlvType)
+	if tlv == nil {
+		return nil, fmt.Errorf("TLV %s is not present in LSP %s", tlvType, *lsp.LspId)
+	}
+
+	name := tlvType.String()
+	if args.UsePathElem {
+		prefix.Elem = append(prefix.Elem,
+			&gnmipb.PathElem{Name: "tlvs"},
+			&gnmipb.PathElem{Name: "tlv", Key: map[string]string{"type": name}},
+		)
+	} else {
+		prefix.Element = append(prefix.Element, "tlvs", "tlv", name)
+	}
+	return renderStruct(tlv, prefix, ts, args)
+}
+
+// RenderSetRequest takes an input IS-IS LSP and outputs a gNMI SetRequest that
+// replaces the contents of the LSP, for consumers that apply SetRequests rather
+// than Notifications. The SetRequest contains a single replace operation, whose
+// path is the path of the LSP, and whose value is the LSP serialised as
+// RFC7951 JSON. If Delete is set in args, the SetRequest instead deletes the
+// path of the LSP. The ISISRenderArgs struct provided gives the context for
+// the generation. Returns an error if the LSP cannot be rendered.
+func RenderSetRequest(lsp *oc.Lsp, args ISISRenderArgs) (*gnmipb.SetRequest, error) {
+	lsp, prefix, _, err := prepareRender(lsp, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Delete {
+		return &gnmipb.SetRequest{Delete: []*gnmipb.Path{prefix}}, nil
+	}
+
+	// The LSP is not validated, as per the Notifications generated by
+	// RenderNotifications.
+	js, err := ygot.EmitJSON(lsp, &ygot.EmitJSONConfig{
+		Format: ygot.RFC7951,
+		RFC7951Config: &ygot.RFC7951JSONConfig{
+			AppendModuleName: true,
+		},
+		SkipValidation: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot render LSP %s as JSON, %v", *lsp.LspId, err)
+	}
+
+	return &gnmipb.SetRequest{
+		Replace: []*gnmipb.Update{{
+			Path: prefix,
+			Val:  &gnmipb.TypedValue{Value: &gnmipb.TypedValue_JsonIetfVal{JsonIetfVal: []byte(js)}},
+		}},
+	}, nil
+}
+
+// prepareRender checks that the LSP supplied can be rendered, and returns the
+// LSP that is to be rendered, the prefix path of the LSP, and the timestamp
+// of the notifications, as specified by args.
+func prepareRender(lsp *oc.Lsp, args ISISRenderArgs) (*oc.Lsp, *gnmipb.Path, time.Time, error) {
 	if lsp == nil {
-		return nil, fmt.Errorf("cannot handle nil LSP")
+		return nil, nil, time.Time{}, fmt.Errorf("cannot handle nil LSP")
 	}
 
 	if lsp.LspId == nil {
-		return nil, fmt.Errorf("cannot handle nil LSP ID in %v", lsp)
+		return nil, nil, time.Time{}, fmt.Errorf("cannot handle nil LSP ID in %v", lsp)
 	}
 
-	rArgs := ygot.GNMINotificationsConfig{
-		UsePathElem: args.UsePathElem,
+	// The LSP ID is used as a key within the path, so ensure that it is
+	// valid such that notifications are not published under a malformed
+	// path.
+	if _, err := ParseLSPID(*lsp.LspId); err != nil {
+		return nil, nil, time.Time{}, fmt.Errorf("cannot render LSP, %v", err)
 	}
 
 	prefix := &gnmipb.Path{
@@ -173,13 +777,43 @@ func RenderNotifications(lsp *oc.Lsp, args ISISRenderArgs) ([]*gnmipb.Notificati
 	if args.UsePathElem {
 		p, err := ygot.StringToStructuredPath(fmt.Sprintf("/network-instances/network-instance[name=%s]/protocols/protocol[identifier=ISIS][name=%s]/isis/levels/level[level-number=%d]/link-state-database/lsp[lsp-id=%s]", args.NetworkInstance, args.ProtocolInstance, args.Level, *lsp.LspId))
 		if err != nil {
-			return nil, fmt.Errorf("cannot create prefix path, %v", err)
+			return nil, nil, time.Time{}, fmt.Errorf("cannot create prefix path, %v", err)
 		}
 		prefix = p
-		rArgs.StringSlicePrefix = nil
 	}
 
-	notifications, err := ygot.TogNMINotifications(lsp, args.Timestamp.UnixNano(), rArgs)
+	if args.ExpandIPv6 {
+		var err error
+		if lsp, err = expandLSPIPv6(lsp); err != nil {
+			return nil, nil, time.Time{}, err
+		}
+	}
+
+	ts := args.Timestamp
+	if args.TimestampTLV != 0 {
+		if t, ok := LSPTimestamp(lsp, args.TimestampTLV); ok {
+			ts = t
+		}
+	}
+	return lsp, prefix, ts, nil
+}
+
+// renderStruct renders s, which is an LSP or a subtree of it, to gNMI
+// notifications with the prefix and timestamp supplied. If args specifies
+// that the LSP is deleted, a notification deleting the prefix is returned.
+func renderStruct(s ygot.GoStruct, prefix *gnmipb.Path, ts time.Time, args ISISRenderArgs) ([]*gnmipb.Notification, error) {
+	if args.Delete {
+		return []*gnmipb.Notification{{
+			Timestamp: ts.UnixNano(),
+			Delete:    []*gnmipb.Path{prefix},
+		}}, nil
+	}
+
+	rArgs := ygot.GNMINotificationsConfig{
+		UsePathElem: args.UsePathElem,
+	}
+
+	notifications, err := ygot.TogNMINotifications(s, ts.UnixNano(), rArgs)
 	if err != nil {
 		return nil, err
 	}
@@ -190,3 +824,286 @@ func RenderNotifications(lsp *oc.Lsp, args ISISRenderArgs) ([]*gnmipb.Notificati
 	}
 	return notifications, nil
 }
+
+// RenderNotificationsChan renders the input IS-IS LSP in the same manner as
+// RenderNotifications, and sends each of the resulting notifications to out,
+// blocking until each is received. No notifications are sent if the LSP cannot
+// be rendered, in which case an error is returned. The out channel is not
+// closed, such that the notifications for multiple LSPs can be written to the
+// same channel; the caller is responsible for closing it.
+func RenderNotificationsChan(lsp *oc.Lsp, args ISISRenderArgs, out chan<- *gnmipb.Notification) error {
+	notifications, err := RenderNotifications(lsp, args)
+	if err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		out <- n
+	}
+	return nil
+}
+
+// RenderFlatMap takes an input IS-IS LSP and outputs a map, keyed by the
+// string form of the gNMI path, of the scalar values that are contained within
+// the notifications that RenderNotifications generates for the LSP. Byte
+// values are rendered as hex-encoded strings, and leaf-lists are rendered as a
+// slice of scalar values. The map is intended for debugging and simple
+// assertions, and does not retain the timestamp of the notifications.
+func RenderFlatMap(lsp *oc.Lsp, args ISISRenderArgs) (map[string]interface{}, error) {
+	notifications, err := RenderNotifications(lsp, args)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := map[string]interface{}{}
+	for _, n := range notifications {
+		for _, u := range n.GetUpdate() {
+			p, err := ygot.PathToString(joinPaths(n.GetPrefix(), u.GetPath()))
+			if err != nil {
+				return nil, fmt.Errorf("cannot render path %v, %v", u.GetPath(), err)
+			}
+			v, err := typedValueToScalar(u.GetVal())
+			if err != nil {
+				return nil, fmt.Errorf("cannot render value of %s, %v", p, err)
+			}
+			flat[p] = v
+		}
+	}
+	return flat, nil
+}
+
+// joinPaths returns a new gNMI path consisting of the elements of the
+// prefix followed by those of the supplied path.
+func joinPaths(prefix, path *gnmipb.Path) *gnmipb.Path {
+	j := &gnmipb.Path{}
+	j.Element = append(append(j.Element, prefix.GetElement()...), path.GetElement()...)
+	j.Elem = append(append(j.Elem, prefix.GetElem()...), path.GetElem()...)
+	return j
+}
+
+// typedValueToScalar returns the Go scalar value that is held in the
+// supplied gNMI TypedValue, or an error if the type is not supported.
+func typedValueToScalar(tv *gnmipb.TypedValue) (interface{}, error) {
+	switch v := tv.GetValue().(type) {
+	case *gnmipb.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmipb.TypedValue_IntVal:
+		return v.IntVal, nil
+	case *gnmipb.TypedValue_UintVal:
+		return v.UintVal, nil
+	case *gnmipb.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmipb.TypedValue_BytesVal:
+		return hex.EncodeToString(v.BytesVal), nil
+	case *gnmipb.TypedValue_FloatVal:
+		return v.FloatVal, nil
+	case *gnmipb.TypedValue_LeaflistVal:
+		var l []interface{}
+		for _, e := range v.LeaflistVal.GetElement() {
+			s, err := typedValueToScalar(e)
+			if err != nil {
+				return nil, err
+			}
+			l = append(l, s)
+		}
+		return l, nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+// expandLSPIPv6 returns a copy of the LSP supplied in which the IPv6 addresses
+// and prefixes are in their fully expanded form. The input LSP is not modified,
+// and TLVs that do not contain IPv6 addresses are shared between the input and
+// the returned LSP. Returns an error if an address cannot be expanded.
+func expandLSPIPv6(lsp *oc.Lsp) (*oc.Lsp, error) {
+	n := *lsp
+	n.Tlv = make(map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv, len(lsp.Tlv))
+	for t, tlv := range lsp.Tlv {
+		n.Tlv[t] = tlv
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY]; tlv.GetExtendedIsReachability() != nil {
+		nt := *tlv
+		nt.ExtendedIsReachability = &oc.Lsp_Tlv_ExtendedIsReachability{
+			Neighbor: make(map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor, len(tlv.ExtendedIsReachability.Neighbor)),
+		}
+		for id, nbr := range tlv.ExtendedIsReachability.Neighbor {
+			nn := *nbr
+			nn.Instance = make(map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, len(nbr.Instance))
+			for x, inst := range nbr.Instance {
+				ni, err := expandISReachInstanceIPv6(inst)
+				if err != nil {
+					return nil, err
+				}
+				nn.Instance[x] = ni
+			}
+			nt.ExtendedIsReachability.Neighbor[id] = &nn
+		}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY]; tlv.GetExtendedIpv4Reachability() != nil {
+		nt := *tlv
+		nt.ExtendedIpv4Reachability = &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+			Prefix: make(map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix, len(tlv.ExtendedIpv4Reachability.Prefix)),
+		}
+		for pfx, p := range tlv.ExtendedIpv4Reachability.Prefix {
+			np := *p
+			if st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID]; st != nil {
+				rid, err := expandIPv6SourceRouterID((*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(st.Ipv6SourceRouterId))
+				if err != nil {
+					return nil, err
+				}
+				nst := *st
+				nst.Ipv6SourceRouterId = (*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(rid)
+				np.Subtlv = make(map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv, len(p.Subtlv))
+				for t, s := range p.Subtlv {
+					np.Subtlv[t] = s
+				}
+				np.Subtlv[nst.Type] = &nst
+			}
+			nt.ExtendedIpv4Reachability.Prefix[pfx] = &np
+		}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID]; tlv.GetIpv6TeRouterId() != nil {
+		nt := *tlv
+		ids, err := expandIPv6Addresses(tlv.Ipv6TeRouterId.RouterId)
+		if err != nil {
+			return nil, err
+		}
+		nt.Ipv6TeRouterId = &oc.Lsp_Tlv_Ipv6TeRouterId{RouterId: ids}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES]; tlv.GetIpv6InterfaceAddresses() != nil {
+		nt := *tlv
+		addrs, err := expandIPv6Addresses(tlv.Ipv6InterfaceAddresses.Address)
+		if err != nil {
+			return nil, err
+		}
+		nt.Ipv6InterfaceAddresses = &oc.Lsp_Tlv_Ipv6InterfaceAddresses{Address: addrs}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY]; tlv.GetMtIpv4Reachability() != nil {
+		nt := *tlv
+		nt.MtIpv4Reachability = &oc.Lsp_Tlv_MtIpv4Reachability{
+			Prefix: make(map[oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Key]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix, len(tlv.MtIpv4Reachability.Prefix)),
+		}
+		for k, p := range tlv.MtIpv4Reachability.Prefix {
+			np := *p
+			if st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID]; st != nil {
+				rid, err := expandIPv6SourceRouterID((*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(st.Ipv6SourceRouterId))
+				if err != nil {
+					return nil, err
+				}
+				nst := *st
+				nst.Ipv6SourceRouterId = (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(rid)
+				np.Subtlv = make(map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv, len(p.Subtlv))
+				for t, s := range p.Subtlv {
+					np.Subtlv[t] = s
+				}
+				np.Subtlv[nst.Type] = &nst
+			}
+			nt.MtIpv4Reachability.Prefix[k] = &np
+		}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	if tlv := lsp.Tlv[oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY]; tlv.GetIpv6Reachability() != nil {
+		nt := *tlv
+		nt.Ipv6Reachability = &oc.Lsp_Tlv_Ipv6Reachability{}
+		for pfx, p := range tlv.Ipv6Reachability.Prefix {
+			e, err := expandIPv6Address(pfx)
+			if err != nil {
+				return nil, err
+			}
+			np := *p
+			np.Prefix = ygot.String(e)
+			if st := p.Subtlv[oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID]; st != nil {
+				rid, err := expandIPv6SourceRouterID(st.Ipv6SourceRouterId)
+				if err != nil {
+					return nil, err
+				}
+				nst := *st
+				nst.Ipv6SourceRouterId = rid
+				np.Subtlv = make(map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv, len(p.Subtlv))
+				for t, s := range p.Subtlv {
+					np.Subtlv[t] = s
+				}
+				np.Subtlv[nst.Type] = &nst
+			}
+			if err := nt.Ipv6Reachability.AppendPrefix(&np); err != nil {
+				return nil, err
+			}
+		}
+		n.Tlv[nt.Type] = &nt
+	}
+
+	return &n, nil
+}
+
+// expandIPv6Addresses returns a copy of the IPv6 addresses supplied, in their
+// fully expanded form. Returns an error if an address cannot be expanded.
+func expandIPv6Addresses(addrs []string) ([]string, error) {
+	var e []string
+	for _, a := range addrs {
+		ea, err := expandIPv6Address(a)
+		if err != nil {
+			return nil, err
+		}
+		e = append(e, ea)
+	}
+	return e, nil
+}
+
+// expandISReachInstanceIPv6 returns a copy of the Extended IS Reachability
+// neighbour instance supplied, in which the addresses of the IPv6 interface and
+// neighbour address sub-TLVs are fully expanded. The remaining sub-TLVs are
+// shared with the input.
+func expandISReachInstanceIPv6(inst *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) (*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, error) {
+	ni := *inst
+	ni.Subtlv = make(map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv, len(inst.Subtlv))
+	for t, st := range inst.Subtlv {
+		ni.Subtlv[t] = st
+		if st.Ipv6InterfaceAddress == nil && st.Ipv6NeighborAddress == nil {
+			continue
+		}
+		nst := *st
+		if st.Ipv6InterfaceAddress != nil {
+			addrs, err := expandIPv6Addresses(st.Ipv6InterfaceAddress.Address)
+			if err != nil {
+				return nil, err
+			}
+			nst.Ipv6InterfaceAddress = &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6InterfaceAddress{Address: addrs}
+		}
+		if st.Ipv6NeighborAddress != nil {
+			addrs, err := expandIPv6Addresses(st.Ipv6NeighborAddress.Address)
+			if err != nil {
+				return nil, err
+			}
+			nst.Ipv6NeighborAddress = &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6NeighborAddress{Address: addrs}
+		}
+		ni.Subtlv[t] = &nst
+	}
+	return &ni, nil
+}
+
+// expandIPv6SourceRouterID returns a copy of the IPv6 source router ID sub-TLV
+// of an IP reachability prefix supplied, in which the router ID is fully
+// expanded. The sub-TLV is returned unmodified if it does not contain a router
+// ID. Returns an error if the router ID cannot be expanded.
+func expandIPv6SourceRouterID(r *oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId) (*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId, error) {
+	if r == nil || r.RouterId == nil {
+		return r, nil
+	}
+	e, err := expandIPv6Address(*r.RouterId)
+	if err != nil {
+		return nil, err
+	}
+	nr := *r
+	nr.RouterId = ygot.String(e)
+	return &nr, nil
+}
@@ -15,9 +15,16 @@
 package lsdbparse
 
 import (
+	"bytes"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
 	"math"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
@@ -57,25 +64,27 @@ func mustTypedValue(i interface{}) *gnmipb.TypedValue {
 	return v
 }
 
-func TestISISBytesToLSP(t *testing.T) {
-	// A lab example.
-	var err error
-	ex1, err := hex.DecodeString(strings.Replace("00:00:40:00:ce:39:00:00:00:00:14:26:27:7f:03:01:0e:0d:39:75:2f:01:00:00:14:00:00:90:00:00:01:0e:02:05:d4:81:02:cc:8e:86:04:0a:f4:a8:1f:84:04:0a:f4:a8:1f:89:0e:72:65:30:2d:70:72:30:35:2e:73:71:6c:38:38:16:4f:00:00:40:00:ce:39:02:00:00:1e:44:06:04:c0:a8:c9:24:04:08:00:00:01:43:00:00:00:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:ec:24:00:00:00:00:00:80:26:07:f8:b0:00:00:00:00:00:00:00:03:40:00:ce:39:00:00:00:1e:00:40:20:01:48:60:c0:a8:c9:20:87:12:00:00:00:00:20:0a:f4:a8:1f:00:00:00:1e:1b:c0:a8:c9:20:f2:05:0a:f4:a8:1f:01", ":", "", -1))
-	if err != nil {
-		t.Fatalf("TestISISBytesToLSP: couldn't decode a static example: %v", err)
-	}
-	// A more detailed example.
-	ex2, err := hex.DecodeString(strings.Replace("00:00:40:00:ce:39:02:00:00:00:0e:40:91:bf:03:16:21:00:00:40:00:ce:39:00:00:00:00:00:00:00:40:00:ce:3b:00:00:00:00:00:00:00:40:00:ce:3a:00:00:00:00:00", ":", "", -1))
+// mustDecodeLSPHex decodes a colon-separated hexadecimal string, as output
+// by packet capture tools, to a byte slice.
+func mustDecodeLSPHex(s string) []byte {
+	b, err := hex.DecodeString(strings.Replace(s, ":", "", -1))
 	if err != nil {
-		t.Fatalf("TestISISBytesToLSP: couldn't decode a static example: %v", err)
+		panic(err)
 	}
+	return b
+}
 
-	// A larger IS-IS PDU.
-	ex3, err := hex.DecodeString(strings.Replace("00:00:40:00:ce:3a:00:00:00:00:18:09:f1:2e:03:01:0e:0d:39:75:2f:01:00:00:14:00:00:90:00:00:01:0e:02:05:d4:81:02:cc:8e:86:04:0a:f4:a8:09:84:04:0a:f4:a8:09:89:0e:72:65:30:2d:62:62:30:37:2e:73:71:6c:38:38:16:cc:00:00:40:00:ce:39:02:00:00:1e:5e:06:04:c0:a8:c9:23:04:08:00:00:00:44:00:00:00:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:20:0b:30:00:00:00:40:00:ce:39:00:00:16:20:0b:b0:00:00:00:40:00:ce:39:00:00:17:00:00:40:00:ce:3c:00:00:00:0a:58:06:04:c0:a8:c8:08:08:04:c0:a8:c8:09:04:08:00:00:00:47:00:00:01:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:66:94:4e:ee:66:94:4e:ee:66:94:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:1f:05:30:00:00:00:14:1f:05:b0:00:00:00:15:16:c6:00:00:40:00:d5:b8:00:00:2e:ea:58:06:04:c0:a8:c8:30:08:04:c0:a8:c8:31:04:08:00:00:00:48:00:00:00:59:0b:20:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:0a:04:4e:6e:6b:28:09:04:4e:95:02:f9:03:04:40:00:00:00:1f:05:30:00:00:00:12:1f:05:b0:00:00:00:13:00:00:40:00:d5:be:00:00:00:0a:58:06:04:c0:a8:c8:0e:08:04:c0:a8:c8:0f:04:08:00:00:00:49:00:00:01:48:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:5b:e6:4e:ee:5b:e6:4e:ee:5b:e6:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:1f:05:30:00:00:00:10:1f:05:b0:00:00:00:11:87:51:00:00:00:1e:1b:c0:a8:c9:20:00:00:00:0a:1f:c0:a8:c8:08:00:00:2e:ea:1f:c0:a8:c8:30:00:00:00:0a:1f:c0:a8:c8:0e:00:00:00:00:20:0a:f4:a8:09:00:00:00:00:60:64:01:01:0d:08:03:06:40:00:00:00:00:c8:00:00:00:00:60:c8:01:01:08:08:03:06:00:00:00:00:75:30:84:08:64:01:01:0d:c8:01:01:08:ec:a4:00:00:00:1e:00:40:20:01:48:60:c0:a8:c9:20:00:00:00:0a:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:08:00:00:2e:ea:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:48:00:00:00:0a:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:14:00:00:00:00:00:80:26:07:f8:b0:00:00:00:00:00:00:00:01:40:00:ce:3a:00:00:00:00:20:80:26:07:f8:b0:00:00:00:00:01:00:00:01:00:01:00:13:08:03:06:40:00:00:00:04:b0:00:00:00:00:20:80:26:07:f8:b0:00:00:00:00:02:00:00:01:00:01:00:08:08:03:06:00:00:00:00:79:18:f2:13:0a:f4:a8:09:00:02:09:c0:00:fd:e9:01:03:06:1a:80:13:01:00", ":", "", -1))
+var (
+	// exampleLSP1 is a lab example.
+	exampleLSP1 = mustDecodeLSPHex("00:00:40:00:ce:39:00:00:00:00:14:26:27:7f:03:01:0e:0d:39:75:2f:01:00:00:14:00:00:90:00:00:01:0e:02:05:d4:81:02:cc:8e:86:04:0a:f4:a8:1f:84:04:0a:f4:a8:1f:89:0e:72:65:30:2d:70:72:30:35:2e:73:71:6c:38:38:16:4f:00:00:40:00:ce:39:02:00:00:1e:44:06:04:c0:a8:c9:24:04:08:00:00:01:43:00:00:00:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:ec:24:00:00:00:00:00:80:26:07:f8:b0:00:00:00:00:00:00:00:03:40:00:ce:39:00:00:00:1e:00:40:20:01:48:60:c0:a8:c9:20:87:12:00:00:00:00:20:0a:f4:a8:1f:00:00:00:1e:1b:c0:a8:c9:20:f2:05:0a:f4:a8:1f:01")
+	// exampleLSP2 is a more detailed example.
+	exampleLSP2 = mustDecodeLSPHex("00:00:40:00:ce:39:02:00:00:00:0e:40:91:bf:03:16:21:00:00:40:00:ce:39:00:00:00:00:00:00:00:40:00:ce:3b:00:00:00:00:00:00:00:40:00:ce:3a:00:00:00:00:00")
+	// exampleLSP3 is a larger IS-IS PDU.
+	exampleLSP3 = mustDecodeLSPHex("00:00:40:00:ce:3a:00:00:00:00:18:09:f1:2e:03:01:0e:0d:39:75:2f:01:00:00:14:00:00:90:00:00:01:0e:02:05:d4:81:02:cc:8e:86:04:0a:f4:a8:09:84:04:0a:f4:a8:09:89:0e:72:65:30:2d:62:62:30:37:2e:73:71:6c:38:38:16:cc:00:00:40:00:ce:39:02:00:00:1e:5e:06:04:c0:a8:c9:23:04:08:00:00:00:44:00:00:00:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:20:0b:30:00:00:00:40:00:ce:39:00:00:16:20:0b:b0:00:00:00:40:00:ce:39:00:00:17:00:00:40:00:ce:3c:00:00:00:0a:58:06:04:c0:a8:c8:08:08:04:c0:a8:c8:09:04:08:00:00:00:47:00:00:01:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:66:94:4e:ee:66:94:4e:ee:66:94:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:1f:05:30:00:00:00:14:1f:05:b0:00:00:00:15:16:c6:00:00:40:00:d5:b8:00:00:2e:ea:58:06:04:c0:a8:c8:30:08:04:c0:a8:c8:31:04:08:00:00:00:48:00:00:00:59:0b:20:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:4e:6e:6b:28:0a:04:4e:6e:6b:28:09:04:4e:95:02:f9:03:04:40:00:00:00:1f:05:30:00:00:00:12:1f:05:b0:00:00:00:13:00:00:40:00:d5:be:00:00:00:0a:58:06:04:c0:a8:c8:0e:08:04:c0:a8:c8:0f:04:08:00:00:00:49:00:00:01:48:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:5b:e6:4e:ee:5b:e6:4e:ee:5b:e6:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:1f:05:30:00:00:00:10:1f:05:b0:00:00:00:11:87:51:00:00:00:1e:1b:c0:a8:c9:20:00:00:00:0a:1f:c0:a8:c8:08:00:00:2e:ea:1f:c0:a8:c8:30:00:00:00:0a:1f:c0:a8:c8:0e:00:00:00:00:20:0a:f4:a8:09:00:00:00:00:60:64:01:01:0d:08:03:06:40:00:00:00:00:c8:00:00:00:00:60:c8:01:01:08:08:03:06:00:00:00:00:75:30:84:08:64:01:01:0d:c8:01:01:08:ec:a4:00:00:00:1e:00:40:20:01:48:60:c0:a8:c9:20:00:00:00:0a:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:08:00:00:2e:ea:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:48:00:00:00:0a:00:7f:20:01:00:00:00:00:48:60:01:92:01:68:02:00:00:14:00:00:00:00:00:80:26:07:f8:b0:00:00:00:00:00:00:00:01:40:00:ce:3a:00:00:00:00:20:80:26:07:f8:b0:00:00:00:00:01:00:00:01:00:01:00:13:08:03:06:40:00:00:00:04:b0:00:00:00:00:20:80:26:07:f8:b0:00:00:00:00:02:00:00:01:00:01:00:08:08:03:06:00:00:00:00:79:18:f2:13:0a:f4:a8:09:00:02:09:c0:00:fd:e9:01:03:06:1a:80:13:01:00")
+)
 
-	if err != nil {
-		t.Fatalf("TestISISBytesToLSP: couldn't decode a static example: %v", err)
-	}
+func TestISISBytesToLSP(t *testing.T) {
+	ex1, ex2, ex3 := exampleLSP1, exampleLSP2, exampleLSP3
 
 	tests := []struct {
 		name         string
@@ -92,6 +101,7 @@ func TestISISBytesToLSP(t *testing.T) {
 		inBytes: ex1,
 		wantLSP: &oc.Lsp{
 			Checksum:       ygot.Uint16(10111),
+			IsType:         ygot.Uint8(3),
 			LspId:          ygot.String("0000.4000.ce39.00-00"),
 			SequenceNumber: ygot.Uint32(5158),
 			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
@@ -243,12 +253,21 @@ func TestISISBytesToLSP(t *testing.T) {
 					},
 				},
 			},
+			UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+				// LSP buffer size TLV, which is not parsed.
+				14: {
+					Type:   ygot.Uint8(14),
+					Length: ygot.Uint8(2),
+					Value:  oc.Binary{0x5, 0xd4},
+				},
+			},
 		},
 	}, {
 		name:    "example #2",
 		inBytes: ex2,
 		wantLSP: &oc.Lsp{
 			Checksum:       ygot.Uint16(37311),
+			IsType:         ygot.Uint8(3),
 			LspId:          ygot.String("0000.4000.ce39.02-00"),
 			SequenceNumber: ygot.Uint32(3648),
 			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
@@ -293,6 +312,7 @@ func TestISISBytesToLSP(t *testing.T) {
 		inBytes: ex3,
 		wantLSP: &oc.Lsp{
 			Checksum:       ygot.Uint16(61742),
+			IsType:         ygot.Uint8(3),
 			LspId:          ygot.String("0000.4000.ce3a.00-00"),
 			SequenceNumber: ygot.Uint32(6153),
 			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
@@ -857,6 +877,14 @@ func TestISISBytesToLSP(t *testing.T) {
 					},
 				},
 			},
+			UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+				// LSP buffer size TLV, which is not parsed.
+				14: {
+					Type:   ygot.Uint8(14),
+					Length: ygot.Uint8(2),
+					Value:  oc.Binary{0x5, 0xd4},
+				},
+			},
 		},
 	}}
 
@@ -882,6 +910,131 @@ type renderLSPTest struct {
 }
 
 var renderLSPTests = map[string]*renderLSPTest{
+	"IPv6 prefix - compressed": {
+		inLSP: func() *oc.Lsp {
+			l := &oc.Lsp{}
+			l.LspId = ygot.String("0000.4000.ce39.00-00")
+			l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetOrCreateIpv6Reachability().GetOrCreatePrefix("2001:db8::/32")
+			return l
+		}(),
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			Timestamp:        time.Date(2017, time.May, 6, 14, 0, 0, 0, time.UTC),
+			UsePathElem:      true,
+			ExpandIPv6:       false,
+		},
+		wantNotifications: []*gnmipb.Notification{{
+			Timestamp: 1494079200000000000,
+			Prefix:    mustPath("/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=ISIS][name=15169]/isis/levels/level[level-number=2]/link-state-database/lsp[lsp-id=0000.4000.ce39.00-00]"),
+			Update: []*gnmipb.Update{{
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/ipv6-reachability/prefixes/prefix[prefix=2001:db8::/32]/state/prefix"),
+				Val:  mustTypedValue("2001:db8::/32"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/ipv6-reachability/prefixes/prefix[prefix=2001:db8::/32]/prefix"),
+				Val:  mustTypedValue("2001:db8::/32"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/state/type"),
+				Val:  mustTypedValue("IPV6_REACHABILITY"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/type"),
+				Val:  mustTypedValue("IPV6_REACHABILITY"),
+			}, {
+				Path: mustPath("lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}, {
+				Path: mustPath("state/lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}},
+			Atomic: true,
+		}},
+	},
+	"IPv6 prefix - expanded": {
+		inLSP: func() *oc.Lsp {
+			l := &oc.Lsp{}
+			l.LspId = ygot.String("0000.4000.ce39.00-00")
+			l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetOrCreateIpv6Reachability().GetOrCreatePrefix("2001:db8::/32")
+			return l
+		}(),
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			Timestamp:        time.Date(2017, time.May, 6, 14, 0, 0, 0, time.UTC),
+			UsePathElem:      true,
+			ExpandIPv6:       true,
+		},
+		wantNotifications: []*gnmipb.Notification{{
+			Timestamp: 1494079200000000000,
+			Prefix:    mustPath("/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=ISIS][name=15169]/isis/levels/level[level-number=2]/link-state-database/lsp[lsp-id=0000.4000.ce39.00-00]"),
+			Update: []*gnmipb.Update{{
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/ipv6-reachability/prefixes/prefix[prefix=2001:0db8:0000:0000:0000:0000:0000:0000/32]/state/prefix"),
+				Val:  mustTypedValue("2001:0db8:0000:0000:0000:0000:0000:0000/32"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/ipv6-reachability/prefixes/prefix[prefix=2001:0db8:0000:0000:0000:0000:0000:0000/32]/prefix"),
+				Val:  mustTypedValue("2001:0db8:0000:0000:0000:0000:0000:0000/32"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/state/type"),
+				Val:  mustTypedValue("IPV6_REACHABILITY"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_REACHABILITY]/type"),
+				Val:  mustTypedValue("IPV6_REACHABILITY"),
+			}, {
+				Path: mustPath("lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}, {
+				Path: mustPath("state/lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}},
+			Atomic: true,
+		}},
+	},
+	"IPv6 TE router ID - expanded": {
+		inLSP: func() *oc.Lsp {
+			l := &oc.Lsp{}
+			l.LspId = ygot.String("0000.4000.ce39.00-00")
+			l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID).GetOrCreateIpv6TeRouterId().RouterId = []string{"2001:db8::1"}
+			return l
+		}(),
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			Timestamp:        time.Date(2017, time.May, 6, 14, 0, 0, 0, time.UTC),
+			UsePathElem:      true,
+			ExpandIPv6:       true,
+		},
+		wantNotifications: []*gnmipb.Notification{{
+			Timestamp: 1494079200000000000,
+			Prefix:    mustPath("/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=ISIS][name=15169]/isis/levels/level[level-number=2]/link-state-database/lsp[lsp-id=0000.4000.ce39.00-00]"),
+			Update: []*gnmipb.Update{{
+				Path: mustPath("tlvs/tlv[type=IPV6_TE_ROUTER_ID]/ipv6-te-router-id/state/router-id"),
+				Val: &gnmipb.TypedValue{
+					Value: &gnmipb.TypedValue_LeaflistVal{
+						&gnmipb.ScalarArray{
+							Element: []*gnmipb.TypedValue{{
+								Value: &gnmipb.TypedValue_StringVal{"2001:0db8:0000:0000:0000:0000:0000:0001"},
+							}},
+						},
+					},
+				},
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_TE_ROUTER_ID]/state/type"),
+				Val:  mustTypedValue("IPV6_TE_ROUTER_ID"),
+			}, {
+				Path: mustPath("tlvs/tlv[type=IPV6_TE_ROUTER_ID]/type"),
+				Val:  mustTypedValue("IPV6_TE_ROUTER_ID"),
+			}, {
+				Path: mustPath("lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}, {
+				Path: mustPath("state/lsp-id"),
+				Val:  mustTypedValue("0000.4000.ce39.00-00"),
+			}},
+			Atomic: true,
+		}},
+	},
 	"simple example": {
 		inLSP: &oc.Lsp{
 			Checksum:       ygot.Uint16(48899),
@@ -1358,6 +1511,10 @@ var renderLSPTests = map[string]*renderLSPTest{
 		inLSP:            &oc.Lsp{},
 		wantErrSubstring: "nil LSP ID",
 	},
+	"LSP ID missing fragment": {
+		inLSP:            &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00")},
+		wantErrSubstring: "invalid LSP ID 0000.4000.ce39.00, no fragment number",
+	},
 	"nil LSP": {
 		wantErrSubstring: "nil LSP",
 	},
@@ -1377,24 +1534,1981 @@ func TestRenderLSP(t *testing.T) {
 	}
 }
 
-func benchmarkRenderLSP(b *testing.B, name string, usePathElem bool) {
-	tt := *renderLSPTests[name]
-	for i := 0; i != b.N; i++ {
-		tt.inArgs.UsePathElem = usePathElem
-		_, err := RenderNotifications(tt.inLSP, tt.inArgs)
+func TestRenderNotificationsChan(t *testing.T) {
+	lsp, _, err := ISISBytesToLSP(exampleLSP3, 0)
+	if err != nil {
+		t.Fatalf("ISISBytesToLSP(exampleLSP3): got unexpected error: %v", err)
+	}
+
+	args := ISISRenderArgs{
+		NetworkInstance:  "DEFAULT",
+		ProtocolInstance: "15169",
+		Level:            2,
+		Timestamp:        time.Unix(42, 0),
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		wantErrSubstring string
+	}{{
+		name:  "larger example",
+		inLSP: lsp,
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		out := make(chan *gnmipb.Notification)
+		done := make(chan []*gnmipb.Notification)
+		go func() {
+			var got []*gnmipb.Notification
+			for n := range out {
+				got = append(got, n)
+			}
+			done <- got
+		}()
+
+		err := RenderNotificationsChan(tt.inLSP, args, out)
+		close(out)
+		got := <-done
+
 		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
-			b.Errorf("%s: RenderNotifications(%v, %v): got unexpected %s", name, tt.inLSP, tt.inArgs, diff)
+			t.Errorf("%s: RenderNotificationsChan(%v, %v, out): got unexpected %s", tt.name, tt.inLSP, args, diff)
+		}
+		if err != nil {
+			if len(got) != 0 {
+				t.Errorf("%s: RenderNotificationsChan(%v, %v, out): sent notifications on error, got: %v", tt.name, tt.inLSP, args, got)
+			}
+			continue
+		}
+
+		want, err := RenderNotifications(tt.inLSP, args)
+		if err != nil {
+			t.Errorf("%s: RenderNotifications(%v, %v): got unexpected error: %v", tt.name, tt.inLSP, args, err)
+			continue
+		}
+
+		if len(got) == 0 {
+			t.Errorf("%s: RenderNotificationsChan(%v, %v, out): did not receive any notifications", tt.name, tt.inLSP, args)
+		}
+		if !testutil.NotificationSetEqual(got, want) {
+			t.Errorf("%s: RenderNotificationsChan(%v, %v, out): did not get expected notifications, got: %v, want: %v", tt.name, tt.inLSP, args, got, want)
 		}
 	}
 }
 
-func BenchmarkRenderLSP(b *testing.B) {
-	benchmarkTests := []string{"simple example", "larger example", "simple - pathelem path"}
+func TestRenderFlatMap(t *testing.T) {
+	simplePrefix := "/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.02-00"
+
+	undefLSP := &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")}
+	undefLSP.GetOrCreateUndefinedTlv(42).Value = []byte{0xde, 0xad, 0xbe, 0xef}
+
+	// Overload, attached default and attached error flags set.
+	flagsLSP := &oc.Lsp{
+		LspId: ygot.String("0000.4000.ce39.00-00"),
+		Flags: parseLSPFlags(0x4C),
+	}
+
+	// Prefix carrying both IPv4 and IPv6 source router ID sub-TLVs.
+	srcRIDLSP := &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")}
+	if err := ParseTLV(srcRIDLSP, 135, []byte{
+		0x0, 0x0, 0x0, 0x0A, 0x58, 192, 0, 2, 0x18,
+		0xB, 0x4, 192, 0, 2, 1,
+		0xC, 0x10, 0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x1,
+	}); err != nil {
+		t.Fatalf("cannot parse source router ID TLV: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		inArgs           ISISRenderArgs
+		wantEntries      map[string]interface{}
+		wantErrSubstring string
+	}{{
+		name:   "simple example",
+		inLSP:  renderLSPTests["simple example"].inLSP,
+		inArgs: renderLSPTests["simple example"].inArgs,
+		wantEntries: map[string]interface{}{
+			simplePrefix + "/state/checksum":                               uint64(48899),
+			simplePrefix + "/state/sequence-number":                        uint64(934033),
+			simplePrefix + "/state/lsp-id":                                 "0000.4000.ce39.02-00",
+			simplePrefix + "/tlvs/tlv/EXTENDED_IS_REACHABILITY/state/type": "EXTENDED_IS_REACHABILITY",
+		},
+	}, {
+		name:   "pathelem paths",
+		inLSP:  renderLSPTests["simple - pathelem path"].inLSP,
+		inArgs: renderLSPTests["simple - pathelem path"].inArgs,
+		wantEntries: map[string]interface{}{
+			"/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=ISIS][name=15169]/isis/levels/level[level-number=2]/link-state-database/lsp[lsp-id=0000.4000.ce39.00-00]/tlvs/tlv[type=EXTENDED_IS_REACHABILITY]/extended-is-reachability/neighbors/neighbor[system-id=0000.4000.ce39]/state/system-id": "0000.4000.ce39",
+		},
+	}, {
+		name:  "bytes are hex encoded",
+		inLSP: undefLSP,
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+		},
+		wantEntries: map[string]interface{}{
+			"/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.00-00/undefined-tlvs/undefined-tlv/42/state/value": "deadbeef",
+		},
+	}, {
+		name:  "multiple LSP flags",
+		inLSP: flagsLSP,
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+		},
+		wantEntries: map[string]interface{}{
+			"/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.00-00/state/flags": []interface{}{
+				"ATTACHED_ERROR",
+				"ATTACHED_DEFAULT",
+				"OVERLOAD",
+			},
+		},
+	}, {
+		name:  "prefix source router IDs",
+		inLSP: srcRIDLSP,
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+		},
+		wantEntries: map[string]interface{}{
+			"/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.00-00/tlvs/tlv/EXTENDED_IPV4_REACHABILITY/extended-ipv4-reachability/prefixes/prefix/192.0.2.0/24/subtlvs/subtlv/IP_REACHABILITY_IPV4_ROUTER_ID/ipv4-source-router-id/state/router-id": "192.0.2.1",
+			"/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.00-00/tlvs/tlv/EXTENDED_IPV4_REACHABILITY/extended-ipv4-reachability/prefixes/prefix/192.0.2.0/24/subtlvs/subtlv/IP_REACHABILITY_IPV6_ROUTER_ID/ipv6-source-router-id/state/router-id": "2001:db8::1",
+		},
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		got, err := RenderFlatMap(tt.inLSP, tt.inArgs)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: RenderFlatMap(%v, %v): got unexpected %s", tt.name, tt.inLSP, tt.inArgs, diff)
+		}
+		if err != nil {
+			continue
+		}
+
+		for path, want := range tt.wantEntries {
+			v, ok := got[path]
+			if !ok {
+				t.Errorf("%s: RenderFlatMap(%v, %v): did not find path %s in %v", tt.name, tt.inLSP, tt.inArgs, path, got)
+				continue
+			}
+			if diff := pretty.Compare(v, want); diff != "" {
+				t.Errorf("%s: RenderFlatMap(%v, %v): did not get expected value for %s, diff(-got,+want):\n%s", tt.name, tt.inLSP, tt.inArgs, path, diff)
+			}
+		}
+	}
+}
+
+// flattenNotifications returns a map, keyed by the string form of the full
+// path of each update in the notifications supplied, of the update's value.
+func flattenNotifications(t *testing.T, ns []*gnmipb.Notification) map[string]interface{} {
+	t.Helper()
+	flat := map[string]interface{}{}
+	for _, n := range ns {
+		for _, u := range n.GetUpdate() {
+			p, err := ygot.PathToString(joinPaths(n.GetPrefix(), u.GetPath()))
+			if err != nil {
+				t.Fatalf("cannot render path %v, %v", u.GetPath(), err)
+			}
+			v, err := typedValueToScalar(u.GetVal())
+			if err != nil {
+				t.Fatalf("cannot render value of %s, %v", p, err)
+			}
+			flat[p] = v
+		}
+	}
+	return flat
+}
+
+func TestRenderTLVNotifications(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+
+	tlvTypes := []oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE{
+		oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+		oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+		oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+	}
 
 	for _, usePathElem := range []bool{false, true} {
-		for _, name := range benchmarkTests {
-			b.Run(name+"/usePathElem="+strconv.FormatBool(usePathElem),
-				func(b *testing.B) { benchmarkRenderLSP(b, name, usePathElem) })
+		args := ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			Timestamp:        time.Unix(42, 0),
+			UsePathElem:      usePathElem,
+		}
+
+		full, err := RenderNotifications(lsp, args)
+		if err != nil {
+			t.Fatalf("RenderNotifications(exampleLSP1, %+v): got unexpected error: %v", args, err)
+		}
+		fullFlat := flattenNotifications(t, full)
+
+		for _, tlvType := range tlvTypes {
+			t.Run(fmt.Sprintf("%s/usePathElem=%v", tlvType, usePathElem), func(t *testing.T) {
+				got, err := RenderTLVNotifications(lsp, tlvType, args)
+				if err != nil {
+					t.Fatalf("RenderTLVNotifications(exampleLSP1, %s, %+v): got unexpected error: %v", tlvType, args, err)
+				}
+
+				tlvPath := fmt.Sprintf("/tlvs/tlv/%s/", tlvType)
+				if usePathElem {
+					tlvPath = fmt.Sprintf("/tlvs/tlv[type=%s]/", tlvType)
+				}
+				want := map[string]interface{}{}
+				for p, v := range fullFlat {
+					if strings.Contains(p, tlvPath) {
+						want[p] = v
+					}
+				}
+				if len(want) == 0 {
+					t.Fatalf("RenderNotifications(exampleLSP1, %+v): did not render any paths within %s", args, tlvPath)
+				}
+
+				if diff := pretty.Compare(flattenNotifications(t, got), want); diff != "" {
+					t.Errorf("RenderTLVNotifications(exampleLSP1, %s, %+v): did not get expected updates, diff(-got,+want):\n%s", tlvType, args, diff)
+				}
+				for _, n := range got {
+					if n.GetTimestamp() != args.Timestamp.UnixNano() {
+						t.Errorf("RenderTLVNotifications(exampleLSP1, %s, %+v): got timestamp %d, want: %d", tlvType, args, n.GetTimestamp(), args.Timestamp.UnixNano())
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRenderNotificationsDelete(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+	purge := &oc.Lsp{LspId: ygot.String("0000.4000.ce39.00-00")}
+
+	tests := []struct {
+		name      string
+		inLSP     *oc.Lsp
+		inTLVType oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE
+	}{{
+		name:  "example LSP",
+		inLSP: lsp,
+	}, {
+		name:  "purged LSP",
+		inLSP: purge,
+	}, {
+		name:      "TLV within example LSP",
+		inLSP:     lsp,
+		inTLVType: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+	}}
+
+	for _, tt := range tests {
+		for _, usePathElem := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/usePathElem=%v", tt.name, usePathElem), func(t *testing.T) {
+				args := ISISRenderArgs{
+					NetworkInstance:  "DEFAULT",
+					ProtocolInstance: "15169",
+					Level:            2,
+					Timestamp:        time.Unix(42, 0),
+					UsePathElem:      usePathElem,
+				}
+				render := func(args ISISRenderArgs) []*gnmipb.Notification {
+					var ns []*gnmipb.Notification
+					var err error
+					if tt.inTLVType != oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_UNSET {
+						ns, err = RenderTLVNotifications(tt.inLSP, tt.inTLVType, args)
+					} else {
+						ns, err = RenderNotifications(tt.inLSP, args)
+					}
+					if err != nil {
+						t.Fatalf("cannot render LSP with args %+v, %v", args, err)
+					}
+					return ns
+				}
+
+				updates := render(args)
+				if len(updates) == 0 {
+					t.Fatalf("did not get any notifications for LSP")
+				}
+				wantPath := updates[0].GetPrefix()
+
+				args.Delete = true
+				got := render(args)
+				if len(got) != 1 {
+					t.Fatalf("did not get a single delete notification, got: %v", got)
+				}
+				n := got[0]
+				if len(n.GetUpdate()) != 0 {
+					t.Errorf("got unexpected updates in delete notification: %v", n.GetUpdate())
+				}
+				if n.GetTimestamp() != args.Timestamp.UnixNano() {
+					t.Errorf("got timestamp %d, want: %d", n.GetTimestamp(), args.Timestamp.UnixNano())
+				}
+				if len(n.GetDelete()) != 1 {
+					t.Fatalf("did not get a single delete path, got: %v", n.GetDelete())
+				}
+				if diff := pretty.Compare(n.GetDelete()[0], wantPath); diff != "" {
+					t.Errorf("did not get delete path matching the prefix of updates, diff(-got,+want):\n%s", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestRenderSetRequest(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		inArgs           ISISRenderArgs
+		wantPath         string
+		wantValues       []string
+		wantErrSubstring string
+	}{{
+		name:  "example LSP",
+		inLSP: lsp,
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+		},
+		wantPath:   "/network-instances/network-instance/DEFAULT/protocols/protocol/ISIS/15169/isis/levels/level/2/link-state-database/lsp/0000.4000.ce39.00-00",
+		wantValues: []string{"re0-pr05.sql88", "5158", "10.244.168.31"},
+	}, {
+		name:  "example LSP with pathelem paths",
+		inLSP: lsp,
+		inArgs: ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			UsePathElem:      true,
+		},
+		wantPath:   "/network-instances/network-instance[name=DEFAULT]/protocols/protocol[identifier=ISIS][name=15169]/isis/levels/level[level-number=2]/link-state-database/lsp[lsp-id=0000.4000.ce39.00-00]",
+		wantValues: []string{"re0-pr05.sql88", "5158", "10.244.168.31"},
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "cannot handle nil LSP",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderSetRequest(tt.inLSP, tt.inArgs)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("RenderSetRequest(lsp, %+v): did not get expected error, %s", tt.inArgs, diff)
+			}
+			if err != nil {
+				return
+			}
+
+			if got.GetPrefix() != nil || len(got.GetUpdate()) != 0 || len(got.GetDelete()) != 0 {
+				t.Errorf("RenderSetRequest(lsp, %+v): got unexpected prefix, update or delete operations, prefix: %v, updates: %v, deletes: %v", tt.inArgs, got.GetPrefix(), got.GetUpdate(), got.GetDelete())
+			}
+			if len(got.GetReplace()) != 1 {
+				t.Fatalf("RenderSetRequest(lsp, %+v): did not get a single replace operation, got: %v", tt.inArgs, got.GetReplace())
+			}
+			r := got.GetReplace()[0]
+
+			path, err := ygot.PathToString(r.GetPath())
+			if err != nil {
+				t.Fatalf("RenderSetRequest(lsp, %+v): cannot render path %v, %v", tt.inArgs, r.GetPath(), err)
+			}
+			if path != tt.wantPath {
+				t.Errorf("RenderSetRequest(lsp, %+v): did not get expected path, got: %s, want: %s", tt.inArgs, path, tt.wantPath)
+			}
+
+			js := r.GetVal().GetJsonIetfVal()
+			if js == nil {
+				t.Fatalf("RenderSetRequest(lsp, %+v): did not get a JSON_IETF value, got: %v", tt.inArgs, r.GetVal())
+			}
+			var v interface{}
+			if err := json.Unmarshal(js, &v); err != nil {
+				t.Fatalf("RenderSetRequest(lsp, %+v): cannot unmarshal JSON value, %v", tt.inArgs, err)
+			}
+			for _, want := range tt.wantValues {
+				if !jsonContains(v, want) {
+					t.Errorf("RenderSetRequest(lsp, %+v): did not find value %s in JSON, got: %s", tt.inArgs, want, js)
+				}
+			}
+		})
+	}
+}
+
+// jsonContains returns true if the unmarshalled JSON in v contains a leaf value
+// whose string representation is want.
+func jsonContains(v interface{}, want string) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, e := range t {
+			if jsonContains(e, want) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			if jsonContains(e, want) {
+				return true
+			}
+		}
+	default:
+		return fmt.Sprint(t) == want
+	}
+	return false
+}
+
+func TestRenderSetRequestDelete(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+
+	for _, usePathElem := range []bool{false, true} {
+		args := ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			UsePathElem:      usePathElem,
+		}
+		replace, err := RenderSetRequest(lsp, args)
+		if err != nil {
+			t.Fatalf("RenderSetRequest(exampleLSP1, %+v): got unexpected error: %v", args, err)
+		}
+
+		args.Delete = true
+		got, err := RenderSetRequest(lsp, args)
+		if err != nil {
+			t.Fatalf("RenderSetRequest(exampleLSP1, %+v): got unexpected error: %v", args, err)
+		}
+		if len(got.GetReplace()) != 0 || len(got.GetDelete()) != 1 {
+			t.Fatalf("RenderSetRequest(exampleLSP1, %+v): did not get a single delete operation, got: %v", args, got)
+		}
+		if diff := pretty.Compare(got.GetDelete()[0], replace.GetReplace()[0].GetPath()); diff != "" {
+			t.Errorf("RenderSetRequest(exampleLSP1, %+v): delete path does not match replace path, diff(-got,+want):\n%s", args, diff)
+		}
+	}
+}
+
+func TestRenderTLVNotificationsErrors(t *testing.T) {
+	lsp, ok, err := ISISBytesToLSP(exampleLSP1, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP1): could not parse LSP: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		inTLVType        oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE
+		wantErrSubstring string
+	}{{
+		name:             "nil LSP",
+		inTLVType:        oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+		wantErrSubstring: "cannot handle nil LSP",
+	}, {
+		name:             "absent TLV",
+		inLSP:            lsp,
+		inTLVType:        oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_ISN,
+		wantErrSubstring: "TLV MT_ISN is not present in LSP 0000.4000.ce39.00-00",
+	}, {
+		name:             "invalid LSP ID",
+		inLSP:            &oc.Lsp{LspId: ygot.String("invalid")},
+		inTLVType:        oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+		wantErrSubstring: "cannot render LSP",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RenderTLVNotifications(tt.inLSP, tt.inTLVType, ISISRenderArgs{})
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("RenderTLVNotifications(%v, %s): did not get expected error, %s", tt.inLSP, tt.inTLVType, diff)
+			}
+		})
+	}
+}
+
+func benchmarkRenderLSP(b *testing.B, name string, usePathElem bool) {
+	tt := *renderLSPTests[name]
+	for i := 0; i != b.N; i++ {
+		tt.inArgs.UsePathElem = usePathElem
+		_, err := RenderNotifications(tt.inLSP, tt.inArgs)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			b.Errorf("%s: RenderNotifications(%v, %v): got unexpected %s", name, tt.inLSP, tt.inArgs, diff)
+		}
+	}
+}
+
+func BenchmarkRenderLSP(b *testing.B) {
+	benchmarkTests := []string{"simple example", "larger example", "simple - pathelem path"}
+
+	for _, usePathElem := range []bool{false, true} {
+		for _, name := range benchmarkTests {
+			b.Run(name+"/usePathElem="+strconv.FormatBool(usePathElem),
+				func(b *testing.B) { benchmarkRenderLSP(b, name, usePathElem) })
+		}
+	}
+}
+
+func TestParseTLVStream(t *testing.T) {
+	errStop := errors.New("stop")
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOffset         int
+		inStopAt         uint8
+		wantHostname     string
+		wantTypes        []uint8
+		wantErrSubstring string
+	}{{
+		name:         "hostname from vendor example",
+		inBytes:      exampleLSP1,
+		wantHostname: "re0-pr05.sql88",
+		wantTypes:    []uint8{1, 14, 129, 134, 132, 137, 22, 236, 135, 242},
+	}, {
+		name:             "callback error stops parsing",
+		inBytes:          exampleLSP1,
+		inStopAt:         137,
+		wantHostname:     "re0-pr05.sql88",
+		wantTypes:        []uint8{1, 14, 129, 134, 132, 137},
+		wantErrSubstring: "stop",
+	}, {
+		name:      "offset",
+		inBytes:   append([]byte{0xFF, 0xFF}, exampleLSP2...),
+		inOffset:  2,
+		wantTypes: []uint8{22},
+	}, {
+		name:             "truncated TLV",
+		inBytes:          append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, 137, 4, 'a'),
+		wantErrSubstring: "invalid TLVs in LSP",
+	}, {
+		name:             "short LSP",
+		inBytes:          []byte{0x01, 0x02},
+		wantErrSubstring: "need at least 15 bytes",
+	}, {
+		name:             "invalid offset",
+		inBytes:          exampleLSP1,
+		inOffset:         -1,
+		wantErrSubstring: "invalid offset",
+	}}
+
+	for _, tt := range tests {
+		var gotHostname string
+		var gotTypes []uint8
+		err := ParseTLVStream(tt.inBytes, tt.inOffset, func(tlvType uint8, value []byte) error {
+			gotTypes = append(gotTypes, tlvType)
+			if tlvType == 137 {
+				gotHostname = string(value)
+			}
+			if tt.inStopAt != 0 && tlvType == tt.inStopAt {
+				return errStop
+			}
+			return nil
+		})
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ParseTLVStream(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if gotHostname != tt.wantHostname {
+			t.Errorf("%s: ParseTLVStream(...): did not get expected hostname, got: %s, want: %s", tt.name, gotHostname, tt.wantHostname)
+		}
+
+		if diff := pretty.Compare(gotTypes, tt.wantTypes); diff != "" {
+			t.Errorf("%s: ParseTLVStream(...): did not get expected TLV types, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestUndefinedTLVs(t *testing.T) {
+	lspHeader := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+
+	tests := []struct {
+		name              string
+		inTLVs            []byte
+		inOpts            []ParseOption
+		wantTLVs          map[uint8][][]byte
+		wantUndefinedTLVs map[uint8]*oc.Lsp_UndefinedTlv
+		wantErrSubstring  string
+	}{{
+		name: "known and unknown TLVs",
+		inTLVs: []byte{
+			// LSP buffer size
+			14, 2, 0x05, 0xd4,
+			// Dynamic hostname
+			137, 2, 'r', '1',
+			// Vendor private TLV
+			250, 3, 0xDE, 0xAD, 0xBE,
+		},
+		wantTLVs: map[uint8][][]byte{
+			14:  {{0x05, 0xd4}},
+			250: {{0xDE, 0xAD, 0xBE}},
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			14: {
+				Type:   ygot.Uint8(14),
+				Length: ygot.Uint8(2),
+				Value:  oc.Binary{0x05, 0xd4},
+			},
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(3),
+				Value:  oc.Binary{0xDE, 0xAD, 0xBE},
+			},
+		},
+	}, {
+		name: "repeated unknown TLV",
+		inTLVs: []byte{
+			250, 1, 0x01,
+			137, 2, 'r', '1',
+			250, 0,
+			250, 2, 0x02, 0x03,
+		},
+		wantTLVs: map[uint8][][]byte{
+			250: {{0x01}, {}, {0x02, 0x03}},
+		},
+		wantErrSubstring: "duplicate undefined TLV of type 250, retaining first instance",
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(1),
+				Value:  oc.Binary{0x01},
+			},
+		},
+	}, {
+		name:   "known TLV in raw range",
+		inTLVs: []byte{137, 2, 'r', '1'},
+		inOpts: []ParseOption{WithRawTLVRange(137, 137)},
+		wantTLVs: map[uint8][][]byte{
+			137: {{'r', '1'}},
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			137: {
+				Type:   ygot.Uint8(137),
+				Length: ygot.Uint8(2),
+				Value:  oc.Binary{'r', '1'},
+			},
+		},
+	}, {
+		name:     "no unknown TLVs",
+		inTLVs:   []byte{137, 2, 'r', '1'},
+		wantTLVs: map[uint8][][]byte{},
+	}, {
+		name:     "unknown TLVs discarded",
+		inTLVs:   []byte{14, 2, 0x05, 0xd4, 250, 1, 0x01},
+		inOpts:   []ParseOption{WithDiscardUnknownTLVs(true)},
+		wantTLVs: map[uint8][][]byte{},
+	}, {
+		name:   "unknown TLVs discarded outside raw range",
+		inTLVs: []byte{14, 2, 0x05, 0xd4, 250, 1, 0x01},
+		inOpts: []ParseOption{WithDiscardUnknownTLVs(true), WithRawTLVRange(250, 254)},
+		wantTLVs: map[uint8][][]byte{
+			250: {{0x01}},
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(1),
+				Value:  oc.Binary{0x01},
+			},
+		},
+	}, {
+		name: "authentication and NLPID TLVs",
+		inTLVs: []byte{
+			10, 5, 1, 'p', 'a', 's', 's',
+			129, 2, 0xCC, 0x81,
+		},
+		wantTLVs: map[uint8][][]byte{},
+	}, {
+		name: "multi-topology TLV with overload and attached flags",
+		inTLVs: []byte{
+			229, 2, 0x00, 0x00,
+			229, 2, 0xC0, 0x02,
+		},
+		wantTLVs: map[uint8][][]byte{
+			229: {{0xC0, 0x02}},
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			229: {
+				Type:   ygot.Uint8(229),
+				Length: ygot.Uint8(2),
+				Value:  oc.Binary{0xC0, 0x02},
+			},
+		},
+	}}
+
+	for _, tt := range tests {
+		in := append(append([]byte{}, lspHeader...), tt.inTLVs...)
+
+		got, err := UndefinedTLVs(in, 0, tt.inOpts...)
+		if err != nil {
+			t.Errorf("%s: UndefinedTLVs(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.wantTLVs); diff != "" {
+			t.Errorf("%s: UndefinedTLVs(...): did not get expected TLVs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		lsp, ok, err := ISISBytesToLSP(in, 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+		if diff := pretty.Compare(lsp.UndefinedTlv, tt.wantUndefinedTLVs); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected undefined TLVs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+
+	if _, err := UndefinedTLVs([]byte{0x01}, 0); err == nil {
+		t.Errorf("UndefinedTLVs(...): did not get expected error for short LSP")
+	}
+}
+
+func TestWithMaxLSPBytes(t *testing.T) {
+	// lsp is an LSP consisting of the LSP ID, sequence number, checksum and
+	// flags, followed by a Dynamic Hostname TLV, 21 bytes in total.
+	lsp := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03, 137, 4, 'r', 't', 'r', '1'}
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOffset         int
+		inOpts           []ParseOption
+		wantErrSubstring string
+	}{{
+		name:    "no limit",
+		inBytes: lsp,
+	}, {
+		name:    "LSP at limit",
+		inBytes: lsp,
+		inOpts:  []ParseOption{WithMaxLSPBytes(21)},
+	}, {
+		name:             "LSP exceeds limit",
+		inBytes:          lsp,
+		inOpts:           []ParseOption{WithMaxLSPBytes(20)},
+		wantErrSubstring: "exceeds maximum of 20 bytes",
+	}, {
+		name:             "oversize buffer",
+		inBytes:          append(append([]byte{}, lsp...), make([]byte, 8192)...),
+		inOpts:           []ParseOption{WithMaxLSPBytes(1492)},
+		wantErrSubstring: "exceeds maximum of 1492 bytes",
+	}, {
+		name:     "offset bytes are not counted",
+		inBytes:  append([]byte{0xFF, 0xFF}, lsp...),
+		inOffset: 2,
+		inOpts:   []ParseOption{WithMaxLSPBytes(21)},
+	}, {
+		name:    "non-positive limit",
+		inBytes: lsp,
+		inOpts:  []ParseOption{WithMaxLSPBytes(0)},
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := ISISBytesToLSP(tt.inBytes, tt.inOffset, tt.inOpts...)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if tt.wantErrSubstring != "" {
+			if ok || got != nil {
+				t.Errorf("%s: ISISBytesToLSP(...): got parsed LSP for rejected input, got: %v, want: nil", tt.name, got)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP", tt.name)
+		}
+	}
+}
+
+func TestSequenceNumberCheck(t *testing.T) {
+	// lspHeader returns the LSP ID, sequence number, checksum and flags of
+	// an LSP with the sequence number seq.
+	lspHeader := func(seq byte) []byte {
+		return []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, seq, 0, 0, 0x03}
+	}
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOpts           []ParseOption
+		wantSeqNum       uint32
+		wantErrSubstring string
+	}{{
+		name:             "zero sequence number with check",
+		inBytes:          lspHeader(0),
+		inOpts:           []ParseOption{WithSequenceNumberCheck(true)},
+		wantErrSubstring: "invalid sequence number 0",
+	}, {
+		name:    "zero sequence number without check",
+		inBytes: lspHeader(0),
+	}, {
+		name:       "valid sequence number with check",
+		inBytes:    lspHeader(1),
+		inOpts:     []ParseOption{WithSequenceNumberCheck(true)},
+		wantSeqNum: 1,
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := ISISBytesToLSP(tt.inBytes, 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if got.SequenceNumber == nil || *got.SequenceNumber != tt.wantSeqNum {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected sequence number, got: %v, want: %d", tt.name, got.SequenceNumber, tt.wantSeqNum)
+		}
+	}
+}
+
+func TestUnusableMetricCheck(t *testing.T) {
+	// lsp is an LSP containing an Extended IPv4 Reachability TLV, in which
+	// 192.0.2.0/24 is advertised with a metric one greater than
+	// MAX_PATH_METRIC, 198.51.100.0/24 is advertised with a metric of 10,
+	// and 203.0.113.0/24 is advertised with a metric of MAX_PATH_METRIC.
+	lsp := []byte{
+		0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03,
+		135, 24,
+		0xfe, 0, 0, 0x01, 24, 192, 0, 2,
+		0, 0, 0, 10, 24, 198, 51, 100,
+		0xfe, 0, 0, 0, 24, 203, 0, 113,
+	}
+
+	tests := []struct {
+		name             string
+		inOpts           []ParseOption
+		wantErrSubstring string
+	}{{
+		name:             "unusable metric with check",
+		inOpts:           []ParseOption{WithUnusableMetricCheck(true)},
+		wantErrSubstring: "prefix 192.0.2.0/24 advertised with unusable metric 0xfe000001",
+	}, {
+		name: "unusable metric without check",
+	}}
+
+	want := []PrefixInfo{{
+		Prefix:   "192.0.2.0/24",
+		TLV:      oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+		Metric:   0xFE000001,
+		DoNotUse: true,
+	}, {
+		Prefix: "198.51.100.0/24",
+		TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+		Metric: 10,
+	}, {
+		Prefix: "203.0.113.0/24",
+		TLV:    oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+		Metric: 0xFE000000,
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := ISISBytesToLSP(lsp, 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		r := got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability()
+		if p := r.GetPrefix("192.0.2.0/24"); !IsUnusableIPv4Prefix(p) {
+			t.Errorf("%s: IsUnusableIPv4Prefix(%v): got: false, want: true", tt.name, p)
+		}
+		for _, pfx := range []string{"198.51.100.0/24", "203.0.113.0/24"} {
+			if p := r.GetPrefix(pfx); IsUnusableIPv4Prefix(p) {
+				t.Errorf("%s: IsUnusableIPv4Prefix(%v): got: true, want: false", tt.name, p)
+			}
+		}
+
+		if diff := pretty.Compare(Prefixes(got), want); diff != "" {
+			t.Errorf("%s: Prefixes(...): did not get expected prefixes, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestWithRemainingLifetime(t *testing.T) {
+	// lifetimePDU returns exampleL2PDU with the remaining lifetime secs.
+	lifetimePDU := func(secs uint16) []byte {
+		b := append([]byte{}, exampleL2PDU...)
+		binary.BigEndian.PutUint16(b[10:12], secs)
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		inParse func() (*oc.Lsp, bool, error)
+		want    *uint16
+	}{{
+		name: "remaining lifetime supplied",
+		inParse: func() (*oc.Lsp, bool, error) {
+			return ISISBytesToLSP(exampleLSP1, 0, WithRemainingLifetime(1199))
+		},
+		want: ygot.Uint16(1199),
+	}, {
+		name: "purged LSP",
+		inParse: func() (*oc.Lsp, bool, error) {
+			return ISISBytesToLSP(exampleLSP1, 0, WithRemainingLifetime(0))
+		},
+		want: ygot.Uint16(0),
+	}, {
+		name: "remaining lifetime not supplied",
+		inParse: func() (*oc.Lsp, bool, error) {
+			return ISISBytesToLSP(exampleLSP1, 0)
+		},
+	}, {
+		name: "remaining lifetime from PDU",
+		inParse: func() (*oc.Lsp, bool, error) {
+			return ISISPDUToLSP(lifetimePDU(1199))
+		},
+		want: ygot.Uint16(1199),
+	}, {
+		name: "remaining lifetime from PDU takes precedence",
+		inParse: func() (*oc.Lsp, bool, error) {
+			return ISISPDUToLSP(lifetimePDU(0), WithRemainingLifetime(1199))
+		},
+		want: ygot.Uint16(0),
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := tt.inParse()
+		if !ok {
+			t.Errorf("%s: could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		switch l := got.RemainingLifetime; {
+		case l == nil && tt.want != nil:
+			t.Errorf("%s: did not get expected remaining lifetime, got: nil, want: %d", tt.name, *tt.want)
+		case l != nil && tt.want == nil:
+			t.Errorf("%s: did not get expected remaining lifetime, got: %d, want: nil", tt.name, *l)
+		case l != nil && *l != *tt.want:
+			t.Errorf("%s: did not get expected remaining lifetime, got: %d, want: %d", tt.name, *l, *tt.want)
+		}
+	}
+}
+
+func TestWithStrictPDULength(t *testing.T) {
+	// lsp is an LSP consisting of the LSP ID, sequence number, checksum and
+	// flags, followed by a Dynamic Hostname TLV.
+	lsp := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03, 137, 4, 'r', 't', 'r', '1'}
+	pdu := WrapAsStandardPDU(lsp, PDUTypeL2LSP, 0)
+	trailing := append(append([]byte{}, pdu...), 0, 0)
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOffset         int
+		inOpts           []ParseOption
+		wantErrSubstring string
+	}{{
+		name:     "complete PDU",
+		inBytes:  pdu,
+		inOffset: LSPIDOffset,
+		inOpts:   []ParseOption{WithStrictPDULength(true)},
+	}, {
+		name:             "trailing bytes after PDU",
+		inBytes:          trailing,
+		inOffset:         LSPIDOffset,
+		inOpts:           []ParseOption{WithStrictPDULength(true)},
+		wantErrSubstring: "2 trailing bytes after declared PDU length of 33 bytes",
+	}, {
+		name:             "second PDU after PDU",
+		inBytes:          append(append([]byte{}, pdu...), pdu...),
+		inOffset:         LSPIDOffset,
+		inOpts:           []ParseOption{WithStrictPDULength(true)},
+		wantErrSubstring: "33 trailing bytes",
+	}, {
+		name:             "truncated PDU",
+		inBytes:          pdu[:len(pdu)-1],
+		inOffset:         LSPIDOffset,
+		inOpts:           []ParseOption{WithStrictPDULength(true)},
+		wantErrSubstring: "exceeds 32 bytes supplied",
+	}, {
+		name:             "prefix bytes before PDU are not counted",
+		inBytes:          append([]byte{0xFF, 0xFF}, trailing...),
+		inOffset:         LSPIDOffset + 2,
+		inOpts:           []ParseOption{WithStrictPDULength(true)},
+		wantErrSubstring: "2 trailing bytes",
+	}, {
+		name:     "trailing bytes without strict option",
+		inBytes:  trailing,
+		inOffset: LSPIDOffset,
+	}, {
+		name:    "PDU length not available",
+		inBytes: append(append([]byte{}, lsp...), 0, 0),
+		inOpts:  []ParseOption{WithStrictPDULength(true)},
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := ISISBytesToLSP(tt.inBytes, tt.inOffset, tt.inOpts...)
+		if tt.wantErrSubstring != "" {
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+			}
+			if ok || got != nil {
+				t.Errorf("%s: ISISBytesToLSP(...): got parsed LSP for rejected input, got: %v, want: nil", tt.name, got)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+		}
+	}
+}
+
+func TestWithTimestampTLV(t *testing.T) {
+	// header is an LSP header consisting of the LSP ID, sequence number,
+	// checksum and flags.
+	header := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+	withTLV := func(tlv ...byte) []byte {
+		return append(append([]byte{}, header...), tlv...)
+	}
+
+	tests := []struct {
+		name            string
+		inBytes         []byte
+		inTLVType       uint8
+		wantTimestamp   time.Time
+		wantNoTimestamp bool
+		wantRaw         []byte
+	}{{
+		name:          "timestamp TLV",
+		inBytes:       withTLV(250, 8, 0x59, 0x0d, 0xd6, 0xe0, 0x00, 0x00, 0x03, 0xe8),
+		inTLVType:     250,
+		wantTimestamp: time.Date(2017, time.May, 6, 14, 0, 0, 1000, time.UTC),
+		wantRaw:       []byte{0x59, 0x0d, 0xd6, 0xe0, 0x00, 0x00, 0x03, 0xe8},
+	}, {
+		name:          "timestamp TLV using type of a known TLV",
+		inBytes:       withTLV(137, 8, 0x59, 0x0d, 0xd6, 0xe0, 0x00, 0x00, 0x00, 0x00),
+		inTLVType:     137,
+		wantTimestamp: time.Date(2017, time.May, 6, 14, 0, 0, 0, time.UTC),
+		wantRaw:       []byte{0x59, 0x0d, 0xd6, 0xe0, 0x00, 0x00, 0x00, 0x00},
+	}, {
+		name:            "unknown timestamp format",
+		inBytes:         withTLV(250, 3, 1, 2, 3),
+		inTLVType:       250,
+		wantNoTimestamp: true,
+		wantRaw:         []byte{1, 2, 3},
+	}, {
+		name:            "invalid nanoseconds",
+		inBytes:         withTLV(250, 8, 0x59, 0x0d, 0xd6, 0xe0, 0xff, 0xff, 0xff, 0xff),
+		inTLVType:       250,
+		wantNoTimestamp: true,
+		wantRaw:         []byte{0x59, 0x0d, 0xd6, 0xe0, 0xff, 0xff, 0xff, 0xff},
+	}, {
+		name:            "no timestamp TLV",
+		inBytes:         withTLV(137, 4, 'r', 't', 'r', '1'),
+		inTLVType:       250,
+		wantNoTimestamp: true,
+	}}
+
+	for _, tt := range tests {
+		lsp, ok, err := ISISBytesToLSP(tt.inBytes, 0, WithTimestampTLV(tt.inTLVType))
+		if !ok || err != nil {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP, got ok: %v, err: %v", tt.name, ok, err)
+			continue
+		}
+
+		if u := lsp.GetUndefinedTlv(tt.inTLVType); tt.wantRaw != nil && (u == nil || !bytes.Equal(u.Value, tt.wantRaw)) {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get raw timestamp TLV, got: %v, want: %v", tt.name, u, tt.wantRaw)
+		}
+
+		got, ok := LSPTimestamp(lsp, tt.inTLVType)
+		if ok == tt.wantNoTimestamp {
+			t.Errorf("%s: LSPTimestamp(...): did not get expected timestamp presence, got: %v, want: %v", tt.name, ok, !tt.wantNoTimestamp)
+			continue
+		}
+		if !got.Equal(tt.wantTimestamp) {
+			t.Errorf("%s: LSPTimestamp(...): did not get expected timestamp, got: %v, want: %v", tt.name, got, tt.wantTimestamp)
+		}
+
+		args := ISISRenderArgs{
+			NetworkInstance:  "DEFAULT",
+			ProtocolInstance: "15169",
+			Level:            2,
+			Timestamp:        time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC),
+			TimestampTLV:     tt.inTLVType,
+		}
+		wantTS := args.Timestamp.UnixNano()
+		if !tt.wantNoTimestamp {
+			wantTS = tt.wantTimestamp.UnixNano()
+		}
+		notifications, err := RenderNotifications(lsp, args)
+		if err != nil {
+			t.Errorf("%s: RenderNotifications(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+		for _, n := range notifications {
+			if n.Timestamp != wantTS {
+				t.Errorf("%s: RenderNotifications(...): did not get expected timestamp, got: %d, want: %d", tt.name, n.Timestamp, wantTS)
+			}
+		}
+	}
+}
+
+func TestWithChecksumCheck(t *testing.T) {
+	// corrupted is exampleLSP1 with its final byte altered, such that the
+	// stored checksum no longer matches its contents.
+	corrupted := append([]byte{}, exampleLSP1...)
+	corrupted[len(corrupted)-1] ^= 0xFF
+
+	// serialised is a synthetic LSP generated by LSPToISISBytes, into
+	// which the computed checksum is written.
+	serialised, err := LSPToISISBytes(&oc.Lsp{
+		LspId:          ygot.String("1920.0000.2001.00-00"),
+		SequenceNumber: ygot.Uint32(1),
+		IsType:         ygot.Uint8(3),
+	})
+	if err != nil {
+		t.Fatalf("LSPToISISBytes(...): got unexpected error: %v", err)
+	}
+	ck, err := LSPChecksum(serialised)
+	if err != nil {
+		t.Fatalf("LSPChecksum(%v): got unexpected error: %v", serialised, err)
+	}
+	binary.BigEndian.PutUint16(serialised[lspChecksumOffset:], ck)
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOpts           []ParseOption
+		wantErrSubstring string
+	}{{
+		name:    "valid checksum",
+		inBytes: exampleLSP1,
+		inOpts:  []ParseOption{WithChecksumCheck(true)},
+	}, {
+		name:    "serialised LSP with computed checksum",
+		inBytes: serialised,
+		inOpts:  []ParseOption{WithChecksumCheck(true)},
+	}, {
+		name:             "corrupted LSP with check",
+		inBytes:          corrupted,
+		inOpts:           []ParseOption{WithChecksumCheck(true)},
+		wantErrSubstring: "invalid checksum in LSP 0000.4000.ce39.00-00, got: 0x277f",
+	}, {
+		name:    "corrupted LSP without check",
+		inBytes: corrupted,
+	}}
+
+	for _, tt := range tests {
+		_, ok, err := ISISBytesToLSP(tt.inBytes, 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+	}
+}
+
+func TestISISBytesToLSPWithOpts(t *testing.T) {
+	// corrupted is exampleLSP1 with its final byte altered, such that the
+	// stored checksum no longer matches its contents.
+	corrupted := append([]byte{}, exampleLSP1...)
+	corrupted[len(corrupted)-1] ^= 0xFF
+
+	// unknownSubTLV is a synthetic LSP with a valid checksum, containing
+	// an extended IS reachability TLV with a sub-TLV of unknown type, and
+	// an undefined TLV.
+	unknown := &oc.Lsp{
+		LspId:          ygot.String("1920.0000.2001.00-00"),
+		SequenceNumber: ygot.Uint32(1),
+		IsType:         ygot.Uint8(3),
+	}
+	inst := unknown.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("1920.0000.2002.00").GetOrCreateInstance(0)
+	inst.Metric = ygot.Uint32(10)
+	inst.GetOrCreateUndefinedSubtlv(200).Value = []byte{0xde, 0xad}
+	unknown.GetOrCreateUndefinedTlv(14).Value = []byte{0x05, 0xdc}
+	unknownSubTLV, err := LSPToISISBytes(unknown)
+	if err != nil {
+		t.Fatalf("LSPToISISBytes(...): got unexpected error: %v", err)
+	}
+	ck, err := LSPChecksum(unknownSubTLV)
+	if err != nil {
+		t.Fatalf("LSPChecksum(%v): got unexpected error: %v", unknownSubTLV, err)
+	}
+	binary.BigEndian.PutUint16(unknownSubTLV[lspChecksumOffset:], ck)
+
+	// Each input contains an undefined TLV of type 14.
+	inputs := []struct {
+		name          string
+		inBytes       []byte
+		badChecksum   bool
+		unknownSubTLV bool
+	}{{
+		name:    "valid LSP",
+		inBytes: exampleLSP1,
+	}, {
+		name:        "corrupted LSP",
+		inBytes:     corrupted,
+		badChecksum: true,
+	}, {
+		name:          "LSP with unknown sub-TLV",
+		inBytes:       unknownSubTLV,
+		unknownSubTLV: true,
+	}}
+
+	for _, in := range inputs {
+		for _, validate := range []bool{false, true} {
+			for _, strict := range []bool{false, true} {
+				for _, discard := range []bool{false, true} {
+					opts := ISISBytesToLSPOpts{
+						ValidateChecksum: validate,
+						Strict:           strict,
+						DiscardUnknown:   discard,
+					}
+					t.Run(fmt.Sprintf("%s/%+v", in.name, opts), func(t *testing.T) {
+						checksumErr := validate && in.badChecksum
+						unknownErr := strict && in.unknownSubTLV
+						wantErr := checksumErr || unknownErr
+						wantOK := !(strict && wantErr)
+
+						got, ok, err := ISISBytesToLSPWithOpts(in.inBytes, opts)
+						if (err != nil) != wantErr {
+							t.Fatalf("ISISBytesToLSPWithOpts(%v): got error: %v, want error: %v", in.name, err, wantErr)
+						}
+						if ok != wantOK {
+							t.Fatalf("ISISBytesToLSPWithOpts(%v): got ok: %v, want: %v", in.name, ok, wantOK)
+						}
+						if !ok {
+							if got != nil {
+								t.Errorf("ISISBytesToLSPWithOpts(%v): got non-nil LSP when parsing failed: %v", in.name, got)
+							}
+							return
+						}
+
+						if gotUndef := got.GetUndefinedTlv(14) != nil; gotUndef != !discard {
+							t.Errorf("ISISBytesToLSPWithOpts(%v): got undefined TLV 14: %v, want: %v", in.name, gotUndef, !discard)
+						}
+					})
+				}
+			}
+		}
+	}
+
+	// The offset is applied to the input in the same manner as
+	// ISISBytesToLSP.
+	padded := append([]byte{0xff, 0xff}, exampleLSP1...)
+	got, ok, err := ISISBytesToLSPWithOpts(padded, ISISBytesToLSPOpts{Offset: 2})
+	if !ok || err != nil {
+		t.Fatalf("ISISBytesToLSPWithOpts(padded, Offset: 2): got unexpected error: %v", err)
+	}
+	want, _, _ := ISISBytesToLSP(exampleLSP1, 0)
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("ISISBytesToLSPWithOpts(padded, Offset: 2): did not get expected LSP, diff(-got,+want):\n%s", diff)
+	}
+
+	// The zero value of the options parses the LSP in the same manner as
+	// ISISBytesToLSP.
+	got, ok, err = ISISBytesToLSPWithOpts(exampleLSP1, ISISBytesToLSPOpts{})
+	if !ok || err != nil {
+		t.Fatalf("ISISBytesToLSPWithOpts(exampleLSP1, {}): got unexpected error: %v", err)
+	}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("ISISBytesToLSPWithOpts(exampleLSP1, {}): did not get expected LSP, diff(-got,+want):\n%s", diff)
+	}
+}
+
+func TestWithFatalTLVTypes(t *testing.T) {
+	// lsp is an LSP containing a Router Capability TLV with a node MSD
+	// sub-TLV of invalid length, followed by a Dynamic Hostname TLV.
+	lsp := []byte{
+		0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03,
+		242, 8,
+		192, 0, 2, 1, 0,
+		23, 1, 1,
+		137, 3, 'r', 't', 'r',
+	}
+
+	tests := []struct {
+		name             string
+		inOpts           []ParseOption
+		wantOK           bool
+		wantErrSubstring string
+	}{{
+		name:             "no fatal TLV types",
+		wantOK:           true,
+		wantErrSubstring: "invalid MSD sub-TLV",
+	}, {
+		name:             "router capability TLV is fatal",
+		inOpts:           []ParseOption{WithFatalTLVTypes(22, 242)},
+		wantErrSubstring: "fatal error in TLV 242: invalid MSD sub-TLV",
+	}, {
+		name:             "other TLV types are fatal",
+		inOpts:           []ParseOption{WithFatalTLVTypes(22, 135)},
+		wantOK:           true,
+		wantErrSubstring: "invalid MSD sub-TLV",
+	}}
+
+	for _, tt := range tests {
+		got, ok, err := ISISBytesToLSP(lsp, 0, tt.inOpts...)
+		if ok != tt.wantOK {
+			t.Errorf("%s: ISISBytesToLSP(...): got unexpected ok, got: %v, want: %v", tt.name, ok, tt.wantOK)
+		}
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+		if !tt.wantOK {
+			if got != nil {
+				t.Errorf("%s: ISISBytesToLSP(...): got LSP for fatal error, got: %v", tt.name, got)
+			}
+			continue
+		}
+		if n := got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetHostname(); n == nil || len(n.Hostname) != 1 || n.Hostname[0] != "rtr" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not parse TLVs following the error, got hostname: %v", tt.name, n)
+		}
+	}
+}
+
+func TestWithParseStats(t *testing.T) {
+	// lsp is an LSP containing an Extended IS Reachability TLV with a known
+	// IPv4 interface address sub-TLV and two unknown sub-TLVs, a Router
+	// Capability TLV with one unknown sub-TLV, and an Extended IPv4
+	// Reachability TLV with a known tag sub-TLV and one unknown sub-TLV.
+	lsp := []byte{
+		0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03,
+		22, 23,
+		0, 0, 0x40, 0, 0xce, 0x3a, 0, 0, 0, 10, 12,
+		6, 4, 192, 0, 2, 1,
+		200, 2, 0xab, 0xcd,
+		201, 0,
+		242, 8,
+		192, 0, 2, 1, 0,
+		250, 1, 0xff,
+		135, 18,
+		0, 0, 0, 10, 0x58, 198, 51, 100, 9,
+		1, 4, 0, 0, 0, 1,
+		99, 1, 0,
+	}
+
+	stats := &ParseStats{}
+	if _, ok, err := ISISBytesToLSP(lsp, 0, WithParseStats(stats)); !ok {
+		t.Fatalf("ISISBytesToLSP(..., WithParseStats): could not parse LSP: %v", err)
+	}
+
+	want := map[uint8]int{22: 2, 135: 1, 242: 1}
+	if diff := pretty.Compare(stats.UnknownSubTLVs, want); diff != "" {
+		t.Errorf("ISISBytesToLSP(..., WithParseStats): did not get expected unknown sub-TLV counts, diff(-got,+want):\n%s", diff)
+	}
+
+	// Statistics are accumulated across LSPs that share a ParseStats.
+	if _, ok, err := ISISBytesToLSP(lsp, 0, WithParseStats(stats)); !ok {
+		t.Fatalf("ISISBytesToLSP(..., WithParseStats): could not parse LSP: %v", err)
+	}
+	for typ, n := range want {
+		if got := stats.UnknownSubTLVs[typ]; got != 2*n {
+			t.Errorf("ISISBytesToLSP(..., WithParseStats): did not accumulate unknown sub-TLVs for TLV type %d, got: %d, want: %d", typ, got, 2*n)
+		}
+	}
+}
+
+func TestWithTLVTimings(t *testing.T) {
+	tests := []struct {
+		name    string
+		inBytes []byte
+	}{{
+		name:    "vendor c example #1",
+		inBytes: exampleLSP1,
+	}, {
+		name:    "vendor c example #3",
+		inBytes: exampleLSP3,
+	}}
+
+	for _, tt := range tests {
+		wantTypes := map[uint8]bool{}
+		if err := ParseTLVStream(tt.inBytes, 0, func(tlvType uint8, _ []byte) error {
+			wantTypes[tlvType] = true
+			return nil
+		}); err != nil {
+			t.Errorf("%s: ParseTLVStream(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		timings := map[uint8]time.Duration{}
+		got, ok, err := ISISBytesToLSP(tt.inBytes, 0, WithTLVTimings(timings))
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(..., WithTLVTimings): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		for typ := range wantTypes {
+			if _, ok := timings[typ]; !ok {
+				t.Errorf("%s: ISISBytesToLSP(..., WithTLVTimings): did not record timing for TLV type %d, got: %v", tt.name, typ, timings)
+			}
+		}
+		for typ := range timings {
+			if !wantTypes[typ] {
+				t.Errorf("%s: ISISBytesToLSP(..., WithTLVTimings): recorded timing for TLV type %d which is not in the LSP", tt.name, typ)
+			}
+		}
+
+		want, _, _ := ISISBytesToLSP(tt.inBytes, 0)
+		if diff := pretty.Compare(got, want); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(..., WithTLVTimings): LSP differs from LSP parsed without timings, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestParseTLV(t *testing.T) {
+	type tlv struct {
+		tlvType uint8
+		value   []byte
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		inTLVs           []tlv
+		inOpts           []ParseOption
+		wantLSP          *oc.Lsp
+		wantErrSubstring string
+	}{{
+		name:  "hostname and area address TLVs",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 137,
+			value:   []byte("re0-pr05.sql88"),
+		}, {
+			tlvType: 1,
+			value:   []byte{0x03, 0x49, 0x00, 0x01},
+		}},
+		wantLSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+					Hostname: &oc.Lsp_Tlv_Hostname{
+						Hostname: []string{"re0-pr05.sql88"},
+					},
+				},
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES,
+					AreaAddress: &oc.Lsp_Tlv_AreaAddress{
+						Address: []string{"49.0001"},
+					},
+				},
+			},
+		},
+	}, {
+		name:  "unknown TLV",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 250,
+			value:   []byte{0x01, 0x02},
+		}},
+		wantLSP: &oc.Lsp{
+			UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+				250: {
+					Type:   ygot.Uint8(250),
+					Length: ygot.Uint8(2),
+					Value:  []byte{0x01, 0x02},
+				},
+			},
+		},
+	}, {
+		name:  "repeated unknown TLV",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 250,
+			value:   []byte{0x01, 0x02},
+		}, {
+			tlvType: 250,
+			value:   []byte{0x03},
+		}},
+		wantErrSubstring: "duplicate undefined TLV of type 250, retaining first instance",
+		wantLSP: &oc.Lsp{
+			UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+				250: {
+					Type:   ygot.Uint8(250),
+					Length: ygot.Uint8(2),
+					Value:  []byte{0x01, 0x02},
+				},
+			},
+		},
+	}, {
+		name:  "unknown TLV discarded",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 250,
+			value:   []byte{0x01, 0x02},
+		}},
+		inOpts:  []ParseOption{WithDiscardUnknownTLVs(true)},
+		wantLSP: &oc.Lsp{},
+	}, {
+		name:  "invalid TLV",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 134,
+			value:   []byte{0x01},
+		}},
+		wantLSP:          &oc.Lsp{},
+		wantErrSubstring: "invalid length Router ID TLV",
+	}, {
+		name:  "oversized TLV",
+		inLSP: &oc.Lsp{},
+		inTLVs: []tlv{{
+			tlvType: 137,
+			value:   make([]byte, 256),
+		}},
+		wantLSP:          &oc.Lsp{},
+		wantErrSubstring: "must be less than 256 bytes",
+	}, {
+		name: "nil LSP",
+		inTLVs: []tlv{{
+			tlvType: 137,
+			value:   []byte("router"),
+		}},
+		wantErrSubstring: "nil LSP",
+	}}
+
+	for _, tt := range tests {
+		var err error
+		for _, r := range tt.inTLVs {
+			if err = ParseTLV(tt.inLSP, r.tlvType, r.value, tt.inOpts...); err != nil {
+				break
+			}
+		}
+
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ParseTLV(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if diff := pretty.Compare(tt.inLSP, tt.wantLSP); diff != "" {
+			t.Errorf("%s: ParseTLV(...): did not get expected LSP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestRouterIDConsistencyCheck(t *testing.T) {
+	// lspHeader is the LSP ID, sequence number, checksum and flags of the
+	// LSPs used in the test.
+	lspHeader := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+
+	tests := []struct {
+		name             string
+		inTLVs           []byte
+		inOpts           []ParseOption
+		wantErrSubstring string
+	}{{
+		name: "disagreeing router IDs",
+		inTLVs: []byte{
+			134, 4, 10, 0, 0, 1,
+			242, 5, 10, 0, 0, 2, 0,
+		},
+		inOpts:           []ParseOption{WithRouterIDConsistencyCheck(true)},
+		wantErrSubstring: "router ID 10.0.0.2 in Router Capability TLV does not match IPv4 TE router IDs [10.0.0.1]",
+	}, {
+		name: "matching router IDs",
+		inTLVs: []byte{
+			134, 4, 10, 0, 0, 1,
+			242, 5, 10, 0, 0, 1, 0,
+		},
+		inOpts: []ParseOption{WithRouterIDConsistencyCheck(true)},
+	}, {
+		name: "zero capability router ID",
+		inTLVs: []byte{
+			134, 4, 10, 0, 0, 1,
+			242, 5, 0, 0, 0, 0, 0,
+		},
+		inOpts: []ParseOption{WithRouterIDConsistencyCheck(true)},
+	}, {
+		name: "no TE router ID",
+		inTLVs: []byte{
+			242, 5, 10, 0, 0, 2, 0,
+		},
+		inOpts: []ParseOption{WithRouterIDConsistencyCheck(true)},
+	}, {
+		name: "disagreeing router IDs without check",
+		inTLVs: []byte{
+			134, 4, 10, 0, 0, 1,
+			242, 5, 10, 0, 0, 2, 0,
+		},
+	}}
+
+	for _, tt := range tests {
+		_, ok, err := ISISBytesToLSP(append(append([]byte{}, lspHeader...), tt.inTLVs...), 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+	}
+}
+
+func TestWithRawTLVRange(t *testing.T) {
+	// lspHeader is the LSP ID, sequence number, checksum and flags of the
+	// LSPs used in the test.
+	lspHeader := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+
+	hostnameTLV := &oc.Lsp_Tlv{
+		Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+		Hostname: &oc.Lsp_Tlv_Hostname{
+			Hostname: []string{"r1"},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		inTLVs            []byte
+		inOpts            []ParseOption
+		wantTLVs          map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv
+		wantUndefinedTLVs map[uint8]*oc.Lsp_UndefinedTlv
+		// wantAllUndefined is the expected result of UndefinedTLVs, checked
+		// when non-nil.
+		wantAllUndefined map[uint8][][]byte
+		wantErrSubstring string
+	}{{
+		name:   "experimental TLV in raw range",
+		inTLVs: []byte{250, 3, 0xDE, 0xAD, 0xBE, 137, 2, 'r', '1'},
+		inOpts: []ParseOption{WithRawTLVRange(250, 254)},
+		wantTLVs: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+			oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: hostnameTLV,
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(3),
+				Value:  oc.Binary{0xDE, 0xAD, 0xBE},
+			},
+		},
+	}, {
+		name:   "experimental TLV outside raw range",
+		inTLVs: []byte{250, 3, 0xDE, 0xAD, 0xBE, 137, 2, 'r', '1'},
+		inOpts: []ParseOption{WithRawTLVRange(251, 254)},
+		wantTLVs: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+			oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: hostnameTLV,
+		},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(3),
+				Value:  oc.Binary{0xDE, 0xAD, 0xBE},
+			},
+		},
+	}, {
+		name:   "known TLV outside raw range",
+		inTLVs: []byte{137, 2, 'r', '1'},
+		inOpts: []ParseOption{WithRawTLVRange(250, 254)},
+		wantTLVs: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+			oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: hostnameTLV,
+		},
+	}, {
+		name:   "known TLV in raw range",
+		inTLVs: []byte{137, 2, 'r', '1'},
+		inOpts: []ParseOption{WithRawTLVRange(137, 137)},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			137: {
+				Type:   ygot.Uint8(137),
+				Length: ygot.Uint8(2),
+				Value:  oc.Binary{'r', '1'},
+			},
+		},
+	}, {
+		name:   "repeated TLV in raw range",
+		inTLVs: []byte{250, 1, 0x01, 250, 1, 0x02},
+		inOpts: []ParseOption{WithRawTLVRange(250, 254)},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			250: {
+				Type:   ygot.Uint8(250),
+				Length: ygot.Uint8(1),
+				Value:  oc.Binary{0x01},
+			},
+		},
+		wantAllUndefined: map[uint8][][]byte{
+			250: {{0x01}, {0x02}},
+		},
+	}, {
+		name:   "repeated known TLV in raw range",
+		inTLVs: []byte{137, 2, 'r', '1', 137, 2, 'r', '2'},
+		inOpts: []ParseOption{WithRawTLVRange(137, 137)},
+		wantUndefinedTLVs: map[uint8]*oc.Lsp_UndefinedTlv{
+			137: {
+				Type:   ygot.Uint8(137),
+				Length: ygot.Uint8(2),
+				Value:  oc.Binary{'r', '1'},
+			},
+		},
+		wantAllUndefined: map[uint8][][]byte{
+			137: {{'r', '1'}, {'r', '2'}},
+		},
+	}}
+
+	for _, tt := range tests {
+		in := append(append([]byte{}, lspHeader...), tt.inTLVs...)
+		got, ok, err := ISISBytesToLSP(in, 0, tt.inOpts...)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(...): could not parse LSP: %v", tt.name, err)
+			continue
+		}
+
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected error, %s", tt.name, diff)
+		}
+
+		if diff := pretty.Compare(got.Tlv, tt.wantTLVs); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected TLVs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		if diff := pretty.Compare(got.UndefinedTlv, tt.wantUndefinedTLVs); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(...): did not get expected undefined TLVs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+
+		if tt.wantAllUndefined == nil {
+			continue
+		}
+		all, err := UndefinedTLVs(in, 0, tt.inOpts...)
+		if err != nil {
+			t.Errorf("%s: UndefinedTLVs(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+		if diff := pretty.Compare(all, tt.wantAllUndefined); diff != "" {
+			t.Errorf("%s: UndefinedTLVs(...): did not get expected TLVs, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+
+	// Repeated TLVs within the raw range that are not stored are reported
+	// when parsing strictly.
+	in := append(append([]byte{}, lspHeader...), 250, 1, 0x01, 250, 1, 0x02)
+	if _, ok, err := ISISBytesToLSP(in, 0, WithRawTLVRange(250, 254), WithStrictParsing(true)); ok || err == nil {
+		t.Errorf("ISISBytesToLSP(%v, WithStrictParsing(true)): got ok: %v, err: %v, want: false, non-nil", in, ok, err)
+	}
+}
+
+// reachabilityLSP returns an LSP, starting at the LSP ID field, that contains
+// area address, NLPID and hostname TLVs, along with v4 IPv4 /24 prefixes in
+// Extended IP Reachability TLVs, and v6 IPv6 /64 prefixes in IPv6
+// Reachability TLVs. It reflects the shape of a typical production LSP.
+func reachabilityLSP(v4, v6 int) []byte {
+	b := []byte{0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03}
+	b = append(b, 1, 4, 3, 0x49, 0, 1)
+	b = append(b, 129, 2, 0xcc, 0x8e)
+	b = append(b, 137, 4, 'r', 't', 'r', '1')
+
+	// appendTLVs adds TLVs of type t containing n entries generated by
+	// entry, packing as many entries into each TLV as possible.
+	appendTLVs := func(t uint8, n int, entry func(i int) []byte) {
+		var v []byte
+		for i := 0; i < n; i++ {
+			e := entry(i)
+			if len(v)+len(e) > 255 {
+				b = append(append(b, t, uint8(len(v))), v...)
+				v = nil
+			}
+			v = append(v, e...)
+		}
+		if len(v) != 0 {
+			b = append(append(b, t, uint8(len(v))), v...)
+		}
+	}
+
+	appendTLVs(135, v4, func(i int) []byte {
+		// Metric, control byte with a prefix length of 24, and 3 bytes
+		// of prefix.
+		return []byte{0, 0, 0, 10, 24, 10, uint8(i >> 8), uint8(i)}
+	})
+	appendTLVs(236, v6, func(i int) []byte {
+		// Metric, control byte, prefix length of 64, and 8 bytes of
+		// prefix.
+		return []byte{0, 0, 0, 10, 0, 64, 0x20, 0x01, 0x0d, 0xb8, 0, 0, uint8(i >> 8), uint8(i)}
+	})
+	return b
+}
+
+func TestReachabilityLSP(t *testing.T) {
+	got, ok, err := ISISBytesToLSP(reachabilityLSP(300, 200), 0)
+	if !ok || err != nil {
+		t.Fatalf("ISISBytesToLSP(reachabilityLSP(300, 200)): could not parse LSP, err: %v", err)
+	}
+
+	if n := len(got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability().Prefix); n != 300 {
+		t.Errorf("ISISBytesToLSP(reachabilityLSP(300, 200)): did not get expected number of IPv4 prefixes, got: %d, want: 300", n)
+	}
+
+	if n := len(got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetIpv6Reachability().Prefix); n != 200 {
+		t.Errorf("ISISBytesToLSP(reachabilityLSP(300, 200)): did not get expected number of IPv6 prefixes, got: %d, want: 200", n)
+	}
+}
+
+func BenchmarkReachabilityLSP(b *testing.B) {
+	lsp := reachabilityLSP(300, 200)
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		if _, _, err := ISISBytesToLSP(lsp, 0); err != nil {
+			b.Fatalf("ISISBytesToLSP(...): got unexpected error: %v", err)
+		}
+	}
+}
+
+var updateGolden = flag.Bool("update_golden", false, "rewrite the golden files for the LSP captures in testdata/captures")
+
+// captureRenderArgs are the arguments used to render the LSP captures in
+// testdata/captures.
+var captureRenderArgs = ISISRenderArgs{
+	NetworkInstance:  "DEFAULT",
+	ProtocolInstance: "15169",
+	Level:            2,
+}
+
+// decodeCaptureHex decodes a hexadecimal LSP capture, in which bytes may be
+// separated by colons or whitespace (including newlines), as is output by
+// common packet capture tools.
+func decodeCaptureHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.Join(strings.Fields(strings.Replace(s, ":", " ", -1)), ""))
+}
+
+// parseAndRender decodes the hexadecimal LSP capture supplied, which starts
+// at the LSP ID, parses it, and renders it to a flat map of gNMI path to
+// value using the args supplied. The result is returned after a round trip
+// through JSON, such that it can be compared directly to a golden file.
+func parseAndRender(hexCapture string, args ISISRenderArgs) (map[string]interface{}, error) {
+	b, err := decodeCaptureHex(hexCapture)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode capture: %v", err)
+	}
+
+	lsp, ok, err := ISISBytesToLSP(b, 0)
+	if !ok {
+		return nil, fmt.Errorf("cannot parse LSP: %v", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("got non-fatal errors parsing LSP: %v", err)
+	}
+
+	flat, err := RenderFlatMap(lsp, args)
+	if err != nil {
+		return nil, fmt.Errorf("cannot render LSP: %v", err)
+	}
+
+	j, err := json.Marshal(flat)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal rendered LSP to JSON: %v", err)
+	}
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(j, &got); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal rendered LSP JSON: %v", err)
+	}
+	return got, nil
+}
+
+// TestCaptures parses each LSP capture in testdata/captures, and compares
+// the rendered output to the golden file alongside it. A capture named
+// <name>.hex contains the LSP as hexadecimal bytes, and <name>.json contains
+// the expected output of parseAndRender. To add a capture, write the .hex
+// file and run the test with -update_golden to generate the .json file, and
+// check the result by hand.
+func TestCaptures(t *testing.T) {
+	captures, err := filepath.Glob(filepath.Join("testdata", "captures", "*.hex"))
+	if err != nil {
+		t.Fatalf("cannot list captures: %v", err)
+	}
+	if len(captures) == 0 {
+		t.Fatalf("did not find any captures in testdata/captures")
+	}
+
+	for _, c := range captures {
+		name := strings.TrimSuffix(filepath.Base(c), ".hex")
+		golden := strings.TrimSuffix(c, ".hex") + ".json"
+
+		in, err := ioutil.ReadFile(c)
+		if err != nil {
+			t.Errorf("%s: cannot read capture: %v", name, err)
+			continue
+		}
+
+		got, err := parseAndRender(string(in), captureRenderArgs)
+		if err != nil {
+			t.Errorf("%s: parseAndRender(...): got unexpected error: %v", name, err)
+			continue
+		}
+
+		if *updateGolden {
+			j, err := json.MarshalIndent(got, "", "  ")
+			if err != nil {
+				t.Errorf("%s: cannot marshal golden file: %v", name, err)
+				continue
+			}
+			if err := ioutil.WriteFile(golden, append(j, '\n'), 0644); err != nil {
+				t.Errorf("%s: cannot write golden file: %v", name, err)
+			}
+			continue
+		}
+
+		wantJSON, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Errorf("%s: cannot read golden file: %v", name, err)
+			continue
+		}
+		want := map[string]interface{}{}
+		if err := json.Unmarshal(wantJSON, &want); err != nil {
+			t.Errorf("%s: cannot unmarshal golden file: %v", name, err)
+			continue
+		}
+
+		if diff := pretty.Compare(got, want); diff != "" {
+			t.Errorf("%s: parseAndRender(...): did not get expected output, diff(-got,+want):\n%s", name, diff)
+		}
+	}
+}
+
+func TestDecodeCaptureHex(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    []byte
+		wantErr bool
+	}{{
+		name: "colon delimited",
+		in:   "00:01:ab",
+		want: []byte{0x00, 0x01, 0xab},
+	}, {
+		name: "space and newline delimited",
+		in:   "00 01\nab  CD\n",
+		want: []byte{0x00, 0x01, 0xab, 0xcd},
+	}, {
+		name:    "invalid character",
+		in:      "00:zz",
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := decodeCaptureHex(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: decodeCaptureHex(%q): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: decodeCaptureHex(%q): did not get expected bytes, diff(-got,+want):\n%s", tt.name, tt.in, diff)
 		}
 	}
 }
@@ -0,0 +1,137 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// nodeMSDSubTLVType is the type of the node maximum SID depth (MSD) sub-TLV
+// of the Router Capability TLV (242), defined in RFC8491.
+const nodeMSDSubTLVType uint8 = 23
+
+// linkMSDSubTLVType is the type of the link MSD sub-TLV of the Extended IS
+// Reachability TLV (22), defined in RFC8491.
+const linkMSDSubTLVType uint8 = 15
+
+// MSDType is the type of a maximum SID depth (MSD) advertisement, as carried
+// in the MSD sub-TLVs defined in RFC8491.
+type MSDType uint8
+
+const (
+	// MSDTypeBaseMPLSImposition is the base MPLS imposition MSD, defined in
+	// RFC8491.
+	MSDTypeBaseMPLSImposition MSDType = 1
+	// MSDTypeERLD is the entropy readable label depth, defined in RFC9088.
+	MSDTypeERLD MSDType = 2
+	// MSDTypeSRv6MaxSegmentsLeft is the maximum value of the segments left
+	// field of an SRH that can be processed, defined in RFC9352.
+	MSDTypeSRv6MaxSegmentsLeft MSDType = 41
+	// MSDTypeSRv6MaxEndPop is the maximum number of SIDs in the SRH to which
+	// the node can apply PSP or USP behaviour, defined in RFC9352.
+	MSDTypeSRv6MaxEndPop MSDType = 42
+	// MSDTypeSRv6MaxHEncaps is the maximum number of SIDs that can be
+	// pushed as part of an H.Encaps behaviour, defined in RFC9352.
+	MSDTypeSRv6MaxHEncaps MSDType = 44
+	// MSDTypeSRv6MaxEndD is the maximum number of SIDs in an SRH when
+	// performing decapsulation, defined in RFC9352.
+	MSDTypeSRv6MaxEndD MSDType = 45
+)
+
+// String returns a human-readable name for the MSD type.
+func (m MSDType) String() string {
+	switch m {
+	case MSDTypeBaseMPLSImposition:
+		return "BASE_MPLS_IMPOSITION"
+	case MSDTypeERLD:
+		return "ERLD"
+	case MSDTypeSRv6MaxSegmentsLeft:
+		return "SRV6_MAX_SEGMENTS_LEFT"
+	case MSDTypeSRv6MaxEndPop:
+		return "SRV6_MAX_END_POP"
+	case MSDTypeSRv6MaxHEncaps:
+		return "SRV6_MAX_H_ENCAPS"
+	case MSDTypeSRv6MaxEndD:
+		return "SRV6_MAX_END_D"
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint8(m))
+}
+
+// IsSRv6 returns true if the MSD type is one of the SRv6 MSD types defined in
+// RFC9352.
+func (m MSDType) IsSRv6() bool {
+	switch m {
+	case MSDTypeSRv6MaxSegmentsLeft, MSDTypeSRv6MaxEndPop, MSDTypeSRv6MaxHEncaps, MSDTypeSRv6MaxEndD:
+		return true
+	}
+	return false
+}
+
+// MSD is a single maximum SID depth advertisement.
+type MSD struct {
+	// Type is the type of the MSD.
+	Type MSDType
+	// Value is the maximum SID depth that is advertised.
+	Value uint8
+}
+
+// parseMSDSubTLV parses an MSD sub-TLV, which consists of a set of 2-byte
+// (MSD-type, MSD-value) pairs. Returns an error if the length of the sub-TLV
+// is not a multiple of 2.
+func parseMSDSubTLV(r *rawTLV) ([]MSD, error) {
+	if len(r.Value)%2 != 0 {
+		return nil, fmt.Errorf("invalid MSD sub-TLV, length was not a multiple of 2: %d", len(r.Value))
+	}
+
+	msds := []MSD{}
+	for x := 0; x < len(r.Value); x += 2 {
+		msds = append(msds, MSD{Type: MSDType(r.Value[x]), Value: r.Value[x+1]})
+	}
+	return msds, nil
+}
+
+// NodeMSDs returns the node MSDs, including the SRv6 MSDs, that are advertised
+// in the Router Capability TLV supplied, decoded from its undefined sub-TLVs.
+// Returns nil if no node MSD sub-TLV is present, or an error if it cannot be
+// decoded.
+func NodeMSDs(c *oc.Lsp_Tlv_Capability) ([]MSD, error) {
+	u := c.GetUndefinedSubtlv(nodeMSDSubTLVType)
+	if u == nil {
+		return nil, nil
+	}
+	return parseMSDSubTLV(&rawTLV{
+		Type:   nodeMSDSubTLVType,
+		Length: uint8(len(u.Value)),
+		Value:  u.Value,
+	})
+}
+
+// LinkMSDs returns the link MSDs that are advertised for the Extended IS
+// Reachability neighbor instance supplied, decoded from its undefined
+// sub-TLVs. Returns nil if no link MSD sub-TLV is present, or an error if it
+// cannot be decoded.
+func LinkMSDs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) ([]MSD, error) {
+	u := n.GetUndefinedSubtlv(linkMSDSubTLVType)
+	if u == nil {
+		return nil, nil
+	}
+	return parseMSDSubTLV(&rawTLV{
+		Type:   linkMSDSubTLVType,
+		Length: uint8(len(u.Value)),
+		Value:  u.Value,
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+func TestNodeMSDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		inValue []byte
+		want    []MSD
+		wantErr bool
+	}{{
+		name:    "base MPLS imposition only",
+		inValue: []byte{1, 10},
+		want: []MSD{
+			{Type: MSDTypeBaseMPLSImposition, Value: 10},
+		},
+	}, {
+		name:    "SRv6 max SL and max end pop",
+		inValue: []byte{41, 10, 42, 6},
+		want: []MSD{
+			{Type: MSDTypeSRv6MaxSegmentsLeft, Value: 10},
+			{Type: MSDTypeSRv6MaxEndPop, Value: 6},
+		},
+	}, {
+		name:    "base MPLS imposition and SRv6 max H.Encaps",
+		inValue: []byte{1, 12, 44, 3},
+		want: []MSD{
+			{Type: MSDTypeBaseMPLSImposition, Value: 12},
+			{Type: MSDTypeSRv6MaxHEncaps, Value: 3},
+		},
+	}, {
+		name:    "empty",
+		inValue: []byte{},
+		want:    []MSD{},
+	}, {
+		name:    "odd length",
+		inValue: []byte{41, 10, 42},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		// Router capability TLV with router ID 192.0.2.1, no flags and a node
+		// MSD sub-TLV.
+		in := append([]byte{192, 0, 2, 1, 0, nodeMSDSubTLVType, uint8(len(tt.inValue))}, tt.inValue...)
+		i := newISISLSP()
+		err := i.processCapabilityTLV(&rawTLV{Type: 242, Length: uint8(len(in)), Value: in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: processCapabilityTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, in, err, tt.wantErr)
+			continue
+		}
+
+		got, err := NodeMSDs(i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY).GetCapability(0))
+		if err != nil {
+			t.Errorf("%s: NodeMSDs(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: NodeMSDs(...): did not get expected result, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestLinkMSDs(t *testing.T) {
+	tests := []struct {
+		name      string
+		inSubTLVs []*rawTLV
+		want      []MSD
+		wantErr   bool
+	}{{
+		name: "base MPLS imposition",
+		inSubTLVs: []*rawTLV{{
+			Type:   linkMSDSubTLVType,
+			Length: 2,
+			Value:  []byte{1, 8},
+		}},
+		want: []MSD{
+			{Type: MSDTypeBaseMPLSImposition, Value: 8},
+		},
+	}, {
+		name: "base MPLS imposition and ERLD",
+		inSubTLVs: []*rawTLV{{
+			Type:   linkMSDSubTLVType,
+			Length: 4,
+			Value:  []byte{1, 8, 2, 4},
+		}},
+		want: []MSD{
+			{Type: MSDTypeBaseMPLSImposition, Value: 8},
+			{Type: MSDTypeERLD, Value: 4},
+		},
+	}, {
+		name: "no link MSD",
+	}, {
+		name: "misaligned length",
+		inSubTLVs: []*rawTLV{{
+			Type:   linkMSDSubTLVType,
+			Length: 3,
+			Value:  []byte{1, 8, 2},
+		}},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		inst := &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{}
+		err := parseExtendedISReachSubTLVs(inst, tt.inSubTLVs)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parseExtendedISReachSubTLVs(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.inSubTLVs, err, tt.wantErr)
+			continue
+		}
+
+		got, err := LinkMSDs(inst)
+		if err != nil {
+			t.Errorf("%s: LinkMSDs(%v): got unexpected error: %v", tt.name, inst, err)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: LinkMSDs(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, inst, diff)
+		}
+	}
+}
+
+func TestMSDType(t *testing.T) {
+	tests := []struct {
+		in         MSDType
+		wantString string
+		wantSRv6   bool
+	}{
+		{MSDTypeBaseMPLSImposition, "BASE_MPLS_IMPOSITION", false},
+		{MSDTypeERLD, "ERLD", false},
+		{MSDTypeSRv6MaxSegmentsLeft, "SRV6_MAX_SEGMENTS_LEFT", true},
+		{MSDTypeSRv6MaxEndPop, "SRV6_MAX_END_POP", true},
+		{MSDTypeSRv6MaxHEncaps, "SRV6_MAX_H_ENCAPS", true},
+		{MSDTypeSRv6MaxEndD, "SRV6_MAX_END_D", true},
+		{MSDType(43), "UNKNOWN(43)", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.wantString {
+			t.Errorf("MSDType(%d).String(): got: %s, want: %s", uint8(tt.in), got, tt.wantString)
+		}
+		if got := tt.in.IsSRv6(); got != tt.wantSRv6 {
+			t.Errorf("MSDType(%d).IsSRv6(): got: %v, want: %v", uint8(tt.in), got, tt.wantSRv6)
+		}
+	}
+}
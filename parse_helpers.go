@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"strconv"
+	"strings"
 
 	"github.com/openconfig/lsdbparse/pkg/oc"
 	"github.com/openconfig/ygot/ygot"
@@ -34,12 +36,7 @@ func binaryToUint32(n []byte) (uint32, error) {
 		return 0, fmt.Errorf("input byte array was incorrect length: %d != 4", len(n))
 	}
 
-	var u uint32
-	if err := binary.Read(bytes.NewBuffer(n), binary.BigEndian, &u); err != nil {
-		return 0, err
-	}
-
-	return u, nil
+	return binary.BigEndian.Uint32(n), nil
 }
 
 // binaryToFloat32 takes an input byte slice, length 4, and parses it as a big
@@ -54,6 +51,59 @@ func binaryToFloat32(n []byte) (float32, error) {
 	return math.Float32frombits(u), nil
 }
 
+const (
+	// multiTopologyTLVType is the type of the Multi-Topology TLV, defined
+	// in RFC5120.
+	multiTopologyTLVType uint8 = 229
+	// mtIDMask is the mask for the 12-bit multi-topology identifier that is
+	// carried in the 2-byte MT ID field of the MT TLVs defined in RFC5120.
+	mtIDMask uint16 = 0x0FFF
+	// mtOverloadBit and mtAttachedBit are the O and A bits that are carried
+	// in the upper bits of the MT ID field. They are only present in the
+	// Multi-Topology TLV (229).
+	mtOverloadBit uint16 = 0x8000
+	mtAttachedBit uint16 = 0x4000
+)
+
+// mtID extracts the 12-bit multi-topology identifier from the 2-byte MT ID
+// field that prefixes the entries of the MT IS reachability (222), MT IPv4
+// reachability (235) and MT IPv6 reachability (237) TLVs. In these TLVs the
+// upper 4 bits of the field are reserved and are hence masked rather than
+// interpreted. Returns an error if the input is not 2 bytes long.
+func mtID(b []byte) (uint16, error) {
+	if len(b) != 2 {
+		return 0, fmt.Errorf("MT ID must be 2 bytes, got: %d", len(b))
+	}
+	return binary.BigEndian.Uint16(b) & mtIDMask, nil
+}
+
+// mtEntry parses a 2-byte entry of the Multi-Topology TLV (229). Unlike the
+// MT reachability TLVs, the upper bits of the MT ID field in TLV 229 carry
+// the overload (O, bit 0) and attached (A, bit 1) flags for the topology.
+// It returns the 12-bit MT ID, and bools indicating whether the O and A bits
+// are set, or an error if the input is not 2 bytes long.
+func mtEntry(b []byte) (uint16, bool, bool, error) {
+	if len(b) != 2 {
+		return 0, false, false, fmt.Errorf("MT entry must be 2 bytes, got: %d", len(b))
+	}
+	v := binary.BigEndian.Uint16(b)
+	return v & mtIDMask, v&mtOverloadBit != 0, v&mtAttachedBit != 0, nil
+}
+
+// mtOverloadAndAttached returns true if any entry of the Multi-Topology TLV
+// (229) value b has both the overload and attached flags set, which cannot be
+// represented by the single attribute stored for each topology in the
+// OpenConfig model.
+func mtOverloadAndAttached(b []byte) bool {
+	for x := 0; x+2 <= len(b); x += 2 {
+		v := binary.BigEndian.Uint16(b[x : x+2])
+		if v&mtOverloadBit != 0 && v&mtAttachedBit != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // ip4BytesToString takes a IPv4 address expressed as 4 bytes and returns it
 // as a string representing an IPv4 address. Returns an error in the case that
 // the address is the wrong length.
@@ -64,6 +114,28 @@ func ip4BytesToString(ip []byte) (string, error) {
 	return net.IP(ip).String(), nil
 }
 
+// ip4PrefixToString takes an IPv4 address expressed as 4 bytes and a prefix
+// length, and returns the prefix in CIDR format. It is equivalent to
+// formatting the result of ip4BytesToString along with the length, but
+// allocates only the returned string. Returns an error in the case that the
+// address is the wrong length.
+func ip4PrefixToString(ip []byte, length int) (string, error) {
+	if len(ip) != 4 {
+		return "", fmt.Errorf("ip4 addresses must be 32-bits")
+	}
+	var buf [len("255.255.255.255/32")]byte
+	b := buf[:0]
+	for x, o := range ip {
+		if x != 0 {
+			b = append(b, '.')
+		}
+		b = strconv.AppendUint(b, uint64(o), 10)
+	}
+	b = append(b, '/')
+	b = strconv.AppendInt(b, int64(length), 10)
+	return string(b), nil
+}
+
 // ip6BytesToString takes an IPv6 address expressed as 16 bytes and returns it
 // as a string representing an IPv6 address. Returns an error in the case that
 // the address is the wrong length.
@@ -74,6 +146,48 @@ func ip6BytesToString(ip []byte) (string, error) {
 	return net.IP(ip).String(), nil
 }
 
+// ip6BytesToString takes an IPv6 address expressed as 16 bytes and returns it
+// as a string, which is fully expanded if the receiver's options specify that
+// IPv6 addresses should be expanded. Returns an error in the case that the
+// address is the wrong length.
+func (i *isisLSP) ip6BytesToString(ip []byte) (string, error) {
+	if !i.opts.expandIPv6 {
+		return ip6BytesToString(ip)
+	}
+	if len(ip) != 16 {
+		return "", fmt.Errorf("ip6 addresses must be 128-bits")
+	}
+	return expandIPv6(net.IP(ip)), nil
+}
+
+// expandIPv6 returns the IPv6 address ip in its fully expanded form - i.e.,
+// with all 8 groups of 4 hexadecimal characters present.
+func expandIPv6(ip net.IP) string {
+	b := make([]byte, 0, 39)
+	for x := 0; x < 16; x++ {
+		if x != 0 && x%2 == 0 {
+			b = append(b, ':')
+		}
+		b = append(b, hexDigits[ip[x]>>4], hexDigits[ip[x]&0x0F])
+	}
+	return string(b)
+}
+
+// expandIPv6Address takes an input IPv6 address, or prefix, as a string and
+// returns it in fully expanded form. Returns an error if the input is not an
+// IPv6 address or prefix.
+func expandIPv6Address(s string) (string, error) {
+	addr, plen := s, ""
+	if x := strings.Index(s, "/"); x != -1 {
+		addr, plen = s[:x], s[x:]
+	}
+	ip := net.ParseIP(addr)
+	if ip == nil || ip.To4() != nil {
+		return "", fmt.Errorf("invalid IPv6 address: %s", s)
+	}
+	return expandIPv6(ip) + plen, nil
+}
+
 // getTLV retrieves a TLV from an isisLSP, creating it if it does not exist. Returns
 // the TLV, a boolean indicating whether the TLV was created, or an error if one is
 // experienced.
@@ -109,6 +223,61 @@ func (i *isisLSP) getTLVAndInit(t oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE, co
 	return tlv, nil
 }
 
+// addUndefinedTLV stores the contents of the TLV r as an undefined TLV within
+// the LSP. Along with addExtendedISReachUndefinedSubTLV and
+// addCapabilityUndefinedSubTLV, it is used both for TLVs of unknown type, and
+// for those that are understood but cannot be represented in the OpenConfig
+// model. The handlers for the latter validate the contents before storing
+// them, such that the accessors that decode them from the undefined TLVs, such
+// as PurgeOriginatorID, NodeMSDs and LabelBlocks, only see valid contents. The
+// OpenConfig model stores a single undefined TLV of each type, such that where
+// a TLV of the same type has already been stored, the first instance is
+// retained, r is not stored, and an error is returned. UndefinedTLVs can be
+// used to retrieve every instance.
+func (i *isisLSP) addUndefinedTLV(r *rawTLV) error {
+	if _, ok := i.LSP.UndefinedTlv[r.Type]; ok {
+		return fmt.Errorf("duplicate undefined TLV of type %d, retaining first instance", r.Type)
+	}
+	u, err := i.LSP.NewUndefinedTlv(r.Type)
+	if err != nil {
+		return err
+	}
+	u.Length = ygot.Uint8(r.Length)
+	u.Value = oc.Binary(r.Value)
+	return nil
+}
+
+// addExtendedISReachUndefinedSubTLV stores the contents of the sub-TLV r as an
+// undefined sub-TLV of the Extended IS Reachability neighbour instance n, as
+// per addUndefinedTLV.
+func addExtendedISReachUndefinedSubTLV(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, r *rawTLV) error {
+	if _, ok := n.UndefinedSubtlv[r.Type]; ok {
+		return fmt.Errorf("duplicate undefined sub-TLV of type %d, retaining first instance", r.Type)
+	}
+	u, err := n.NewUndefinedSubtlv(r.Type)
+	if err != nil {
+		return err
+	}
+	u.Length = ygot.Uint8(r.Length)
+	u.Value = oc.Binary(r.Value)
+	return nil
+}
+
+// addCapabilityUndefinedSubTLV stores the contents of the sub-TLV r as an
+// undefined sub-TLV of the Router Capability TLV c, as per addUndefinedTLV.
+func addCapabilityUndefinedSubTLV(c *oc.Lsp_Tlv_Capability, r *rawTLV) error {
+	if _, ok := c.UndefinedSubtlv[r.Type]; ok {
+		return fmt.Errorf("duplicate undefined sub-TLV of type %d, retaining first instance", r.Type)
+	}
+	u, err := c.NewUndefinedSubtlv(r.Type)
+	if err != nil {
+		return err
+	}
+	u.Length = ygot.Uint8(r.Length)
+	u.Value = oc.Binary(r.Value)
+	return nil
+}
+
 // getCapabilitySubTLV retrieves the specified sub-TLV from the
 // OpenConfig Router Capabilities TLV struct. If the sub-TLV does
 // not exist, it is created.
@@ -163,3 +332,107 @@ func canonicalHexString(in []byte) string {
 	}
 	return b.String()
 }
+
+// canonicalHexToBytes parses a string in the canonical format produced by
+// canonicalHexString (e.g., xxxx.yyyy.zzzz) and returns the bytes that it
+// represents. Returns an error if the string is not valid hexadecimal, or does
+// not represent n bytes.
+func canonicalHexToBytes(s string, n int) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Replace(s, ".", "", -1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid identifier %s: %v", s, err)
+	}
+	if len(b) != n || canonicalHexString(b) != strings.ToLower(s) {
+		return nil, fmt.Errorf("invalid identifier %s, must be %d bytes in canonical format", s, n)
+	}
+	return b, nil
+}
+
+// lspIDToBytes parses an LSP ID in the canonical format (xxxx.yyyy.zzzz.aa-bb)
+// used by ISISBytesToLSP and returns its 8-byte wire representation. Returns an
+// error if the LSP ID is not valid.
+func lspIDToBytes(s string) ([]byte, error) {
+	x := strings.LastIndex(s, "-")
+	if x == -1 {
+		return nil, fmt.Errorf("invalid LSP ID %s, no fragment number", s)
+	}
+	id, err := canonicalHexToBytes(s[:x], 7)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LSP ID %s: %v", s, err)
+	}
+	frag, err := canonicalHexToBytes(s[x+1:], 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LSP ID %s: %v", s, err)
+	}
+	return append(id, frag...), nil
+}
+
+// LSPID is an LSP ID, split into its components.
+type LSPID struct {
+	// SystemID is the system ID of the originating IS, in the format
+	// xxxx.yyyy.zzzz.
+	SystemID string
+	// PseudonodeID is the pseudonode ID, which is 0 for a non-pseudonode
+	// LSP.
+	PseudonodeID uint8
+	// Fragment is the LSP fragment number.
+	Fragment uint8
+}
+
+// ParseLSPID parses an LSP ID in the canonical format, xxxx.yyyy.zzzz.pp-ff,
+// as used by ISISBytesToLSP. Returns an error if the LSP ID is not in the
+// canonical format.
+func ParseLSPID(s string) (*LSPID, error) {
+	b, err := lspIDToBytes(s)
+	if err != nil {
+		return nil, err
+	}
+	return &LSPID{
+		SystemID:     canonicalHexString(b[:defaultIDLength]),
+		PseudonodeID: b[defaultIDLength],
+		Fragment:     b[defaultIDLength+1],
+	}, nil
+}
+
+// hexDigits is the set of characters used when encoding bytes to hexadecimal.
+const hexDigits = "0123456789abcdef"
+
+// maxStackHexLen is the length of the buffer that is allocated on the stack by
+// fastCanonicalHexString. It is sufficient for LSP IDs and system IDs.
+const maxStackHexLen = 32
+
+// fastCanonicalHexString returns the same output as canonicalHexString, but
+// writes directly into a fixed-size buffer, rather than allocating a buffer
+// and an intermediate hexadecimal string. Since the length of the output is
+// determined by the length of the input, the only allocation for system
+// and LSP IDs is the returned string. It should be used in hot paths, such as
+// the parsing of neighbor IDs.
+func fastCanonicalHexString(in []byte) string {
+	if len(in) == 0 {
+		return ""
+	}
+
+	// Each byte is encoded as two characters, with a "." separating each
+	// group of 4 characters.
+	l := len(in)*2 + (len(in)*2-1)/4
+
+	var stack [maxStackHexLen]byte
+	var out []byte
+	if l <= maxStackHexLen {
+		out = stack[:l]
+	} else {
+		out = make([]byte, l)
+	}
+
+	var j int
+	for i, b := range in {
+		if i != 0 && i%2 == 0 {
+			out[j] = '.'
+			j++
+		}
+		out[j] = hexDigits[b>>4]
+		out[j+1] = hexDigits[b&0x0F]
+		j += 2
+	}
+	return string(out)
+}
@@ -15,6 +15,7 @@
 package lsdbparse
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/kylelemons/godebug/pretty"
@@ -154,6 +155,50 @@ func TestIP4BytesToString(t *testing.T) {
 	}
 }
 
+func TestIP4PrefixToString(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       []byte
+		inLength int
+		want     string
+		wantErr  bool
+	}{{
+		name:     "host prefix",
+		in:       []byte{10, 192, 64, 32},
+		inLength: 32,
+		want:     "10.192.64.32/32",
+	}, {
+		name:     "default route",
+		in:       []byte{0, 0, 0, 0},
+		inLength: 0,
+		want:     "0.0.0.0/0",
+	}, {
+		name:     "maximum length",
+		in:       []byte{255, 255, 255, 255},
+		inLength: 32,
+		want:     "255.255.255.255/32",
+	}, {
+		name:     "ip4, too short",
+		in:       []byte{84},
+		inLength: 8,
+		wantErr:  true,
+	}}
+
+	for _, tt := range tests {
+		got, err := ip4PrefixToString(tt.in, tt.inLength)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: ip4PrefixToString(%v, %d): got unexpected error: %v", tt.name, tt.in, tt.inLength, err)
+			}
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: ip4PrefixToString(%v, %d): did not get expected prefix, got: %s, want: %s", tt.name, tt.in, tt.inLength, got, tt.want)
+		}
+	}
+}
+
 func TestIP6BytesToString(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -272,11 +317,235 @@ func TestCanonicalHexString(t *testing.T) {
 		name: "short",
 		in:   []byte{0x42},
 		want: "42",
+	}, {
+		name: "empty",
+		in:   []byte{},
+		want: "",
+	}, {
+		name: "odd number of bytes",
+		in:   []byte{0x01, 0x02, 0x03},
+		want: "0102.03",
+	}, {
+		name: "longer than stack buffer",
+		in:   bytes.Repeat([]byte{0xab}, 16),
+		want: "abab.abab.abab.abab.abab.abab.abab.abab",
 	}}
 
 	for _, tt := range tests {
 		if got := canonicalHexString(tt.in); got != tt.want {
 			t.Errorf("%s: canonicalHexString(%v): did not get expected formatted system ID, got: %v, want: %v", tt.name, tt.in, got, tt.want)
 		}
+
+		if got := fastCanonicalHexString(tt.in); got != tt.want {
+			t.Errorf("%s: fastCanonicalHexString(%v): did not get expected formatted system ID, got: %v, want: %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkCanonicalHexString(b *testing.B) {
+	in := []byte{10, 0, 0, 8, 0, 0, 42}
+	b.Run("canonicalHexString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			canonicalHexString(in)
+		}
+	})
+	b.Run("fastCanonicalHexString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			fastCanonicalHexString(in)
+		}
+	})
+}
+
+func TestMTID(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    uint16
+		wantErr bool
+	}{{
+		name: "standard topology",
+		in:   []byte{0x00, 0x00},
+		want: 0,
+	}, {
+		name: "IPv6 unicast topology",
+		in:   []byte{0x00, 0x02},
+		want: 2,
+	}, {
+		name: "reserved bits set are masked",
+		in:   []byte{0xC0, 0x02},
+		want: 2,
+	}, {
+		name: "maximum MT ID with all reserved bits set",
+		in:   []byte{0xFF, 0xFF},
+		want: 4095,
+	}, {
+		name:    "short input",
+		in:      []byte{0x00},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := mtID(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: mtID(%v): got unexpected error: %v", tt.name, tt.in, err)
+			}
+			continue
+		}
+
+		if tt.wantErr {
+			t.Errorf("%s: mtID(%v): did not get expected error", tt.name, tt.in)
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: mtID(%v): did not get expected value, got: %d, want: %d", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMTEntry(t *testing.T) {
+	tests := []struct {
+		name         string
+		in           []byte
+		wantID       uint16
+		wantOverload bool
+		wantAttached bool
+		wantErr      bool
+	}{{
+		name:   "standard topology, no flags",
+		in:     []byte{0x00, 0x00},
+		wantID: 0,
+	}, {
+		name:         "IPv6 unicast topology, overload",
+		in:           []byte{0x80, 0x02},
+		wantID:       2,
+		wantOverload: true,
+	}, {
+		name:         "IPv6 unicast topology, attached",
+		in:           []byte{0x40, 0x02},
+		wantID:       2,
+		wantAttached: true,
+	}, {
+		name:         "both flags, reserved bits ignored",
+		in:           []byte{0xF0, 0x03},
+		wantID:       3,
+		wantOverload: true,
+		wantAttached: true,
+	}, {
+		name:    "long input",
+		in:      []byte{0x00, 0x02, 0x00},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		gotID, gotOverload, gotAttached, err := mtEntry(tt.in)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: mtEntry(%v): got unexpected error: %v", tt.name, tt.in, err)
+			}
+			continue
+		}
+
+		if tt.wantErr {
+			t.Errorf("%s: mtEntry(%v): did not get expected error", tt.name, tt.in)
+		}
+
+		if gotID != tt.wantID || gotOverload != tt.wantOverload || gotAttached != tt.wantAttached {
+			t.Errorf("%s: mtEntry(%v): did not get expected value, got: (%d, %v, %v), want: (%d, %v, %v)", tt.name, tt.in, gotID, gotOverload, gotAttached, tt.wantID, tt.wantOverload, tt.wantAttached)
+		}
+	}
+}
+
+func TestExpandIPv6Address(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{{
+		name: "compressed address",
+		in:   "2001:db8::1",
+		want: "2001:0db8:0000:0000:0000:0000:0000:0001",
+	}, {
+		name: "prefix",
+		in:   "2001:db8::/32",
+		want: "2001:0db8:0000:0000:0000:0000:0000:0000/32",
+	}, {
+		name: "default route",
+		in:   "::/0",
+		want: "0000:0000:0000:0000:0000:0000:0000:0000/0",
+	}, {
+		name:    "IPv4 address",
+		in:      "192.0.2.1",
+		wantErr: true,
+	}, {
+		name:    "invalid address",
+		in:      "fish",
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := expandIPv6Address(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expandIPv6Address(%s): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%s: expandIPv6Address(%s): did not get expected value, got: %s, want: %s", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLSPIDToBytes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []byte
+		wantErr bool
+	}{
+		{in: "0000.4000.ce39.00-00", want: []byte{0x00, 0x00, 0x40, 0x00, 0xce, 0x39, 0x00, 0x00}},
+		{in: "1920.0000.2001.02-1a", want: []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x02, 0x1a}},
+		{in: "1920.0000.2001.02", wantErr: true},
+		{in: "1920.0000.2001-00", wantErr: true},
+		{in: "19200000200102-00", wantErr: true},
+		{in: "1920.0000.2001.02-001", wantErr: true},
+		{in: "zzzz.0000.2001.02-00", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := lspIDToBytes(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("lspIDToBytes(%s): got unexpected error status, got: %v, wantErr: %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("lspIDToBytes(%s): got: %v, want: %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseLSPID(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    *LSPID
+		wantErr bool
+	}{
+		{in: "0000.4000.ce39.00-00", want: &LSPID{SystemID: "0000.4000.ce39"}},
+		{in: "1920.0000.2001.02-1a", want: &LSPID{SystemID: "1920.0000.2001", PseudonodeID: 2, Fragment: 26}},
+		{in: "1920.0000.2001.02", wantErr: true},
+		{in: "1920.0000.2001.02-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLSPID(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLSPID(%s): got unexpected error status, got: %v, wantErr: %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("ParseLSPID(%s): did not get expected LSP ID, diff(-got,+want):\n%s", tt.in, diff)
+		}
 	}
 }
@@ -15,8 +15,12 @@
 package lsdbparse
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/openconfig/gnmi/errlist"
 	"github.com/openconfig/lsdbparse/pkg/oc"
@@ -34,19 +38,33 @@ const (
 	routerCapabilityContainer         string = "Capability"
 	ipv6ReachabilityContainer         string = "Ipv6Reachability"
 	ipv4TERouterIDContainer           string = "Ipv4TeRouterId"
+	ipv6TERouterIDContainer           string = "Ipv6TeRouterId"
 	ipv4InterfaceAddressesContainer   string = "Ipv4InterfaceAddresses"
 	ipv6InterfaceAddressesContainer   string = "Ipv6InterfaceAddresses"
 	extendedISReachabilityContainer   string = "ExtendedIsReachability"
 	extendedIPv4ReachabilityContainer string = "ExtendedIpv4Reachability"
+	authenticationContainer           string = "Authentication"
+	isReachabilityContainer           string = "IsReachability"
+	multiTopologyContainer            string = "MultiTopology"
+	mtIPv4ReachabilityContainer       string = "MtIpv4Reachability"
 	// Names of the containers that are used within the Extended IS
 	// Reachability SubTLV structure.
 	extISReachAdminGroupContainer  string = "AdminGroup"
 	extISReachAvailableBandwidth   string = "AvailableBandwidth"
+	extISReachExtendedAdminGroup   string = "ExtendedAdminGroup"
 	extISReachIPv4InterfaceAddress string = "Ipv4InterfaceAddress"
 	extISReachIPv4NeighborAddress  string = "Ipv4NeighborAddress"
+	extISReachIPv6InterfaceAddress string = "Ipv6InterfaceAddress"
+	extISReachIPv6NeighborAddress  string = "Ipv6NeighborAddress"
+	extISReachLinkDelay            string = "LinkDelay"
+	extISReachLinkDelayVariation   string = "LinkDelayVariation"
+	extISReachLinkLoss             string = "LinkLoss"
+	extISReachLinkProtectionType   string = "LinkProtectionType"
+	extISReachMinMaxLinkDelay      string = "MinMaxLinkDelay"
 	extISReachMaxLinkBW            string = "MaxLinkBandwidth"
 	extISReachMaxReservableBW      string = "MaxReservableLinkBandwidth"
 	extISReachResidualBW           string = "ResidualBandwidth"
+	extISReachTEDefaultMetric      string = "TeDefaultMetric"
 )
 
 const (
@@ -63,68 +81,294 @@ const (
 )
 
 // TLVBytesToTLVs takes an input byte slice that contains the TLVs section
-// of the LSP, and extracts the TLVs as a slice of structs. Returns an error if
-// unable to extract the TLVs.
+// of the LSP, and extracts the TLVs as a slice of structs. The value of each
+// TLV is a sub-slice of tlvBytes, such that tlvBytes must not be modified
+// whilst the TLVs are in use. Returns an error if unable to extract the TLVs.
 func TLVBytesToTLVs(tlvBytes []byte) ([]*rawTLV, error) {
+	var s *tlvScratch
+	return s.tlvs(tlvBytes)
+}
+
+// tlvScratch is storage for the TLVs extracted from an LSP, which can be
+// reused when extracting the TLVs of subsequent LSPs.
+type tlvScratch struct {
+	// raw stores the TLVs.
+	raw []rawTLV
+	// ptrs stores pointers to each element of raw.
+	ptrs []*rawTLV
+}
+
+// tlvs extracts the TLVs from tlvBytes, as described by TLVBytesToTLVs. If s
+// is non-nil, the TLVs are stored within it, overwriting the TLVs from any
+// previous call, and otherwise new storage is allocated for them.
+func (s *tlvScratch) tlvs(tlvBytes []byte) ([]*rawTLV, error) {
+	// Count the TLVs first, such that their storage can be allocated
+	// once, rather than once per TLV.
+	var n int
+	if err := walkTLVs(tlvBytes, func(uint8, []byte) error {
+		n++
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	var raw []rawTLV
 	var tlvs []*rawTLV
+	if s != nil {
+		raw, tlvs = s.raw[:0], s.ptrs[:0]
+	}
+	// The capacity of raw must not be exceeded, since the pointers to its
+	// elements would no longer refer to the TLVs that are returned.
+	if cap(raw) < n {
+		raw = make([]rawTLV, 0, n)
+	}
+	if cap(tlvs) < n {
+		tlvs = make([]*rawTLV, 0, n)
+	}
+
+	// The TLVs have been validated by the first walk, and hence the second
+	// cannot return an error.
+	walkTLVs(tlvBytes, func(tlvType uint8, value []byte) error {
+		raw = append(raw, rawTLV{
+			Type:   tlvType,
+			Length: uint8(len(value)),
+			Value:  value,
+		})
+		tlvs = append(tlvs, &raw[len(raw)-1])
+		return nil
+	})
+
+	if s != nil {
+		s.raw, s.ptrs = raw, tlvs
+	}
+	return tlvs, nil
+}
+
+// TLVsToTLVBytes takes an input slice of TLVs and serialises them, in order,
+// to the format of the TLVs section of the LSP, such that it is the inverse of
+// TLVBytesToTLVs. The length of each TLV is computed from its value, and hence
+// the Length field is ignored. Returns an error if the value of a TLV exceeds
+// the 255 bytes that can be described by its length.
+func TLVsToTLVBytes(tlvs []*rawTLV) ([]byte, error) {
+	var l int
+	for x, r := range tlvs {
+		if r == nil {
+			return nil, fmt.Errorf("nil TLV at index %d", x)
+		}
+		if len(r.Value) > maxTLVValueLen {
+			return nil, fmt.Errorf("TLV %d at index %d: value length %d exceeds %d bytes", r.Type, x, len(r.Value), maxTLVValueLen)
+		}
+		l += tlvHeaderLen + len(r.Value)
+	}
+
+	b := make([]byte, 0, l)
+	for _, r := range tlvs {
+		b = append(b, r.Type, uint8(len(r.Value)))
+		b = append(b, r.Value...)
+	}
+	return b, nil
+}
+
+// walkTLVs takes an input byte slice that contains a sequence of TLVs, and
+// calls fn with the type and value of each TLV in turn. The value supplied to
+// fn is a sub-slice of tlvBytes, and hence must be copied if it is retained
+// beyond the lifetime of tlvBytes. Its capacity is limited to its length, such
+// that appending to it cannot overwrite the TLV that follows.
+// Returns an error if the TLVs cannot be extracted, or the first error that is
+// returned by fn.
+func walkTLVs(tlvBytes []byte, fn func(tlvType uint8, value []byte) error) error {
 	var tlvLen int
 	// Update the position within the tlvBytes slice, 2 bytes of type and length,
 	// and then the specified number of bytes for the length.
 	for pos := 0; pos < len(tlvBytes); pos += 2 + tlvLen {
 		if pos == len(tlvBytes)-1 {
-			return nil, fmt.Errorf("invalid length of TLVs, got a TLV with type and no length: %d", pos)
+			return fmt.Errorf("invalid length of TLVs, got a TLV with type and no length: %d", pos)
 		}
 
 		tlvLen = int(tlvBytes[pos+1])
 		if pos+2+tlvLen > len(tlvBytes) {
-			return nil, fmt.Errorf("invalid length of TLVs, overflowed buffer, at: %d, length: %d", pos+2, tlvLen)
-		}
-
-		var tlvContents []byte
-		for i := pos + 2; i < pos+2+tlvLen; i++ {
-			tlvContents = append(tlvContents, tlvBytes[i])
+			return fmt.Errorf("invalid length of TLVs, overflowed buffer, at: %d, length: %d", pos+2, tlvLen)
 		}
 
-		t := &rawTLV{
-			Type:   uint8(tlvBytes[pos]),
-			Length: uint8(tlvLen),
-			Value:  tlvContents,
+		end := pos + 2 + tlvLen
+		if err := fn(tlvBytes[pos], tlvBytes[pos+2:end:end]); err != nil {
+			return err
 		}
-		tlvs = append(tlvs, t)
 	}
 
-	return tlvs, nil
+	return nil
 }
 
 // processTLVMap maps the IS-IS TLV type to the function that parses the TLV.
 var processTLVMap = map[uint8]func(*isisLSP, *rawTLV) error{
 	1:   (*isisLSP).processAreaAddressTLV,
+	2:   (*isisLSP).processISReachabilityTLV,
+	10:  (*isisLSP).processAuthenticationTLV,
 	22:  (*isisLSP).processExtendedISReachabilityTLV,
 	129: (*isisLSP).processNLPIDTLV,
 	132: (*isisLSP).processIPInterfaceAddressTLV,
 	134: (*isisLSP).processTERouterIDTLV,
 	135: (*isisLSP).processExtendedIPReachTLV,
 	137: (*isisLSP).processDynamicNameTLV,
+	140: (*isisLSP).processIPv6TERouterIDTLV,
+	229: (*isisLSP).processMTTLV,
 	232: (*isisLSP).processIPv6InterfaceAddressTLV,
+	235: (*isisLSP).processMTIPv4ReachabilityTLV,
 	236: (*isisLSP).processIPv6ReachabilityTLV,
 	242: (*isisLSP).processCapabilityTLV,
 }
 
+// fatalTLVError is returned by processTLVs when an error is encountered in a
+// TLV whose type has been specified to be fatal using WithFatalTLVTypes.
+type fatalTLVError struct {
+	// tlvType is the type of the TLV in which the error was encountered.
+	tlvType uint8
+	// err is the error that was encountered.
+	err error
+}
+
+// Error implements the error interface.
+func (e *fatalTLVError) Error() string {
+	return fmt.Sprintf("fatal error in TLV %d: %v", e.tlvType, e.err)
+}
+
 // processTLVs processes the set of TLVs that are stored in the rawTLVs slice of the
 // receiver isisLSP, and populates the LSP field with the OpenConfig data model that
 // corresponds to the TLVs contained in the message. Returns an error when parsing
-// is not successful.
+// is not successful. Where an error is encountered in a TLV whose type is fatal,
+// processing stops and a fatalTLVError is returned.
 func (i *isisLSP) processTLVs() error {
 	var pErr errlist.List
 
 	for _, r := range i.rawTLVs {
-		if f, ok := processTLVMap[r.Type]; ok {
-			pErr.Add(f(i, r))
-			//lint:ignore SA9003 empty branch
+		var err error
+		if i.opts.tlvTimings != nil {
+			start := time.Now()
+			err = i.processTLV(r)
+			i.opts.tlvTimings[r.Type] += time.Since(start)
 		} else {
-			// TODO(robjs): Append this TLV to the undefined TLVs in the
-			// OpenConfig data model.
+			err = i.processTLV(r)
 		}
+
+		if err != nil && i.opts.fatalTLVTypes[r.Type] {
+			return &fatalTLVError{tlvType: r.Type, err: err}
+		}
+		pErr.Add(err)
+	}
+
+	if i.opts.checkRouterIDs {
+		pErr.Add(i.checkRouterIDConsistency())
+	}
+	if i.opts.checkUnusableMetric {
+		pErr.Add(i.checkUnusableMetrics())
+	}
+	return pErr.Err()
+}
+
+// processTLV decodes the TLV r into the LSP, using the handler for its type
+// from processTLVMap. TLVs without a handler, or within the raw TLV range, are
+// stored as undefined TLVs, unless TLVs without a handler are to be discarded.
+func (i *isisLSP) processTLV(r *rawTLV) error {
+	f, ok := processTLVMap[r.Type]
+	raw := i.opts.isRawTLV(r.Type)
+	if !ok && !raw && i.opts.discardUnknownTLVs {
+		return nil
+	}
+	if !ok || raw {
+		err := i.addUndefinedTLV(r)
+		if err != nil && (raw || r.Type == aslaSRLGTLVType) {
+			// TLVs within the raw range, and application-specific SRLG
+			// TLVs, which RFC8919 allows to be repeated, are expected to
+			// be repeated, such that the instances that are not stored are
+			// only reported when parsing strictly.
+			i.strictErrs = append(i.strictErrs, err)
+			return nil
+		}
+		return err
+	}
+	i.tlvType = r.Type
+	return f(i, r)
+}
+
+// addUnknownSubTLVs records n sub-TLVs of unknown type within the TLV that is
+// currently being processed, if parse statistics were requested, or as an
+// error if parsing strictly.
+func (i *isisLSP) addUnknownSubTLVs(n int) {
+	if n == 0 {
+		return
+	}
+	if i.opts.strict {
+		i.strictErrs = append(i.strictErrs, fmt.Errorf("%d unknown sub-TLVs in TLV %d", n, i.tlvType))
+	}
+	if i.opts.stats == nil {
+		return
+	}
+	if i.opts.stats.UnknownSubTLVs == nil {
+		i.opts.stats.UnknownSubTLVs = map[uint8]int{}
+	}
+	i.opts.stats.UnknownSubTLVs[i.tlvType] += n
+}
+
+// checkRouterIDConsistency checks that the router IDs advertised in the Router
+// Capability TLV (242) are consistent with those advertised in the IPv4 TE
+// Router ID TLV (134). Router Capability TLVs with a router ID of 0.0.0.0 are
+// ignored, since RFC7981 allows this value to be used when no IPv4 router ID
+// is available. Returns an error if both TLVs are present, and a router ID in
+// the Router Capability TLV is not advertised as a TE router ID.
+func (i *isisLSP) checkRouterIDConsistency() error {
+	te := i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID).GetIpv4TeRouterId()
+	capTLV := i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY)
+	if te == nil || len(te.RouterId) == 0 || capTLV == nil {
+		return nil
+	}
+
+	teIDs := map[string]bool{}
+	for _, id := range te.RouterId {
+		teIDs[id] = true
+	}
+
+	// Sort the instances such that errors are returned deterministically.
+	var insts []uint32
+	for n := range capTLV.Capability {
+		insts = append(insts, n)
+	}
+	sort.Slice(insts, func(x, y int) bool { return insts[x] < insts[y] })
+
+	var pErr errlist.List
+	for _, n := range insts {
+		id := capTLV.Capability[n].RouterId
+		if id == nil || *id == "0.0.0.0" || teIDs[*id] {
+			continue
+		}
+		pErr.Add(fmt.Errorf("router ID %s in Router Capability TLV does not match IPv4 TE router IDs %v", *id, te.RouterId))
+	}
+	return pErr.Err()
+}
+
+// checkUnusableMetrics returns an error for each prefix in the Extended IPv4
+// Reachability TLV (135) that is advertised with the unusable metric, such
+// that it is not used for SPF.
+func (i *isisLSP) checkUnusableMetrics() error {
+	r := i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability()
+	if r == nil {
+		return nil
+	}
+
+	// Sort the prefixes such that errors are returned deterministically.
+	var pfxs []string
+	for pfx, p := range r.Prefix {
+		if IsUnusableIPv4Prefix(p) {
+			pfxs = append(pfxs, pfx)
+		}
+	}
+	sort.Strings(pfxs)
+
+	var pErr errlist.List
+	for _, pfx := range pfxs {
+		pErr.Add(fmt.Errorf("prefix %s advertised with unusable metric %#x, greater than MAX_PATH_METRIC %#x, must not be used for SPF", pfx, uint32Value(r.Prefix[pfx].Metric), maxPathMetric))
 	}
 	return pErr.Err()
 }
@@ -136,7 +380,62 @@ func (i *isisLSP) processDynamicNameTLV(r *rawTLV) error {
 		return err
 	}
 
-	tlv.Hostname.Hostname = append(tlv.Hostname.Hostname, string(r.Value))
+	name, _ := i.intern(internHostname, r.Value, 0, func() (string, error) {
+		return string(r.Value), nil
+	})
+
+	var nameErr error
+	for _, h := range tlv.Hostname.Hostname {
+		if h != name {
+			nameErr = fmt.Errorf("multiple distinct hostnames in Dynamic Hostname TLVs, %q and %q", h, name)
+			break
+		}
+	}
+
+	switch {
+	case i.opts.hostnameMode == HostnameLast:
+		tlv.Hostname.Hostname = []string{name}
+	case i.opts.hostnameMode == HostnameFirst && len(tlv.Hostname.Hostname) != 0:
+		// Retain the hostname from the first TLV.
+	default:
+		tlv.Hostname.Hostname = append(tlv.Hostname.Hostname, name)
+	}
+	return nameErr
+}
+
+const (
+	// Values of the authentication type field of the authentication TLV.
+	authTypeCleartext     uint8 = 1  // Defined in ISO10589.
+	authTypeGenericCrypto uint8 = 3  // Defined in RFC5310.
+	authTypeHMACMD5       uint8 = 54 // Defined in RFC5304.
+)
+
+// processAuthenticationTLV parses the authentication TLV (type = 10). The
+// first byte of the TLV indicates the authentication type, with the remaining
+// bytes being the authentication value. The type is stored in the model for
+// cleartext passwords and HMAC-MD5 (RFC5304). The authentication value is not
+// stored, such that a cleartext password is not rendered with the LSP, and
+// can instead be retrieved from the LSP bytes using LSPAuthentication.
+func (i *isisLSP) processAuthenticationTLV(r *rawTLV) error {
+	if len(r.Value) < 1 {
+		return fmt.Errorf("invalid length authentication TLV: %d", len(r.Value))
+	}
+
+	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION, authenticationContainer)
+	if err != nil {
+		return err
+	}
+
+	switch r.Value[0] {
+	case authTypeCleartext:
+		tlv.Authentication.CryptoType = oc.OpenconfigIsis_Authentication_CryptoType_CLEARTEXT
+	case authTypeHMACMD5:
+		tlv.Authentication.CryptoType = oc.OpenconfigIsis_Authentication_CryptoType_HMAC_MD5
+	case authTypeGenericCrypto:
+		if _, err := parseGenericCryptoAuth(r.Value[1:]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -157,36 +456,38 @@ func (i *isisLSP) processAreaAddressTLV(r *rawTLV) error {
 		if endPos > len(r.Value) {
 			return fmt.Errorf("invalid length of address, %d, overflows TLV length %d at position %d, TLV contents: %v, currently parsed: %v", addrLen, len(r.Value), x, r.Value, tlv.AreaAddress.Address)
 		}
-		a := fmt.Sprintf("%s.%s", canonicalHexString([]byte{r.Value[x+1]}), canonicalHexString(r.Value[x+2:endPos]))
+		a, _ := i.intern(internAreaAddress, r.Value[x+1:endPos], 0, func() (string, error) {
+			return fmt.Sprintf("%s.%s", canonicalHexString([]byte{r.Value[x+1]}), canonicalHexString(r.Value[x+2:endPos])), nil
+		})
 		tlv.AreaAddress.Address = append(tlv.AreaAddress.Address, a)
 	}
 	return nil
 }
 
 // processNLPIDTLV parses TLV 129 the NLPID (network layer protocol identifiers)
-// that are supported by the intermediate system. Defined in RFC 1195.
+// that are supported by the intermediate system. Defined in RFC 1195. The
+// OpenConfig model can only represent the IPv4 and IPv6 NLPIDs, such that other
+// NLPIDs, such as CLNP (0x81), are not stored, and can instead be retrieved
+// from the LSP bytes using UnknownNLPIDs.
 func (i *isisLSP) processNLPIDTLV(r *rawTLV) error {
 	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID, nlpidContainer)
 	if err != nil {
 		return err
 	}
 
-	var pErr errlist.List
 	for _, b := range r.Value {
-		var v oc.E_OpenconfigIsis_Nlpid_Nlpid
-		switch b {
-		case 0xCC:
-			v = oc.OpenconfigIsis_Nlpid_Nlpid_IPV4
-		case 0x8E:
-			v = oc.OpenconfigIsis_Nlpid_Nlpid_IPV6
-		default:
-			pErr.Add(fmt.Errorf("unknown NLPID specified: %v", b))
-			continue
+		if v, ok := nlpidValues[b]; ok {
+			tlv.Nlpid.Nlpid = append(tlv.Nlpid.Nlpid, v)
 		}
-		tlv.Nlpid.Nlpid = append(tlv.Nlpid.Nlpid, v)
 	}
+	return nil
+}
 
-	return pErr.Err()
+// nlpidValues maps the NLPIDs that can be represented in the OpenConfig model
+// to their enumerated value.
+var nlpidValues = map[uint8]oc.E_OpenconfigIsis_Nlpid_Nlpid{
+	0xCC: oc.OpenconfigIsis_Nlpid_Nlpid_IPV4,
+	0x8E: oc.OpenconfigIsis_Nlpid_Nlpid_IPV6,
 }
 
 // processIPInterfaceAddressTLV processes the IP interface address TLV (type = 132)
@@ -214,6 +515,53 @@ func (i *isisLSP) processIPInterfaceAddressTLV(r *rawTLV) error {
 	return pErr.Err()
 }
 
+// processMTTLV processes the Multi-Topology TLV (type = 229) defined in RFC5120.
+// The TLV consists of repeated 2-byte entries, each of which carries the
+// overload (O) and attached (A) flags in its upper bits, and a 12-bit MT ID.
+// The OpenConfig model stores a single attribute for each topology, such that
+// where both the O and A flags are set, OVERLOAD is stored and the TLV is
+// additionally preserved as an undefined TLV.
+func (i *isisLSP) processMTTLV(r *rawTLV) error {
+	if len(r.Value)%2 != 0 {
+		return fmt.Errorf("invalid Multi-Topology TLV, length was not a multiple of 2: %d", len(r.Value))
+	}
+
+	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY, multiTopologyContainer)
+	if err != nil {
+		return err
+	}
+
+	var pErr errlist.List
+	for x := 0; x < len(r.Value); x += 2 {
+		id, overload, attached, err := mtEntry(r.Value[x : x+2])
+		if err != nil {
+			pErr.Add(err)
+			continue
+		}
+
+		t, err := tlv.MultiTopology.NewTopology(id)
+		if err != nil {
+			pErr.Add(fmt.Errorf("cannot add MT ID %d to Multi-Topology TLV: %v", id, err))
+			continue
+		}
+
+		switch {
+		case overload:
+			t.Attributes = oc.OpenconfigIsis_Topology_Attributes_OVERLOAD
+		case attached:
+			t.Attributes = oc.OpenconfigIsis_Topology_Attributes_ATTACHED
+		}
+	}
+
+	if mtOverloadAndAttached(r.Value) {
+		if err := i.addUndefinedTLV(r); err != nil {
+			pErr.Add(fmt.Errorf("cannot preserve Multi-Topology TLV with overload and attached flags: %v", err))
+		}
+	}
+
+	return pErr.Err()
+}
+
 // processIPv6InterfaceAddressTLV processes the IPv6 interface address TLV (type = 232)
 // of an IS-IS LSP. Defined in RFC5308.
 func (i *isisLSP) processIPv6InterfaceAddressTLV(r *rawTLV) error {
@@ -227,7 +575,7 @@ func (i *isisLSP) processIPv6InterfaceAddressTLV(r *rawTLV) error {
 	}
 
 	for x := 0; x < len(r.Value); x += 16 {
-		ip6, err := ip6BytesToString(r.Value[x : x+16])
+		ip6, err := i.ip6BytesToString(r.Value[x : x+16])
 		if err != nil {
 			return err
 		}
@@ -286,9 +634,38 @@ func (i *isisLSP) processCapabilityTLV(r *rawTLV) error {
 			pErr.Add(processSRCapabilitySubTLV(rcap, s))
 		case 19:
 			pErr.Add(processSRAlgorithmCapabilitySubTLV(rcap, s))
+		case nodeMSDSubTLVType:
+			// Node MSD, RFC8491.
+			if _, err := parseMSDSubTLV(s); err != nil {
+				pErr.Add(err)
+				continue
+			}
+			if err := addCapabilityUndefinedSubTLV(rcap, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store node MSD sub-TLV: %v", err))
+			}
+		case srlbSubTLVType:
+			// SR Local Block, RFC8667.
+			if _, err := parseSRLBSubTLV(s); err != nil {
+				pErr.Add(err)
+				continue
+			}
+			if err := addCapabilityUndefinedSubTLV(rcap, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store SRLB sub-TLV: %v", err))
+			}
+		case srmsPreferenceSubTLVType:
+			// SRMS preference, RFC8667.
+			if _, err := parseSRMSPreferenceSubTLV(s); err != nil {
+				pErr.Add(err)
+				continue
+			}
+			if err := addCapabilityUndefinedSubTLV(rcap, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store SRMS preference sub-TLV: %v", err))
+			}
 		default:
-			// TODO(robjs): Add this subTLV to the unknown subTLV list.
-			pErr.Add(fmt.Errorf("unimplemented router capability sub-TLV, type: %d", s.Type))
+			i.addUnknownSubTLVs(1)
+			if err := addCapabilityUndefinedSubTLV(rcap, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store undefined router capability sub-TLV of type %d: %v", s.Type, err))
+			}
 		}
 	}
 
@@ -417,6 +794,10 @@ func (i *isisLSP) processIPv6ReachabilityTLV(r *rawTLV) error {
 		return err
 	}
 
+	if tlv.Ipv6Reachability.Prefix == nil {
+		tlv.Ipv6Reachability.Prefix = make(map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix, i.prefixCountHint(r.Type, ipv6PrefixEntryHint))
+	}
+
 	// Encoding of this TLV is:
 	// 4-bytes of metric
 	// 1-byte of control:
@@ -473,19 +854,24 @@ func (i *isisLSP) processIPv6ReachabilityTLV(r *rawTLV) error {
 			ipBytes[j] = r.Value[x+6+j]
 		}
 
-		addr, err := ip6BytesToString(ipBytes)
+		kind := internIPv6Prefix
+		if i.opts.expandIPv6 {
+			kind = internExpandedIPv6Prefix
+		}
+		pfx, err := i.intern(kind, ipBytes, uint8(pfxlen), func() (string, error) {
+			addr, err := i.ip6BytesToString(ipBytes)
+			if err != nil {
+				return "", err
+			}
+			return addr + "/" + strconv.Itoa(pfxlen), nil
+		})
 		if err != nil {
 			return err
 		}
-		pfx := fmt.Sprintf("%s/%d", addr, pfxlen)
 
 		// Track the current size of this TLV
 		s = x + 6 + ipL
 
-		if _, ok := tlv.Ipv6Reachability.Prefix[pfx]; ok {
-			return err
-		}
-
 		pfxTLV := &oc.Lsp_Tlv_Ipv6Reachability_Prefix{
 			Prefix: ygot.String(pfx),
 			UpDown: ygot.Bool(upDown),
@@ -514,23 +900,70 @@ func (i *isisLSP) processIPv6ReachabilityTLV(r *rawTLV) error {
 
 			for _, st := range subTLVs {
 				switch st.Type {
+				case 1:
+					tags, err := parseAdminTagSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						break
+					}
+					tag := pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetOrCreateTag()
+					tag.Tag32 = append(tag.Tag32, tags...)
+				case 2:
+					tags, err := parseAdminTag64SubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						break
+					}
+					tag := pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64).GetOrCreateTag64()
+					tag.Tag64 = append(tag.Tag64, tags...)
 				case 3:
 					pfxseg, err := parsePrefixSIDSubTLV(st)
 					if err != nil {
 						pErr.Add(err)
 						break
 					}
+					pErr.Add(validatePrefixSIDFlags(st))
 					if err := addIPv6ReachabilityPrefixSID(pfxTLV, pfxseg); err != nil {
 						pErr.Add(err)
 					}
+				case 4:
+					flags, err := parsePrefixAttributeFlagsSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						break
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS).GetOrCreateFlags().Flags = flags
+				case 11:
+					rid, err := parseIPv4SourceRouterIDSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						break
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID).GetOrCreateIpv4SourceRouterId().RouterId = ygot.String(rid)
+				case 12:
+					rid, err := i.parseIPv6SourceRouterIDSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						break
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID).GetOrCreateIpv6SourceRouterId().RouterId = ygot.String(rid)
 				default:
-					// TODO(robjs): Add this subTLV to the unknown subTLV list.
+					// TODO(robjs): Preserve this sub-TLV as an undefined
+					// sub-TLV. Only the count of unknown sub-TLVs is kept, and
+					// the sub-TLV itself is discarded.
+					i.addUnknownSubTLVs(1)
 					pErr.Add(fmt.Errorf("unimplemented sub-TLV parsing for type %d in IPv6 Reachability TLV", st.Type))
 				}
 			}
 			s += 1 + subTLVLen
 		}
 
+		// As per the Extended IP Reachability TLV, the first instance of a
+		// duplicate prefix is retained, and subsequent prefixes are parsed.
+		if _, ok := tlv.Ipv6Reachability.Prefix[pfx]; ok {
+			pErr.Add(fmt.Errorf("duplicate prefix %s in IPv6 Reachability TLV, retaining first instance", pfx))
+			continue
+		}
 		if err := tlv.Ipv6Reachability.AppendPrefix(pfxTLV); err != nil {
 			return fmt.Errorf("cannot append IPv6 Reachability TLV, %v", err)
 		}
@@ -543,6 +976,86 @@ func (i *isisLSP) processIPv6ReachabilityTLV(r *rawTLV) error {
 	return pErr.Err()
 }
 
+// parseAdminTagSubTLV parses sub-TLV 1 of the IP reachability TLVs, which
+// carries one or more 32-bit administrative tags, as defined in RFC5130.
+// Returns the tags, or an error if the length is not a multiple of 4.
+func parseAdminTagSubTLV(r *rawTLV) ([]uint32, error) {
+	if len(r.Value) == 0 || len(r.Value)%4 != 0 {
+		return nil, fmt.Errorf("invalid length for administrative tag sub-TLV, %d is not a non-zero multiple of 4", len(r.Value))
+	}
+
+	tags := make([]uint32, 0, len(r.Value)/4)
+	for x := 0; x < len(r.Value); x += 4 {
+		tags = append(tags, binary.BigEndian.Uint32(r.Value[x:x+4]))
+	}
+	return tags, nil
+}
+
+// parseAdminTag64SubTLV parses sub-TLV 2 of the IP reachability TLVs, which
+// carries one or more 64-bit administrative tags, as defined in RFC5130.
+// Returns the tags, or an error if the length is not a multiple of 8.
+func parseAdminTag64SubTLV(r *rawTLV) ([]uint64, error) {
+	if len(r.Value) == 0 || len(r.Value)%8 != 0 {
+		return nil, fmt.Errorf("invalid length for 64-bit administrative tag sub-TLV, %d is not a non-zero multiple of 8", len(r.Value))
+	}
+
+	tags := make([]uint64, 0, len(r.Value)/8)
+	for x := 0; x < len(r.Value); x += 8 {
+		tags = append(tags, binary.BigEndian.Uint64(r.Value[x:x+8]))
+	}
+	return tags, nil
+}
+
+// prefixAttributeFlagBits maps the bits of the prefix attribute flags field
+// to the OpenConfig enumerated type for prefix flags, ordered from the most
+// significant bit. The bit assignments are defined in RFC7794.
+var prefixAttributeFlagBits = []struct {
+	bit  uint8
+	flag oc.E_OpenconfigIsis_Flags_Flags
+}{
+	{bit0, oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG},
+	{bit1, oc.OpenconfigIsis_Flags_Flags_READVERTISEMENT_FLAG},
+	{bit2, oc.OpenconfigIsis_Flags_Flags_NODE_FLAG},
+}
+
+// parsePrefixAttributeFlagsSubTLV parses sub-TLV 4 of the IP reachability
+// TLVs, the prefix attribute flags defined in RFC7794. Only the first octet
+// of the sub-TLV is defined, any further octets are ignored. Returns the
+// flags that are set, or an error if the sub-TLV is empty.
+func parsePrefixAttributeFlagsSubTLV(r *rawTLV) ([]oc.E_OpenconfigIsis_Flags_Flags, error) {
+	if len(r.Value) < 1 {
+		return nil, fmt.Errorf("invalid length for prefix attribute flags sub-TLV: %d", len(r.Value))
+	}
+
+	var flags []oc.E_OpenconfigIsis_Flags_Flags
+	for _, f := range prefixAttributeFlagBits {
+		if r.Value[0]&f.bit != 0 {
+			flags = append(flags, f.flag)
+		}
+	}
+	return flags, nil
+}
+
+// parseIPv4SourceRouterIDSubTLV parses sub-TLV 11 of the IP reachability
+// TLVs, the IPv4 source router ID defined in RFC7794. Returns the router ID,
+// or an error if the sub-TLV is not 4 bytes long.
+func parseIPv4SourceRouterIDSubTLV(r *rawTLV) (string, error) {
+	if len(r.Value) != 4 {
+		return "", fmt.Errorf("IPv4 source router ID sub-TLV had incorrect length: %d != 4", len(r.Value))
+	}
+	return ip4BytesToString(r.Value)
+}
+
+// parseIPv6SourceRouterIDSubTLV parses sub-TLV 12 of the IP reachability
+// TLVs, the IPv6 source router ID defined in RFC7794. Returns the router ID,
+// or an error if the sub-TLV is not 16 bytes long.
+func (i *isisLSP) parseIPv6SourceRouterIDSubTLV(r *rawTLV) (string, error) {
+	if len(r.Value) != 16 {
+		return "", fmt.Errorf("IPv6 source router ID sub-TLV had incorrect length: %d != 16", len(r.Value))
+	}
+	return i.ip6BytesToString(r.Value)
+}
+
 // prefixSIDSubTLV describes sub-TLV3 of the IP reachability TLV types
 // (i.e., 135, 235, 236, 237). It is used to store an arbitrary representation
 // of the PrefixSID subTLV in a manner that does not require knowledge of where
@@ -560,12 +1073,23 @@ type prefixSIDSubTLV struct {
 func parsePrefixSIDSubTLV(r *rawTLV) (*prefixSIDSubTLV, error) {
 	p := &prefixSIDSubTLV{}
 
-	// Perform a primary length check to ensure that we do
-	// not panic.
-	if len(r.Value) < 4 {
+	if len(r.Value) < 1 {
 		return nil, fmt.Errorf("invalid Prefix-SID subTLV, invalid length: %d", len(r.Value))
 	}
 
+	// A label is encoded in 3 bytes, and an index in 4 bytes, following the
+	// 1-byte flags and 1-byte algorithm. The length is checked against the
+	// VALUE flag before the SID is read, such that a misaligned sub-TLV is
+	// rejected rather than decoded.
+	isLabel := r.Value[0]&bit4 != 0
+	wantLen := 6
+	if isLabel {
+		wantLen = 5
+	}
+	if len(r.Value) != wantLen {
+		return nil, fmt.Errorf("invalid Prefix-SID length for VALUE flag %v, got: %d, want: %d", isLabel, len(r.Value), wantLen)
+	}
+
 	if b := r.Value[0] & bit0; b != 0 {
 		p.Flags = append(p.Flags, oc.OpenconfigIsis_PrefixSid_Flags_READVERTISEMENT)
 	}
@@ -582,15 +1106,8 @@ func parsePrefixSIDSubTLV(r *rawTLV) (*prefixSIDSubTLV, error) {
 		p.Flags = append(p.Flags, oc.OpenconfigIsis_PrefixSid_Flags_EXPLICIT_NULL)
 	}
 
-	var isLabel bool
-	if b := r.Value[0] & bit4; b != 0 {
-		isLabel = true
+	if isLabel {
 		p.Flags = append(p.Flags, oc.OpenconfigIsis_PrefixSid_Flags_VALUE)
-		// This also means that the value should be 4-bytes long rather than 3,
-		// so we check that the length will not cause a panic later.
-		if len(r.Value) < 5 {
-			return nil, fmt.Errorf("invalid Prefix-SID subTLV, invalid length for index: %d", len(r.Value))
-		}
 	}
 
 	if b := r.Value[0] & bit5; b != 0 {
@@ -618,6 +1135,27 @@ func parsePrefixSIDSubTLV(r *rawTLV) (*prefixSIDSubTLV, error) {
 	return p, nil
 }
 
+// validatePrefixSIDFlags checks the flags of a Prefix-SID sub-TLV, which has
+// been successfully parsed by parsePrefixSIDSubTLV, for combinations that are
+// not valid according to RFC8667. Such combinations indicate that the encoder
+// is non-compliant, but do not prevent the sub-TLV being parsed. Returns an
+// error if an implausible combination is found.
+func validatePrefixSIDFlags(r *rawTLV) error {
+	if len(r.Value) == 0 {
+		return nil
+	}
+
+	flags := r.Value[0]
+	noPHP, explicitNull := flags&bit2 != 0, flags&bit3 != 0
+
+	// Explicit NULL is signalled to the penultimate hop, and hence cannot be
+	// requested where PHP is being performed.
+	if explicitNull && !noPHP {
+		return errors.New("invalid Prefix-SID flags, EXPLICIT_NULL set without NO_PHP")
+	}
+	return nil
+}
+
 // addIPv6ReachabilityPrefixSID adds the contents of a prefixSIDSubTLV to the supplied
 // IPv6 Reachability prefix TLV. Return an error if adding the contents is not possible.
 func addIPv6ReachabilityPrefixSID(c *oc.Lsp_Tlv_Ipv6Reachability_Prefix, p *prefixSIDSubTLV) error {
@@ -658,6 +1196,91 @@ func (i *isisLSP) processTERouterIDTLV(r *rawTLV) error {
 	return nil
 }
 
+// processIPv6TERouterIDTLV parses TLV type 140, extracting the 16-byte IPv6
+// TE Router ID. Defined by RFC6119. Returns an error if the input is invalid.
+func (i *isisLSP) processIPv6TERouterIDTLV(r *rawTLV) error {
+	if len(r.Value) != 16 {
+		return fmt.Errorf("invalid length IPv6 Router ID TLV: %d", len(r.Value))
+	}
+
+	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID, ipv6TERouterIDContainer)
+	if err != nil {
+		return err
+	}
+
+	ip6, err := i.ip6BytesToString(r.Value)
+	if err != nil {
+		return err
+	}
+
+	tlv.Ipv6TeRouterId.RouterId = append(tlv.Ipv6TeRouterId.RouterId, ip6)
+	return nil
+}
+
+// processISReachabilityTLV parses the IS reachability TLV (type 2) defined in
+// ISO10589, which uses narrow (6-bit) metrics. It is stored separately from the
+// extended IS reachability TLV (22), such that LSPs that carry both TLVs can be
+// represented. Returns an error if the input is invalid.
+func (i *isisLSP) processISReachabilityTLV(r *rawTLV) error {
+	// Encoding of this TLV is:
+	// 1 octet of virtual flag
+	// Followed by repeated entries of:
+	//	1 octet of default metric
+	//	1 octet of delay metric
+	//	1 octet of expense metric
+	//	1 octet of error metric
+	//	7 octets of neighbor ID
+	if len(r.Value) < 1 || (len(r.Value)-1)%11 != 0 {
+		return fmt.Errorf("invalid length IS Reachability TLV (2), %d is not 1 + a multiple of 11", len(r.Value))
+	}
+
+	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS, isReachabilityContainer)
+	if err != nil {
+		return err
+	}
+
+	var pErr errlist.List
+	for x := 1; x < len(r.Value); x += 11 {
+		nid := i.internSystemID(r.Value[x+4 : x+11])
+		n, err := tlv.IsReachability.NewNeighbor(nid)
+		if err != nil {
+			pErr.Add(fmt.Errorf("cannot add neighbor %s to IS Reachability TLV: %v", nid, err))
+			continue
+		}
+
+		n.DefaultMetric = &oc.Lsp_Tlv_IsReachability_Neighbor_DefaultMetric{
+			Metric: ygot.Uint8(r.Value[x] & 0x3F),
+		}
+		if r.Value[x]&bit1 == 0 {
+			n.DefaultMetric.Flags = oc.OpenconfigIsis_DefaultMetric_Flags_INTERNAL
+		}
+
+		dm, dflags := narrowMetric(r.Value[x+1])
+		n.DelayMetric = &oc.Lsp_Tlv_IsReachability_Neighbor_DelayMetric{Metric: dm, Flags: dflags}
+		em, eflags := narrowMetric(r.Value[x+2])
+		n.ExpenseMetric = &oc.Lsp_Tlv_IsReachability_Neighbor_ExpenseMetric{Metric: em, Flags: eflags}
+		rm, rflags := narrowMetric(r.Value[x+3])
+		n.ErrorMetric = &oc.Lsp_Tlv_IsReachability_Neighbor_ErrorMetric{Metric: rm, Flags: rflags}
+	}
+
+	return pErr.Err()
+}
+
+// narrowMetric parses the delay, expense or error metric byte of the narrow
+// metric TLVs defined in ISO10589, returning the 6-bit metric value and its
+// flags. The S (unsupported) bit is bit 0 of the byte, and the I/E bit, which
+// is unset for internal metrics, is bit 1.
+func narrowMetric(b uint8) (*uint8, []oc.E_OpenconfigIsis_IsisMetricFlags) {
+	var flags []oc.E_OpenconfigIsis_IsisMetricFlags
+	if b&bit0 != 0 {
+		flags = append(flags, oc.OpenconfigIsis_IsisMetricFlags_UNSUPPORTED)
+	}
+	if b&bit1 == 0 {
+		flags = append(flags, oc.OpenconfigIsis_IsisMetricFlags_INTERNAL)
+	}
+	return ygot.Uint8(b & 0x3F), flags
+}
+
 // processExtendedISReachabilityTLV parses TLV type 22. Defined by RFC5305.
 // Returns an error if the input is invalid.
 func (i *isisLSP) processExtendedISReachabilityTLV(r *rawTLV) error {
@@ -708,7 +1331,7 @@ func (i *isisLSP) processExtendedISReachabilityTLV(r *rawTLV) error {
 			continue
 		}
 
-		nid := canonicalHexString(r.Value[x : x+7])
+		nid := i.internSystemID(r.Value[x : x+7])
 		var n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor
 
 		if t, ok := tlv.ExtendedIsReachability.Neighbor[nid]; ok {
@@ -726,13 +1349,15 @@ func (i *isisLSP) processExtendedISReachabilityTLV(r *rawTLV) error {
 		// adjacencies between the same two ISes. There is no expectation
 		// that two instances will have the same identifier with subsequent
 		// parses of an LSP if the order changes.
-		// It is always safe to call GetOrCreate here since we dynamically
-		// compute the key.
-		inst := n.GetOrCreateInstance(uint64(len(n.Instance)))
+		// It is always safe to call GetOrCreate here since the key is
+		// allocated such that it is not already in use.
+		inst := n.GetOrCreateInstance(i.nextISReachInstanceID(nid, n))
 
 		inst.Metric = ygot.Uint32(defmetric)
 
-		if err := parseExtendedISReachSubTLVs(inst, subTLVs); err != nil {
+		unknown, err := i.decodeExtendedISReachSubTLVs(inst, subTLVs)
+		i.addUnknownSubTLVs(unknown)
+		if err != nil {
 			pErr.Add(err)
 			continue
 		}
@@ -741,11 +1366,40 @@ func (i *isisLSP) processExtendedISReachabilityTLV(r *rawTLV) error {
 	return pErr.Err()
 }
 
+// nextISReachInstanceID returns the ID to be used for a new instance of the
+// extended IS reachability neighbor n, whose neighbor ID is nid. IDs are
+// allocated from a counter that is maintained for each neighbor for the
+// duration of the parse, such that the instances of a neighbor that is
+// advertised in multiple extended IS reachability TLVs are numbered in the
+// order in which they appear in the LSP, and never collide with an existing
+// instance.
+func (i *isisLSP) nextISReachInstanceID(nid string, n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor) uint64 {
+	if i.isReachInstances == nil {
+		i.isReachInstances = map[string]uint64{}
+	}
+
+	id := i.isReachInstances[nid]
+	for n.Instance[id] != nil {
+		id++
+	}
+	i.isReachInstances[nid] = id + 1
+	return id
+}
+
 // parseExtendedISReachSubTLVs parses the subTLVs of the extended IS reachability
 // TLV, appending them to the instance provided. Returns an error if parsing is
 // unsuccesful.
 func parseExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, subTLVs []*rawTLV) error {
+	_, err := (&isisLSP{}).decodeExtendedISReachSubTLVs(n, subTLVs)
+	return err
+}
+
+// decodeExtendedISReachSubTLVs implements parseExtendedISReachSubTLVs, and
+// additionally returns the number of sub-TLVs of unknown type that were
+// stored as undefined sub-TLVs.
+func (i *isisLSP) decodeExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance, subTLVs []*rawTLV) (int, error) {
 	var pErr errlist.List
+	var unknown int
 	for _, s := range subTLVs {
 		switch s.Type {
 		case 3:
@@ -799,6 +1453,54 @@ func parseExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_I
 			}
 
 			tlv.Ipv4NeighborAddress.Address = append(tlv.Ipv4NeighborAddress.Address, a)
+		case 12:
+			a, err := i.parseIPv6InterfaceSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS, extISReachIPv6InterfaceAddress)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv.Ipv6InterfaceAddress.Address = append(tlv.Ipv6InterfaceAddress.Address, a)
+		case 13:
+			a, err := i.parseIPv6InterfaceSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS, extISReachIPv6NeighborAddress)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv.Ipv6NeighborAddress.Address = append(tlv.Ipv6NeighborAddress.Address, a)
+		case 14:
+			words, err := parseExtendedAdminGroupSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP, extISReachExtendedAdminGroup)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			// The position of each word within the bitmask is significant,
+			// so the words of a repeated sub-TLV cannot be appended.
+			if tlv.ExtendedAdminGroup.ExtendedAdminGroup != nil {
+				pErr.Add(fmt.Errorf("duplicate extended administrative group sub-TLV"))
+				continue
+			}
+			tlv.ExtendedAdminGroup.ExtendedAdminGroup = words
 		case 9:
 			b, err := parseLinkBandwidthSubTLV(s)
 			if err != nil {
@@ -850,6 +1552,34 @@ func parseExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_I
 				}
 			}
 
+		case 18:
+			m, err := parseTEDefaultMetricSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_TE_DEFAULT_METRIC, extISReachTEDefaultMetric)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.TeDefaultMetric.Metric = ygot.Uint32(m)
+
+		case 20:
+			types, err := parseLinkProtectionTypeSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE, extISReachLinkProtectionType)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.LinkProtectionType.Type = append(tlv.LinkProtectionType.Type, types...)
+
 		case 31:
 			adjs, err := parseAdjSIDSubTLV(s)
 			if err != nil {
@@ -874,6 +1604,62 @@ func parseExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_I
 				continue
 			}
 
+		case 33:
+			anomalous, delay, err := parseLinkDelaySubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY, extISReachLinkDelay)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.LinkDelay.ABit = ygot.Bool(anomalous)
+			tlv.LinkDelay.Delay = ygot.Uint32(delay)
+		case 34:
+			anomalous, minDelay, maxDelay, err := parseMinMaxLinkDelaySubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY, extISReachMinMaxLinkDelay)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.MinMaxLinkDelay.ABit = ygot.Bool(anomalous)
+			tlv.MinMaxLinkDelay.MinDelay = ygot.Uint32(minDelay)
+			tlv.MinMaxLinkDelay.MaxDelay = ygot.Uint32(maxDelay)
+		case 35:
+			v, err := parseLinkDelayVariationSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY_VARIATION, extISReachLinkDelayVariation)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.LinkDelayVariation.Delay = ygot.Uint32(v)
+		case 36:
+			anomalous, loss, err := parseLinkLossSubTLV(s)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			tlv, err := getExtendedISReachSubTLV(n, oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS, extISReachLinkLoss)
+			if err != nil {
+				pErr.Add(err)
+				continue
+			}
+			tlv.LinkLoss.ABit = ygot.Bool(anomalous)
+			tlv.LinkLoss.LinkLoss = ygot.Uint32(loss)
 		case 38:
 			b, err := parseLinkBandwidthSubTLV(s)
 			if err != nil {
@@ -887,13 +1673,33 @@ func parseExtendedISReachSubTLVs(n *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_I
 				continue
 			}
 			tlv.ResidualBandwidth.Bandwidth = b
+		case aslaSubTLVType:
+			// Application-specific link attributes, RFC8919.
+			if _, err := i.parseASLASubTLV(s); err != nil {
+				pErr.Add(err)
+				continue
+			}
+			appendASLASubTLV(n, s)
+		case linkMSDSubTLVType:
+			// Link MSD, RFC8491.
+			if _, err := parseMSDSubTLV(s); err != nil {
+				pErr.Add(err)
+				continue
+			}
+
+			if err := addExtendedISReachUndefinedSubTLV(n, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store link MSD sub-TLV: %v", err))
+				continue
+			}
 		default:
-			// TODO(robjs): Append to undefined subTLV list.
-			continue
+			unknown++
+			if err := addExtendedISReachUndefinedSubTLV(n, s); err != nil {
+				pErr.Add(fmt.Errorf("cannot store undefined sub-TLV of type %d: %v", s.Type, err))
+			}
 		}
 	}
 
-	return pErr.Err()
+	return unknown, pErr.Err()
 }
 
 // parseAdministrativeGroupSubTLV parses sub-TLV 3 of the IS adjacency TLVs,
@@ -908,6 +1714,70 @@ func parseAdministrativeGroupSubTLV(r *rawTLV) (uint32, error) {
 	return mask, nil
 }
 
+// parseExtendedAdminGroupSubTLV parses sub-TLV 14 of the IS adjacency TLVs,
+// the extended administrative group defined in RFC7308. The value is a
+// variable length bitmask, which is returned as a slice of 4-byte words, such
+// that word i holds the bits for administrative groups 32*i to 32*i+31.
+func parseExtendedAdminGroupSubTLV(r *rawTLV) ([]uint32, error) {
+	if len(r.Value)%4 != 0 {
+		return nil, fmt.Errorf("invalid extended administrative group sub-TLV, length was not a multiple of 4: %d", len(r.Value))
+	}
+
+	words := make([]uint32, 0, len(r.Value)/4)
+	for x := 0; x < len(r.Value); x += 4 {
+		w, err := binaryToUint32(r.Value[x : x+4])
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, w)
+	}
+	return words, nil
+}
+
+// parseTEDefaultMetricSubTLV parses sub-TLV 18 of the IS adjacency TLVs, the
+// 3-byte TE default metric defined in RFC5305. Returns the metric, or an error
+// if the sub-TLV is not 3 bytes long.
+func parseTEDefaultMetricSubTLV(r *rawTLV) (uint32, error) {
+	if len(r.Value) != 3 {
+		return 0, fmt.Errorf("incorrect length for TE default metric sub-TLV: %d != 3", len(r.Value))
+	}
+	return binaryToUint32([]byte{0x0, r.Value[0], r.Value[1], r.Value[2]})
+}
+
+// linkProtectionTypeBits maps the bits of the link protection capabilities
+// field to the OpenConfig enumerated type for link protection, ordered from the
+// most significant bit. The bit assignments are defined in RFC4203.
+var linkProtectionTypeBits = []struct {
+	bit uint8
+	typ oc.E_OpenconfigIsis_LinkProtectionType_Type
+}{
+	{bit2, oc.OpenconfigIsis_LinkProtectionType_Type_ENHANCED},
+	{bit3, oc.OpenconfigIsis_LinkProtectionType_Type_PLUS_ONE},
+	{bit4, oc.OpenconfigIsis_LinkProtectionType_Type_ONE_ONE},
+	{bit5, oc.OpenconfigIsis_LinkProtectionType_Type_SHARED},
+	{bit6, oc.OpenconfigIsis_LinkProtectionType_Type_UNPROTECTED},
+	{bit7, oc.OpenconfigIsis_LinkProtectionType_Type_EXTRA_TRAFFIC},
+}
+
+// parseLinkProtectionTypeSubTLV parses sub-TLV 20 of the IS adjacency TLVs,
+// the link protection type defined in RFC5307. The sub-TLV consists of a
+// 1-byte protection capabilities field followed by a reserved byte. Returns
+// the protection types that are set, or an error if the sub-TLV is not 2 bytes
+// long.
+func parseLinkProtectionTypeSubTLV(r *rawTLV) ([]oc.E_OpenconfigIsis_LinkProtectionType_Type, error) {
+	if len(r.Value) != 2 {
+		return nil, fmt.Errorf("incorrect length for link protection type sub-TLV: %d != 2", len(r.Value))
+	}
+
+	var types []oc.E_OpenconfigIsis_LinkProtectionType_Type
+	for _, p := range linkProtectionTypeBits {
+		if r.Value[0]&p.bit != 0 {
+			types = append(types, p.typ)
+		}
+	}
+	return types, nil
+}
+
 // parseLinkLocalRemoteSubTLV parses sub-TLV 4 of the IS adjacency TLVs
 // 22, 23, 141, 222 and 223. It returns two uint32s, the first specifies
 // the local link ID, and the second being the remote link ID. The link
@@ -944,6 +1814,16 @@ func parseIPv4InterfaceSubTLV(r *rawTLV) (string, error) {
 	return addr, nil
 }
 
+// parseIPv6InterfaceSubTLV parses sub-TLV 12 or 13 of the IS adjacency
+// TLVs as defined in RFC6119. Returns a string containing the IPv6 address
+// which is within the TLV, or an error if the sub-TLV is not 16 bytes long.
+func (i *isisLSP) parseIPv6InterfaceSubTLV(r *rawTLV) (string, error) {
+	if len(r.Value) != 16 {
+		return "", fmt.Errorf("IPv6 interface sub-TLV (type %d) had incorrect length: %d != 16", r.Type, len(r.Value))
+	}
+	return i.ip6BytesToString(r.Value)
+}
+
 // parseLinkBandwidthSubTLV parses sub-TLV 9 or 10 of the IS adjacency TLVs 22,
 // 23, 141, 222 and 223. Returns a []byte containing a float32 representing the
 // bandwidth level communicated within the TLV, or an error if encountered.
@@ -955,6 +1835,67 @@ func parseLinkBandwidthSubTLV(r *rawTLV) ([]byte, error) {
 	return r.Value, nil
 }
 
+// parseLinkDelaySubTLV parses sub-TLV 33 of the IS adjacency TLVs, the
+// unidirectional link delay defined in RFC8570. It returns whether the
+// anomalous (A) flag is set, and the delay in microseconds, which is carried
+// in the low 24 bits of the value.
+func parseLinkDelaySubTLV(r *rawTLV) (bool, uint32, error) {
+	if len(r.Value) != 4 {
+		return false, 0, fmt.Errorf("incorrect length for unidirectional link delay sub-TLV: %d != 4", len(r.Value))
+	}
+	delay := uint32(r.Value[1])<<16 | uint32(r.Value[2])<<8 | uint32(r.Value[3])
+	return r.Value[0]&bit0 != 0, delay, nil
+}
+
+// parseMinMaxLinkDelaySubTLV parses sub-TLV 34 of the IS adjacency TLVs, the
+// minimum and maximum unidirectional link delay defined in RFC8570. It returns
+// whether the anomalous (A) flag is set, and the minimum and maximum delay in
+// microseconds, each of which is carried in a 3-byte field.
+func parseMinMaxLinkDelaySubTLV(r *rawTLV) (bool, uint32, uint32, error) {
+	if len(r.Value) != 7 {
+		return false, 0, 0, fmt.Errorf("incorrect length for min/max unidirectional link delay sub-TLV: %d != 7", len(r.Value))
+	}
+
+	minDelay, err := binaryToUint32([]byte{0x0, r.Value[1], r.Value[2], r.Value[3]})
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("invalid minimum delay: %v", err)
+	}
+
+	maxDelay, err := binaryToUint32([]byte{0x0, r.Value[4], r.Value[5], r.Value[6]})
+	if err != nil {
+		return false, 0, 0, fmt.Errorf("invalid maximum delay: %v", err)
+	}
+
+	return r.Value[0]&bit0 != 0, minDelay, maxDelay, nil
+}
+
+// parseLinkDelayVariationSubTLV parses sub-TLV 35 of the IS adjacency TLVs,
+// the unidirectional delay variation defined in RFC8570. The first byte is
+// reserved, and the variation in microseconds is carried in the remaining 3
+// bytes.
+func parseLinkDelayVariationSubTLV(r *rawTLV) (uint32, error) {
+	if len(r.Value) != 4 {
+		return 0, fmt.Errorf("incorrect length for unidirectional delay variation sub-TLV: %d != 4", len(r.Value))
+	}
+	return binaryToUint32([]byte{0x0, r.Value[1], r.Value[2], r.Value[3]})
+}
+
+// parseLinkLossSubTLV parses sub-TLV 36 of the IS adjacency TLVs, the
+// unidirectional link loss defined in RFC8570. It returns whether the
+// anomalous (A) flag is set, and the raw 3-byte loss value, which is in units
+// of 0.000003% of packets; scaling is left to the consumer.
+func parseLinkLossSubTLV(r *rawTLV) (bool, uint32, error) {
+	if len(r.Value) != 4 {
+		return false, 0, fmt.Errorf("incorrect length for unidirectional link loss sub-TLV: %d != 4", len(r.Value))
+	}
+
+	loss, err := binaryToUint32([]byte{0x0, r.Value[1], r.Value[2], r.Value[3]})
+	if err != nil {
+		return false, 0, err
+	}
+	return r.Value[0]&bit0 != 0, loss, nil
+}
+
 // parseUnreservedBandwidthSubTLV parses sub-TLV 11 of TLVs 22, 23, 25, 141, 222
 // and 223 extracting the bandwidth per priority level. It returns a map, keyed by
 // priority level, of the unreserved bandwidth reported within the TLV.
@@ -1050,7 +1991,7 @@ func parseLANAdjSIDSubTLV(r *rawTLV) (*oc.Lsp_Tlv_ExtendedIsReachability_Neighbo
 		return nil, fmt.Errorf("cannot parse weight in LAN adjacency SID, %v", err)
 	}
 
-	neighID := canonicalHexString(r.Value[2:8])
+	neighID := fastCanonicalHexString(r.Value[2:8])
 
 	value, err := adjSIDValue(r.Value[8:], isValue, isLocal)
 	if err != nil {
@@ -1069,6 +2010,10 @@ func parseLANAdjSIDSubTLV(r *rawTLV) (*oc.Lsp_Tlv_ExtendedIsReachability_Neighbo
 // sub-TLV. It returns a slice containing the OpenConfig enumerated value
 // indicating the flags, and a pair of bools which indicate whether the value
 // and local flags are set.
+//
+// The address family (F) flag does not change the encoding of the SID, and
+// hence is reported only as a flag. When set, the SID refers to an adjacency
+// that is used for IPv6 forwarding, rather than IPv4.
 func adjSIDFlags(flagByte uint8) ([]oc.E_OpenconfigIsis_AdjacencySid_Flags, bool, bool) {
 	var flags []oc.E_OpenconfigIsis_AdjacencySid_Flags
 	if b := flagByte & bit0; b != 0 {
@@ -1095,7 +2040,8 @@ func adjSIDFlags(flagByte uint8) ([]oc.E_OpenconfigIsis_AdjacencySid_Flags, bool
 		flags = append(flags, oc.OpenconfigIsis_AdjacencySid_Flags_SET)
 	}
 
-	// TODO(robjs): OpenConfig model is currently missing the persistent flag.
+	// TODO(robjs): OpenConfig model is currently missing the persistent flag
+	// (bit 5), and hence it is not reported.
 
 	return flags, isValue, isLocal
 }
@@ -1132,7 +2078,8 @@ func lanAdjSIDFlags(flagByte uint8) ([]oc.E_OpenconfigIsis_LanAdjacencySid_Flags
 		flags = append(flags, oc.OpenconfigIsis_LanAdjacencySid_Flags_SET)
 	}
 
-	// TODO(robjs): OpenConfig model is currently missing the persistent flag.
+	// TODO(robjs): OpenConfig model is currently missing the persistent flag
+	// (bit 5), and hence it is not reported.
 
 	return flags, isValue, isLocal
 }
@@ -1166,6 +2113,30 @@ func adjSIDValue(valbytes []byte, isValue, isLocal bool) (uint32, error) {
 	return 0, fmt.Errorf("invalid combination of value and local flagByte, value: %v, local: %v", isValue, isLocal)
 }
 
+const (
+	// ipv4PrefixEntryHint and ipv6PrefixEntryHint are the typical lengths of
+	// a prefix entry in the IPv4 and IPv6 reachability TLVs, corresponding to
+	// a /24 and /64 prefix respectively, without sub-TLVs. They are used to
+	// estimate the number of prefixes in an LSP.
+	ipv4PrefixEntryHint = 8
+	ipv6PrefixEntryHint = 14
+)
+
+// prefixCountHint returns an estimate of the number of prefixes that are
+// contained in the TLVs of type t within the LSP, assuming that each prefix
+// entry is entryLen bytes long. It is used to size prefix maps such that they
+// are allocated once for the LSP, rather than being grown as each TLV of the
+// type is processed.
+func (i *isisLSP) prefixCountHint(t uint8, entryLen int) int {
+	var n int
+	for _, r := range i.rawTLVs {
+		if r.Type == t {
+			n += len(r.Value)
+		}
+	}
+	return n / entryLen
+}
+
 // processExtendedIPReachTLV process the Extended IP Reachability TLV (type 135).
 // Defined by RFC5305. Returns an error if any is encountered during processing.
 func (i *isisLSP) processExtendedIPReachTLV(r *rawTLV) error {
@@ -1174,6 +2145,18 @@ func (i *isisLSP) processExtendedIPReachTLV(r *rawTLV) error {
 		return err
 	}
 
+	if tlv.ExtendedIpv4Reachability.Prefix == nil {
+		tlv.ExtendedIpv4Reachability.Prefix = make(map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix, i.prefixCountHint(r.Type, ipv4PrefixEntryHint))
+	}
+	return i.parseExtendedIPReachPrefixes(r.Value, tlv.ExtendedIpv4Reachability.Prefix)
+}
+
+// parseExtendedIPReachPrefixes parses the repeated prefix entries of the
+// Extended IP Reachability encoding defined in RFC5305 that are contained in b,
+// adding each prefix to the pfxs map, keyed by prefix. The encoding is shared
+// by the Extended IP Reachability TLV (type 135) and the MT IPv4 Reachability
+// TLV (type 235). Returns an error if any is encountered during processing.
+func (i *isisLSP) parseExtendedIPReachPrefixes(b []byte, pfxs map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix) error {
 	// Encoding of this TLV is:
 	// 4-octets of metric.
 	// 1 octet of control:
@@ -1189,26 +2172,26 @@ func (i *isisLSP) processExtendedIPReachTLV(r *rawTLV) error {
 	// Used to track the size of the TLV instance.
 	var s int
 	var pErr errlist.List
-	for x := 0; x < len(r.Value); x = s {
-		if len(r.Value) < x+5 {
+	for x := 0; x < len(b); x = s {
+		if len(b) < x+5 {
 			// Must have at least the metric and control bytes present.
-			return fmt.Errorf("invalid Extended IP Reachability TLV, insufficient data - at position %d, total length: %d", x, len(r.Value))
+			return fmt.Errorf("invalid Extended IP Reachability TLV, insufficient data - at position %d, total length: %d", x, len(b))
 		}
-		metric, err := binaryToUint32(r.Value[x : x+4])
+		metric, err := binaryToUint32(b[x : x+4])
 		if err != nil {
 			return err
 		}
 
 		var upDown, subTLVPresent bool
-		if ubit := r.Value[x+4] & bit0; ubit != 0 {
+		if ubit := b[x+4] & bit0; ubit != 0 {
 			upDown = true
 		}
 
-		if sbit := r.Value[x+4] & bit1; sbit != 0 {
+		if sbit := b[x+4] & bit1; sbit != 0 {
 			subTLVPresent = true
 		}
 
-		pfxLen := int(r.Value[x+4] &^ 0xC0) // clear bits 0 and 1
+		pfxLen := int(b[x+4] &^ 0xC0) // clear bits 0 and 1
 		if pfxLen > 32 {
 			// Fatal as we cannot determine how many bytes the
 			// prefix might use.
@@ -1217,28 +2200,27 @@ func (i *isisLSP) processExtendedIPReachTLV(r *rawTLV) error {
 		ipBytes := make([]byte, 4)
 		ipB := int((pfxLen + 7) / 8)
 
-		if len(r.Value) < x+5+ipB {
+		if len(b) < x+5+ipB {
 			// Fatal as we will panic in the parsing of the address if this is not the case.
-			return fmt.Errorf("insufficient bytes for IPv4 prefix within TLV, length: %d, expected: %d", len(r.Value), x+5+ipB)
+			return fmt.Errorf("insufficient bytes for IPv4 prefix within TLV, length: %d, expected: %d", len(b), x+5+ipB)
 		}
 
 		for j := 0; j < ipB; j++ {
-			ipBytes[j] = r.Value[x+5+j]
+			ipBytes[j] = b[x+5+j]
 		}
 
-		pfx, err := ip4BytesToString(ipBytes)
+		// Track current size of the TLV. This must be updated prior to any
+		// further checks, such that a prefix that is skipped does not cause
+		// the same bytes to be parsed again.
+		s = x + 5 + ipB
+
+		v4Pfx, err := i.intern(internIPv4Prefix, ipBytes, uint8(pfxLen), func() (string, error) {
+			return ip4PrefixToString(ipBytes, pfxLen)
+		})
 		if err != nil {
 			pErr.Add(err)
 			continue
 		}
-		v4Pfx := fmt.Sprintf("%s/%d", pfx, pfxLen)
-
-		// Track current size of the TLV
-		s = x + 5 + ipB
-
-		if _, ok := tlv.ExtendedIpv4Reachability.Prefix[v4Pfx]; ok {
-			return err
-		}
 
 		pfxTLV := &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
 			Prefix: ygot.String(v4Pfx),
@@ -1248,45 +2230,95 @@ func (i *isisLSP) processExtendedIPReachTLV(r *rawTLV) error {
 		}
 
 		if subTLVPresent {
-			if len(r.Value) < s+1 {
+			if len(b) < s+1 {
 				return fmt.Errorf("invalid length Extended IP Reachability TLV, subTLVs present but no length byte exists")
 			}
 
-			subTLVLen := int(r.Value[s])
+			subTLVLen := int(b[s])
 
-			if len(r.Value) < s+1+subTLVLen {
-				return fmt.Errorf("invalid length Extended IP Reachability TLV, subTLV length %d but byte length %d", s+subTLVLen, len(r.Value))
+			if len(b) < s+1+subTLVLen {
+				return fmt.Errorf("invalid length Extended IP Reachability TLV, subTLV length %d but byte length %d", s+1+subTLVLen, len(b))
 			}
 
-			subTLVs, err := TLVBytesToTLVs(r.Value[s+1 : s+1+subTLVLen])
+			subTLVs, err := TLVBytesToTLVs(b[s+1 : s+1+subTLVLen])
 			if err != nil {
 				return fmt.Errorf("invalid sub-TLVs in ExtendedIPReachability TLV: %v", err)
 			}
 
 			for _, st := range subTLVs {
 				switch st.Type {
+				case 1:
+					tags, err := parseAdminTagSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						continue
+					}
+					tag := pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetOrCreateTag()
+					tag.Tag32 = append(tag.Tag32, tags...)
+				case 2:
+					tags, err := parseAdminTag64SubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						continue
+					}
+					tag := pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64).GetOrCreateTag64()
+					tag.Tag64 = append(tag.Tag64, tags...)
 				case 3:
 					pfxseg, err := parsePrefixSIDSubTLV(st)
 					if err != nil {
 						pErr.Add(err)
 						continue
 					}
+					pErr.Add(validatePrefixSIDFlags(st))
 
 					if err := addExtendedIPReachabilityPrefixSID(pfxTLV, pfxseg); err != nil {
 						pErr.Add(err)
 					}
+				case 4:
+					flags, err := parsePrefixAttributeFlagsSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						continue
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS).GetOrCreateFlags().Flags = flags
+				case 11:
+					rid, err := parseIPv4SourceRouterIDSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						continue
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID).GetOrCreateIpv4SourceRouterId().RouterId = ygot.String(rid)
+				case 12:
+					rid, err := i.parseIPv6SourceRouterIDSubTLV(st)
+					if err != nil {
+						pErr.Add(err)
+						continue
+					}
+					pfxTLV.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID).GetOrCreateIpv6SourceRouterId().RouterId = ygot.String(rid)
 				default:
-					// TODO(robjs): Add to unknown subTLV list.
+					// TODO(robjs): Preserve this sub-TLV as an undefined
+					// sub-TLV. Only the count of unknown sub-TLVs is kept, and
+					// the sub-TLV itself is discarded.
+					i.addUnknownSubTLVs(1)
 					pErr.Add(fmt.Errorf("for prefix %s unimplemented sub-TLV parsing for type %d in Extended IP Reachability TLV", v4Pfx, st.Type))
 				}
 			}
 			s += 1 + subTLVLen
 		}
 
-		if tlv.ExtendedIpv4Reachability.Prefix == nil {
-			tlv.ExtendedIpv4Reachability.Prefix = make(map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix)
+		// A prefix that is advertised more than once is invalid, and hence
+		// the first instance is retained, such that the result does not
+		// depend on which instance is considered. The duplicate has been
+		// fully consumed, such that subsequent prefixes are still parsed.
+		if _, ok := pfxs[v4Pfx]; ok {
+			pErr.Add(fmt.Errorf("duplicate prefix %s in Extended IP Reachability TLV, retaining first instance", v4Pfx))
+			continue
 		}
-		tlv.ExtendedIpv4Reachability.Prefix[v4Pfx] = pfxTLV
+		pfxs[v4Pfx] = pfxTLV
+	}
+
+	if s != len(b) {
+		return fmt.Errorf("invalid Extended IP Reachability TLV, does not have correct length: %d != %d, remaining bytes: %v", s, len(b), b[s:])
 	}
 
 	return pErr.Err()
@@ -1312,23 +2344,111 @@ func addExtendedIPReachabilityPrefixSID(c *oc.Lsp_Tlv_ExtendedIpv4Reachability_P
 	return nil
 }
 
+// processMTIPv4ReachabilityTLV processes the MT IPv4 Reachability TLV (type 235)
+// defined in RFC5120. The TLV consists of a 2-byte field, the lower 12 bits of
+// which are the MT ID, followed by prefix entries in the same format as the
+// Extended IP Reachability TLV (type 135). Returns an error if any is
+// encountered during processing.
+func (i *isisLSP) processMTIPv4ReachabilityTLV(r *rawTLV) error {
+	if len(r.Value) < 2 {
+		return fmt.Errorf("invalid MT IPv4 Reachability TLV, insufficient data for MT ID, length: %d", len(r.Value))
+	}
+
+	tlv, err := i.getTLVAndInit(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY, mtIPv4ReachabilityContainer)
+	if err != nil {
+		return err
+	}
+
+	id, err := mtID(r.Value[0:2])
+	if err != nil {
+		return err
+	}
+
+	// Prefixes that are parsed prior to a fatal error are retained, as per
+	// the Extended IP Reachability TLV, hence the error is checked only after
+	// the parsed prefixes have been added.
+	pfxs := make(map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix, len(r.Value)/ipv4PrefixEntryHint)
+	var pErr errlist.List
+	pErr.Add(i.parseExtendedIPReachPrefixes(r.Value[2:], pfxs))
+
+	for _, p := range pfxs {
+		if err := tlv.MtIpv4Reachability.AppendPrefix(mtIPv4Prefix(id, p)); err != nil {
+			pErr.Add(fmt.Errorf("cannot add prefix %s in MT ID %d to MT IPv4 Reachability TLV: %v", *p.Prefix, id, err))
+		}
+	}
+
+	return pErr.Err()
+}
+
+// mtIPv4Prefix returns the prefix p, parsed from the Extended IP Reachability
+// encoding, as a prefix within the MT IPv4 Reachability TLV for MT ID id. The
+// OpenConfig model uses distinct, but identical, types for the prefixes of the
+// two TLVs, such that the sub-TLVs of p are converted rather than copied, and
+// are hence shared with p.
+func mtIPv4Prefix(id uint16, p *oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix) *oc.Lsp_Tlv_MtIpv4Reachability_Prefix {
+	mp := &oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+		MtId:   ygot.Uint16(id),
+		Prefix: p.Prefix,
+		Metric: p.Metric,
+		SBit:   p.SBit,
+		UpDown: p.UpDown,
+	}
+
+	for t, st := range p.Subtlv {
+		mst := &oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv{
+			Type:               st.Type,
+			Flags:              (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Flags)(st.Flags),
+			Ipv4SourceRouterId: (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Ipv4SourceRouterId)(st.Ipv4SourceRouterId),
+			Ipv6SourceRouterId: (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Ipv6SourceRouterId)(st.Ipv6SourceRouterId),
+			Tag:                (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Tag)(st.Tag),
+			Tag64:              (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Tag64)(st.Tag64),
+		}
+		for v, sid := range st.PrefixSid {
+			if mst.PrefixSid == nil {
+				mst.PrefixSid = map[uint32]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_PrefixSid{}
+			}
+			mst.PrefixSid[v] = (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_PrefixSid)(sid)
+		}
+		if mp.Subtlv == nil {
+			mp.Subtlv = map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv{}
+		}
+		mp.Subtlv[t] = mst
+	}
+
+	for t, u := range p.UndefinedSubtlv {
+		if mp.UndefinedSubtlv == nil {
+			mp.UndefinedSubtlv = map[uint8]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_UndefinedSubtlv{}
+		}
+		mp.UndefinedSubtlv[t] = (*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_UndefinedSubtlv)(u)
+	}
+
+	return mp
+}
+
+// lspFlagBits maps the bits of the LSP flags field to the OpenConfig
+// enumerated type for LSP flags. Entries are ordered from the most
+// significant bit, such that flags are always reported in the same order.
+var lspFlagBits = []struct {
+	bit  uint8
+	flag oc.E_OpenconfigIsis_Lsp_Flags
+}{
+	{bit0, oc.OpenconfigIsis_Lsp_Flags_PARTITION_REPAIR},
+	{bit1, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_ERROR},
+	{bit2, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_EXPENSE},
+	{bit3, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DELAY},
+	{bit4, oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT},
+	{bit5, oc.OpenconfigIsis_Lsp_Flags_OVERLOAD},
+}
+
 // parseLSPFlags parses the contents of the LSP flags field, and returns
 // a slice of the OpenConfig enumerated type for LSP flags for each flag that is
-// set in the attrs byte.
+// set in the attrs byte. Flags are returned in the order of their bit position
+// in the field, starting from the most significant bit.
 func parseLSPFlags(attrs uint8) []oc.E_OpenconfigIsis_Lsp_Flags {
 	var flags []oc.E_OpenconfigIsis_Lsp_Flags
-	bitmap := map[uint8]oc.E_OpenconfigIsis_Lsp_Flags{
-		bit0: oc.OpenconfigIsis_Lsp_Flags_PARTITION_REPAIR,
-		bit1: oc.OpenconfigIsis_Lsp_Flags_ATTACHED_ERROR,
-		bit2: oc.OpenconfigIsis_Lsp_Flags_ATTACHED_EXPENSE,
-		bit3: oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DELAY,
-		bit4: oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT,
-		bit5: oc.OpenconfigIsis_Lsp_Flags_OVERLOAD,
-	}
-
-	for bit, flag := range bitmap {
-		if b := attrs & bit; b != 0 {
-			flags = append(flags, flag)
+	for _, f := range lspFlagBits {
+		if attrs&f.bit != 0 {
+			flags = append(flags, f.flag)
 		}
 	}
 	return flags
@@ -15,6 +15,8 @@
 package lsdbparse
 
 import (
+	"bytes"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -85,11 +87,102 @@ func TestTLVBytesToTLVs(t *testing.T) {
 	}
 }
 
+func TestTLVsToTLVBytes(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               []*rawTLV
+		want             []byte
+		wantErrSubstring string
+	}{{
+		name: "no TLVs",
+	}, {
+		name: "length computed from value",
+		in: []*rawTLV{
+			{Type: 1, Length: 42, Value: []byte{0, 10, 20, 30}},
+			{Type: 2, Value: []byte{}},
+		},
+		want: []byte{1, 4, 0, 10, 20, 30, 2, 0},
+	}, {
+		name:             "value too long",
+		in:               []*rawTLV{{Type: 1, Value: make([]byte, 256)}},
+		wantErrSubstring: "value length 256 exceeds 255 bytes",
+	}, {
+		name:             "nil TLV",
+		in:               []*rawTLV{{Type: 1}, nil},
+		wantErrSubstring: "nil TLV at index 1",
+	}}
+
+	for _, tt := range tests {
+		got, err := TLVsToTLVBytes(tt.in)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: TLVsToTLVBytes(%v): did not get expected error, %s", tt.name, tt.in, diff)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: TLVsToTLVBytes(%v): did not get expected bytes, got: %v, want: %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestTLVsToTLVBytesRoundTrip checks that TLVsToTLVBytes is the inverse of
+// TLVBytesToTLVs, over the example LSPs and randomly generated TLVs.
+func TestTLVsToTLVBytesRoundTrip(t *testing.T) {
+	inputs := [][]byte{exampleLSP1[15:], exampleLSP2[15:], exampleLSP3[15:]}
+
+	rnd := rand.New(rand.NewSource(1))
+	for x := 0; x < 100; x++ {
+		var b []byte
+		for n := rnd.Intn(10); n > 0; n-- {
+			v := make([]byte, rnd.Intn(256))
+			rnd.Read(v)
+			b = append(b, uint8(rnd.Intn(256)), uint8(len(v)))
+			b = append(b, v...)
+		}
+		inputs = append(inputs, b)
+	}
+
+	for _, in := range inputs {
+		tlvs, err := TLVBytesToTLVs(in)
+		if err != nil {
+			t.Errorf("TLVBytesToTLVs(%v): got unexpected error: %v", in, err)
+			continue
+		}
+		got, err := TLVsToTLVBytes(tlvs)
+		if err != nil {
+			t.Errorf("TLVsToTLVBytes(TLVBytesToTLVs(%v)): got unexpected error: %v", in, err)
+			continue
+		}
+		if !bytes.Equal(got, in) {
+			t.Errorf("TLVsToTLVBytes(TLVBytesToTLVs(%v)): did not round-trip, got: %v", in, got)
+		}
+	}
+}
+
+func BenchmarkTLVBytesToTLVs(b *testing.B) {
+	// The TLVs of the example LSPs follow the 15-byte fixed header.
+	tlvBytes := exampleLSP3[15:]
+
+	b.Run("TLVBytesToTLVs", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			TLVBytesToTLVs(tlvBytes)
+		}
+	})
+
+	b.Run("ISISBytesToLSP", func(b *testing.B) {
+		b.ReportAllocs()
+		for n := 0; n < b.N; n++ {
+			ISISBytesToLSP(exampleLSP3, 0)
+		}
+	})
+}
+
 func TestProcessDynamicNameTLV(t *testing.T) {
 	tests := []struct {
 		name    string
 		inTLV   *rawTLV
 		inLSP   *isisLSP
+		inOpts  []ParseOption
 		wantLSP *isisLSP
 		wantErr bool
 	}{{
@@ -138,6 +231,47 @@ func TestProcessDynamicNameTLV(t *testing.T) {
 				},
 			},
 		},
+		wantErr: true,
+	}, {
+		name: "identical hostname to existing TLV",
+		inTLV: &rawTLV{
+			Value: []byte("pf01.cbf99"),
+		},
+		inLSP:   hostnameLSP("pf01.cbf99"),
+		wantLSP: hostnameLSP("pf01.cbf99", "pf01.cbf99"),
+	}, {
+		name: "differing hostname, keep first",
+		inTLV: &rawTLV{
+			Value: []byte("bd07.sql88"),
+		},
+		inLSP:   hostnameLSP("pf01.cbf99"),
+		inOpts:  []ParseOption{WithHostnameMode(HostnameFirst)},
+		wantLSP: hostnameLSP("pf01.cbf99"),
+		wantErr: true,
+	}, {
+		name: "differing hostname, keep last",
+		inTLV: &rawTLV{
+			Value: []byte("bd07.sql88"),
+		},
+		inLSP:   hostnameLSP("pf01.cbf99"),
+		inOpts:  []ParseOption{WithHostnameMode(HostnameLast)},
+		wantLSP: hostnameLSP("bd07.sql88"),
+		wantErr: true,
+	}, {
+		name: "identical hostname, keep first",
+		inTLV: &rawTLV{
+			Value: []byte("pf01.cbf99"),
+		},
+		inLSP:   hostnameLSP("pf01.cbf99"),
+		inOpts:  []ParseOption{WithHostnameMode(HostnameFirst)},
+		wantLSP: hostnameLSP("pf01.cbf99"),
+	}, {
+		name: "first hostname, keep last",
+		inTLV: &rawTLV{
+			Value: []byte("pf01.cbf99"),
+		},
+		inOpts:  []ParseOption{WithHostnameMode(HostnameLast)},
+		wantLSP: hostnameLSP("pf01.cbf99"),
 	}}
 
 	for _, tt := range tests {
@@ -146,16 +280,171 @@ func TestProcessDynamicNameTLV(t *testing.T) {
 			got = newISISLSP()
 		}
 
-		err := got.processDynamicNameTLV(tt.inTLV)
+		for _, o := range tt.inOpts {
+			o(&got.opts)
+		}
+
+		// Errors for multiple hostnames are non-fatal, such that the LSP is
+		// checked regardless of whether an error is returned.
+		if err := got.processDynamicNameTLV(tt.inTLV); (err != nil) != tt.wantErr {
+			t.Errorf("%s: i.processDynamicNameTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.inTLV, err, tt.wantErr)
+		}
+		// The options are not part of the expected output.
+		got.opts = parseOptions{}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processDynamicNameTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+// hostnameLSP returns an isisLSP containing a Dynamic Hostname TLV with the
+// hostnames supplied.
+func hostnameLSP(hostnames ...string) *isisLSP {
+	return &isisLSP{
+		LSP: &oc.Lsp{
+			Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME,
+					Hostname: &oc.Lsp_Tlv_Hostname{
+						Hostname: hostnames,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestProcessAuthenticationTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "cleartext password",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 5,
+			Value:  []byte{1, 'p', 'a', 's', 's'},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION,
+						Authentication: &oc.Lsp_Tlv_Authentication{
+							CryptoType: oc.OpenconfigIsis_Authentication_CryptoType_CLEARTEXT,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "HMAC-MD5",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 17,
+			Value:  []byte{54, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION,
+						Authentication: &oc.Lsp_Tlv_Authentication{
+							CryptoType: oc.OpenconfigIsis_Authentication_CryptoType_HMAC_MD5,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "generic cryptographic authentication",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 7,
+			Value:  []byte{3, 0x01, 0x02, 0xDE, 0xAD, 0xBE, 0xEF},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION: {
+						Type:           oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION,
+						Authentication: &oc.Lsp_Tlv_Authentication{},
+					},
+				},
+			},
+		},
+	}, {
+		name: "generic cryptographic authentication without key ID",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 2,
+			Value:  []byte{3, 0x01},
+		},
+		wantErr: true,
+	}, {
+		name: "unknown authentication type",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 3,
+			Value:  []byte{42, 0x01, 0x02},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION: {
+						Type:           oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION,
+						Authentication: &oc.Lsp_Tlv_Authentication{},
+					},
+				},
+			},
+		},
+	}, {
+		name: "cleartext with zero-length password",
+		inTLV: &rawTLV{
+			Type:   10,
+			Length: 1,
+			Value:  []byte{1},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AUTHENTICATION,
+						Authentication: &oc.Lsp_Tlv_Authentication{
+							CryptoType: oc.OpenconfigIsis_Authentication_CryptoType_CLEARTEXT,
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "empty TLV",
+		inTLV: &rawTLV{
+			Type: 10,
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got := newISISLSP()
+		err := got.processAuthenticationTLV(tt.inTLV)
 		if err != nil {
 			if !tt.wantErr {
-				t.Errorf("%s: i.processDynamicNameTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+				t.Errorf("%s: i.processAuthenticationTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
 			}
 			continue
 		}
 
+		if tt.wantErr {
+			t.Errorf("%s: i.processAuthenticationTLV(%v): did not get expected error", tt.name, tt.inTLV)
+			continue
+		}
+
 		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
-			t.Errorf("%s: i.processDynamicNameTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+			t.Errorf("%s: i.processAuthenticationTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
 		}
 	}
 }
@@ -333,9 +622,39 @@ func TestProcessProtocolsSupportedTLV(t *testing.T) {
 	}, {
 		name: "nlpid with unknown value",
 		inTLV: &rawTLV{
-			Value: []byte{0x42},
+			Type:   129,
+			Length: 1,
+			Value:  []byte{0x42},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID: {
+						Type:  oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID,
+						Nlpid: &oc.Lsp_Tlv_Nlpid{},
+					},
+				},
+			},
+		},
+	}, {
+		name: "nlpid with known and unknown values",
+		inTLV: &rawTLV{
+			Type:   129,
+			Length: 3,
+			Value:  []byte{0xCC, 0x81, 0x8E},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID,
+						Nlpid: &oc.Lsp_Tlv_Nlpid{
+							Nlpid: []oc.E_OpenconfigIsis_Nlpid_Nlpid{oc.OpenconfigIsis_Nlpid_Nlpid_IPV4, oc.OpenconfigIsis_Nlpid_Nlpid_IPV6},
+						},
+					},
+				},
+			},
 		},
-		wantErr: true,
 	}}
 
 	for _, tt := range tests {
@@ -628,6 +947,64 @@ func TestProcessCapabilityTLV(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "router capability with SR algorithm and unknown sub-TLVs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// Router ID
+				192, 0, 2, 1,
+				// Flags
+				0x0,
+				// Unknown subTLV
+				200, 2, 0xDE, 0xAD,
+				// SR algorithm subTLV
+				19, 2, 0, 1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY,
+						Capability: map[uint32]*oc.Lsp_Tlv_Capability{
+							0: {
+								InstanceNumber: ygot.Uint32(0),
+								RouterId:       ygot.String("192.0.2.1"),
+								Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Capability_Subtlv{
+									oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_ALGORITHM: {
+										Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_ALGORITHM,
+										SegmentRoutingAlgorithms: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingAlgorithms{
+											Algorithm: []oc.E_OpenconfigIsis_SegmentRoutingAlgorithms_Algorithm{
+												oc.OpenconfigIsis_SegmentRoutingAlgorithms_Algorithm_SPF,
+												oc.OpenconfigIsis_SegmentRoutingAlgorithms_Algorithm_STRICT_SPF,
+											},
+										},
+									},
+								},
+								UndefinedSubtlv: map[uint8]*oc.Lsp_Tlv_Capability_UndefinedSubtlv{
+									200: {
+										Type:   ygot.Uint8(200),
+										Length: ygot.Uint8(2),
+										Value:  oc.Binary{0xDE, 0xAD},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "router capability with repeated unknown sub-TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				192, 0, 2, 1,
+				0x0,
+				200, 1, 0x1,
+				200, 1, 0x2,
+			},
+		},
+		wantErr: true,
 	}, {
 		name: "router capability with SR algorithm with overflow length",
 		inTLV: &rawTLV{
@@ -697,43 +1074,104 @@ func TestProcessCapabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "router capability with SR capability with invalid length",
-		inTLV: &rawTLV{
-			Value: []byte{84, 18, 192, 84, 0x0, 2, 128, 1, 1},
-		},
-		wantErr: true,
-	}, {
-		name: "router capability with SR capability with invalid SID/Label length",
-		inTLV: &rawTLV{
-			Value: []byte{84, 18, 192, 84, 0x0,
-				2, 9,
-				0xC0,
-				0x0, 0x0, 0xFF,
-				1, 42, 1, 2,
-			},
-		},
-		wantErr: true,
-	}, {
-		name: "router capability with SR capability with invalid SID/Label type",
+		name: "router capability with SR capability using index-encoded SRGB base",
 		inTLV: &rawTLV{
 			Value: []byte{
 				// Router Capability TLV header
 				84, 18, 192, 84, 0x0,
-				2, 9,
-				0xC0,
-				// SR Capability sub-TLV
+				// subTLV 2 == SR Capability
+				2, 19,
+				// Flags
+				0x80,
 				// Range
-				0x0, 0x0, 42,
-				// SID/Label SubTLV
-				42, 3, 0x0, 0x0, 42,
+				0x01, 0x00, 0x00,
+				// SID/Label SubTLV with a 4-byte index
+				1, 4, 0x01, 0x02, 0x03, 0x04,
+				// Range
+				0x0, 0x0, 0x10,
+				// SID/Label SubTLV with a 4-byte index
+				1, 4, 0x0, 0x0, 0x0, 0x0,
 			},
 		},
-		wantErr: true,
-	}, {
-		name: "router capability with SR capability with invalid SID/Label length for type",
-		inTLV: &rawTLV{
-			Value: []byte{
-				84, 18, 192, 84, 0x0, 2, 10, 0xC0, 0x0, 0x0, 42,
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY,
+						Capability: map[uint32]*oc.Lsp_Tlv_Capability{
+							0: {
+								InstanceNumber: ygot.Uint32(0),
+								RouterId:       ygot.String("84.18.192.84"),
+								Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Capability_Subtlv{
+									oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_CAPABILITY: {
+										Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_ROUTER_CAPABILITY_SR_CAPABILITY,
+										SegmentRoutingCapability: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability{
+											Flags: []oc.E_OpenconfigIsis_SegmentRoutingCapability_Flags{
+												oc.OpenconfigIsis_SegmentRoutingCapability_Flags_IPV4_MPLS,
+											},
+											SrgbDescriptor: map[uint32]*oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor{
+												0: {Range: ygot.Uint32(65536), Label: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor_Label_Union_Uint32{Uint32: 16909060}},
+												1: {Range: ygot.Uint32(16), Label: &oc.Lsp_Tlv_Capability_Subtlv_SegmentRoutingCapability_SrgbDescriptor_Label_Union_Uint32{Uint32: 0}},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "router capability with SR capability with truncated index-encoded SRGB base",
+		inTLV: &rawTLV{
+			Value: []byte{
+				84, 18, 192, 84, 0x0,
+				2, 8,
+				0x80,
+				0x0, 0x0, 0x10,
+				1, 4, 0x0, 0x0,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "router capability with SR capability with invalid length",
+		inTLV: &rawTLV{
+			Value: []byte{84, 18, 192, 84, 0x0, 2, 128, 1, 1},
+		},
+		wantErr: true,
+	}, {
+		name: "router capability with SR capability with invalid SID/Label length",
+		inTLV: &rawTLV{
+			Value: []byte{84, 18, 192, 84, 0x0,
+				2, 9,
+				0xC0,
+				0x0, 0x0, 0xFF,
+				1, 42, 1, 2,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "router capability with SR capability with invalid SID/Label type",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// Router Capability TLV header
+				84, 18, 192, 84, 0x0,
+				2, 9,
+				0xC0,
+				// SR Capability sub-TLV
+				// Range
+				0x0, 0x0, 42,
+				// SID/Label SubTLV
+				42, 3, 0x0, 0x0, 42,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "router capability with SR capability with invalid SID/Label length for type",
+		inTLV: &rawTLV{
+			Value: []byte{
+				84, 18, 192, 84, 0x0, 2, 10, 0xC0, 0x0, 0x0, 42,
 				1, 4, 0x0, 0x0, 0x4,
 			},
 		},
@@ -1095,168 +1533,257 @@ func TestProcessIPv6ReachabilityTLV(t *testing.T) {
 		},
 		wantErr: true,
 	}, {
-		name: "tlv with prefix SID subtlv, missing value bytes",
+		name: "prefix with administrative tag",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x0, 0x0, 0x0, 0x2A, 0xE0, 0x30, 0x20, 0x01, 0x4c, 0x20, 0x00, 0x42,
-				// Length of sub-TLVs
-				0x4, 0x03, 0x2,
-				// PrefixSID subTLV encoding, label value
-				0xFC,
-				// Algorithm
-				0x00,
-				// Missing bytes
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte, sub-TLVs present
+				0x20,
+				// Prefix length
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0x6,
+				// SubTLV contents
+				0x1, 0x4,
+				0x0, 0x0, 0x0, 0x64,
 			},
 		},
-		wantErr: true,
-	}}
-
-	for _, tt := range tests {
-		got := tt.inLSP
-		if got == nil {
-			got = newISISLSP()
-		}
-
-		err := got.processIPv6ReachabilityTLV(tt.inTLV)
-		if err != nil {
-			if !tt.wantErr {
-				t.Errorf("%s: i.processIPv6ReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
-			}
-			continue
-		}
-
-		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
-			t.Errorf("%s: i.processIPv6ReachabiltyTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
-		}
-	}
-}
-
-func TestIPv4TERouterIDTLV(t *testing.T) {
-	tests := []struct {
-		name    string
-		inTLV   *rawTLV
-		inLSP   *isisLSP
-		wantLSP *isisLSP
-		wantErr bool
-	}{{
-		name: "simple IPv4 TE Router ID",
-		inTLV: &rawTLV{
-			Value: []byte{192, 168, 1, 1},
-		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
-						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
-							RouterId: []string{"192.168.1.1"},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:db8::/32": {
+									Prefix: ygot.String("2001:db8::/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG,
+											Tag: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Tag{
+												Tag32: []uint32{100},
+											},
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
 	}, {
-		name: "short IPv4 TE Router ID TLV",
+		name: "prefix with several administrative tags",
 		inTLV: &rawTLV{
-			Value: []byte{84, 18},
+			Value: []byte{
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte, sub-TLVs present
+				0x20,
+				// Prefix length
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0x10,
+				// SubTLV contents, two tags
+				0x1, 0x8,
+				0x0, 0x0, 0x0, 0x64,
+				0x0, 0x0, 0xFF, 0xFF,
+				// A further sub-TLV with a third tag
+				0x1, 0x4,
+				0xDE, 0xAD, 0xBE, 0xEF,
+			},
 		},
-		wantErr: true,
-	}, {
-		name: "long IPv4 TE Router ID TLV",
-		inTLV: &rawTLV{
-			Value: []byte{84, 18, 192, 72, 84},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:db8::/32": {
+									Prefix: ygot.String("2001:db8::/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG,
+											Tag: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Tag{
+												Tag32: []uint32{100, 65535, 3735928559},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 		},
-		wantErr: true,
 	}, {
-		name: "simple IPv4 TE Router ID",
+		name: "prefix with 64-bit administrative tag",
 		inTLV: &rawTLV{
-			Value: []byte{84, 18, 192, 72},
+			Value: []byte{
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte, sub-TLVs present
+				0x20,
+				// Prefix length
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0xA,
+				// SubTLV contents
+				0x2, 0x8,
+				0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x64,
+			},
 		},
-		inLSP: &isisLSP{
+		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
-						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
-							RouterId: []string{"192.16.1.1"},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:db8::/32": {
+									Prefix: ygot.String("2001:db8::/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64,
+											Tag64: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Tag64{
+												Tag64: []uint64{4294967396},
+											},
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
+	}, {
+		name: "prefix with truncated 64-bit administrative tag",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x2A,
+				0x20,
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0xC,
+				// SubTLV contents, one complete and one truncated tag
+				0x2, 0xA,
+				0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x64,
+				0x0, 0x0,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "prefix with prefix attribute flags",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte, sub-TLVs present
+				0x20,
+				// Prefix length
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0x4,
+				// SubTLV contents, N-flag set followed by a reserved byte
+				0x4, 0x2,
+				0x20, 0x0,
+			},
+		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
-						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
-							RouterId: []string{"192.16.1.1", "84.18.192.72"},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:db8::/32": {
+									Prefix: ygot.String("2001:db8::/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS,
+											Flags: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Flags{
+												Flags: []oc.E_OpenconfigIsis_Flags_Flags{oc.OpenconfigIsis_Flags_Flags_NODE_FLAG},
+											},
+										},
+									},
+								},
+							},
 						},
 					},
 				},
 			},
 		},
-	}}
-
-	for _, tt := range tests {
-		got := tt.inLSP
-		if got == nil {
-			got = newISISLSP()
-		}
-
-		err := got.processTERouterIDTLV(tt.inTLV)
-
-		if err != nil {
-			if !tt.wantErr {
-				t.Errorf("%s: i.processTERouterIDTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
-			}
-			continue
-		}
-
-		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
-			t.Errorf("%s: i.processTERouterIDTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
-		}
-	}
-}
-
-func TestProcessExtendedISReachabilityTLV(t *testing.T) {
-	tests := []struct {
-		name    string
-		inTLV   *rawTLV
-		inLSP   *isisLSP
-		wantLSP *isisLSP
-		wantErr bool
-	}{{
-		name: "short TLV",
-		inTLV: &rawTLV{
-			Value: []byte{0x10, 0x20},
-		},
-		wantErr: true,
 	}, {
-		name: "simple is-reachability tlv with zero subtlvs",
+		name: "prefix with source router IDs",
 		inTLV: &rawTLV{
 			Value: []byte{
-				// System ID
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				// Default metric
-				0, 0, 42,
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte, sub-TLVs present
+				0x20,
+				// Prefix length
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
 				// SubTLV length
-				0,
+				0x18,
+				// IPv4 source router ID sub-TLV
+				0xB, 0x4,
+				192, 0, 2, 1,
+				// IPv6 source router ID sub-TLV
+				0xC, 0x10,
+				0x20, 0x01, 0x0d, 0xb8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
 			},
 		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(42),
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:db8::/32": {
+									Prefix: ygot.String("2001:db8::/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID,
+											Ipv4SourceRouterId: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv4SourceRouterId{
+												RouterId: ygot.String("192.0.2.1"),
+											},
+										},
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID,
+											Ipv6SourceRouterId: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId{
+												RouterId: ygot.String("2001:db8::1"),
+											},
 										},
 									},
 								},
@@ -1267,171 +1794,117 @@ func TestProcessExtendedISReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "append to existing neighbor in TLV",
+		name: "prefix with invalid length source router ID",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				// Default metric
-				0, 0, 42,
+				0x0, 0x0, 0x0, 0x2A,
+				0x20,
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
 				// SubTLV length
-				0x6,
-				// SubTLV type
-				0x3,
-				// SubTLV len
-				0x4,
-				// SubTLV value
-				0x0, 0x2A, 0x2A, 0x0,
-			},
-		},
-		inLSP: &isisLSP{
-			LSP: &oc.Lsp{
-				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(42),
-										},
-									},
-								},
-							},
-						},
-					},
-				},
+				0x7,
+				// IPv6 source router ID sub-TLV carrying an IPv4 address
+				0xC, 0x5,
+				192, 0, 2, 1, 0,
 			},
 		},
-		wantLSP: &isisLSP{
-			LSP: &oc.Lsp{
-				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(42),
-										},
-										1: {
-											Id:     ygot.Uint64(1),
-											Metric: ygot.Uint32(42),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
-													AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
-														AdminGroup: []uint32{2763264},
-													},
-												},
-											},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
+		wantErr: true,
+	}, {
+		name: "tlv with prefix SID subtlv, missing value bytes",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x2A, 0xE0, 0x30, 0x20, 0x01, 0x4c, 0x20, 0x00, 0x42,
+				// Length of sub-TLVs
+				0x4, 0x03, 0x2,
+				// PrefixSID subTLV encoding, label value
+				0xFC,
+				// Algorithm
+				0x00,
+				// Missing bytes
 			},
 		},
+		wantErr: true,
 	}, {
-		name: "is-reachability tlv with administrative group subtlv",
+		name: "tlv with no subtlvs - expanded IPv6 prefixes",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0x2A,
-				// SubTLV length
-				0x6,
-				// SubTLV type
-				0x3,
-				// SubTLV len
-				0x4,
-				// SubTLV value
-				0x0, 0x2A, 0x2A, 0x0,
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control Byte
+				0x00,
+				// Prefix length
+				0x20,
+				// Octets of prefix
+				0x20, 0x01, 0x0d, 0xb8,
+			},
+		},
+		inLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{},
 			},
+			opts: parseOptions{expandIPv6: true},
 		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(42),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
-													AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
-														AdminGroup: []uint32{2763264},
-													},
-												},
-											},
-										},
-									},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:0db8:0000:0000:0000:0000:0000:0000/32": {
+									Prefix: ygot.String("2001:0db8:0000:0000:0000:0000:0000:0000/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(false),
+									Metric: ygot.Uint32(42),
 								},
 							},
 						},
 					},
 				},
 			},
+			opts: parseOptions{expandIPv6: true},
 		},
 	}, {
-		name: "is-reachability TLV with incorrect length admin group",
+		name: "prefix with source router ID - expanded IPv6 addresses",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0x2A,
-				// Length of SubTLVs, SubTLV type and length
-				0x6, 0x3, 0x3,
-				// Value (should be 4b)
-				0x2A, 0x2A,
+				0x0, 0x0, 0x0, 0x2A,
+				0x20,
+				0x20,
+				0x20, 0x01, 0x0d, 0xb8,
+				// SubTLV length
+				0x12,
+				// IPv6 source router ID sub-TLV
+				0xC, 0x10,
+				0x20, 0x01, 0x0d, 0xb8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
 			},
 		},
-		wantErr: true,
-	}, {
-		name: "is-reachability TLV with IPv4 Interface Address subTLV",
-		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				// Length of SubTLVs
-				0x6,
-				// SubTLV type and length
-				0x6, 0x4,
-				// Value
-				192, 168, 1, 1,
+		inLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{},
 			},
+			opts: parseOptions{expandIPv6: true},
 		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(255),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS,
-													Ipv4InterfaceAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4InterfaceAddress{
-														Address: []string{"192.168.1.1"},
-													},
-												},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY,
+						Ipv6Reachability: &oc.Lsp_Tlv_Ipv6Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_Ipv6Reachability_Prefix{
+								"2001:0db8:0000:0000:0000:0000:0000:0000/32": {
+									Prefix: ygot.String("2001:0db8:0000:0000:0000:0000:0000:0000/32"),
+									UpDown: ygot.Bool(false),
+									XBit:   ygot.Bool(false),
+									SBit:   ygot.Bool(true),
+									Metric: ygot.Uint32(42),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID,
+											Ipv6SourceRouterId: &oc.Lsp_Tlv_Ipv6Reachability_Prefix_Subtlv_Ipv6SourceRouterId{
+												RouterId: ygot.String("2001:0db8:0000:0000:0000:0000:0000:0001"),
 											},
 										},
 									},
@@ -1441,222 +1914,532 @@ func TestProcessExtendedISReachabilityTLV(t *testing.T) {
 					},
 				},
 			},
+			opts: parseOptions{expandIPv6: true},
 		},
-	}, {
-		name: "is-reachability TLV with invalid length IPv4 Interface address",
-		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				// Length of subTLVs
-				0x5,
-				// SubTLV type and length
-				0x6, 0x3,
-				// Value,
-				10, 0, 1,
-			},
+	}}
+
+	for _, tt := range tests {
+		got := tt.inLSP
+		if got == nil {
+			got = newISISLSP()
+		}
+
+		err := got.processIPv6ReachabilityTLV(tt.inTLV)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processIPv6ReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processIPv6ReachabiltyTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessIPv6ReachabilityTLVDuplicatePrefix(t *testing.T) {
+	r := &rawTLV{
+		Value: []byte{
+			0x0, 0x0, 0x0, 0x0A,
+			0x0,
+			0x20,
+			0x20, 0x01, 0x0d, 0xb8,
+			// Duplicate of 2001:db8::/32, with a metric of 20 and a
+			// prefix SID sub-TLV.
+			0x0, 0x0, 0x0, 0x14,
+			0x20,
+			0x20,
+			0x20, 0x01, 0x0d, 0xb8,
+			0x8,
+			0x3, 0x6, 0x40, 0x0, 0x0, 0x0, 0x0, 0x2A,
+			// Distinct prefix following the duplicate.
+			0x0, 0x0, 0x0, 0x1E,
+			0x0,
+			0x20,
+			0x20, 0x01, 0x0d, 0xb9,
 		},
-		wantErr: true,
-	}, {
-		name: "is-reachability TLV with IPv4 Neighbor Address subTLV",
+	}
+
+	i := newISISLSP()
+	err := i.processIPv6ReachabilityTLV(r)
+	if diff := errdiff.Substring(err, "duplicate prefix 2001:db8::/32 in IPv6 Reachability TLV, retaining first instance"); diff != "" {
+		t.Errorf("i.processIPv6ReachabilityTLV(%v): did not get expected error, %s", r, diff)
+	}
+
+	pfxs := i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY).GetIpv6Reachability().Prefix
+	if p := pfxs["2001:db8::/32"]; p == nil || p.Metric == nil || *p.Metric != 10 || len(p.Subtlv) != 0 {
+		t.Errorf("i.processIPv6ReachabilityTLV(%v): did not retain first instance of duplicate prefix, got: %v", r, p)
+	}
+	if p := pfxs["2001:db9::/32"]; p == nil || p.Metric == nil || *p.Metric != 30 {
+		t.Errorf("i.processIPv6ReachabilityTLV(%v): did not parse prefix following duplicate, got: %v", r, p)
+	}
+}
+
+func TestIPv4TERouterIDTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		inLSP   *isisLSP
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "simple IPv4 TE Router ID",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				// Length of SubTLVs
-				0x6,
-				// SubTLV type and length
-				0x8, 0x4,
-				// Value
-				192, 0, 2, 1,
-			},
+			Value: []byte{192, 168, 1, 1},
 		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(255),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS,
-													Ipv4NeighborAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4NeighborAddress{
-														Address: []string{"192.0.2.1"},
-													},
-												},
-											},
-										},
-									},
-								},
-							},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
+						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
+							RouterId: []string{"192.168.1.1"},
 						},
 					},
 				},
 			},
 		},
 	}, {
-		name: "is-reachability TLV with IPv4 Neighbor Address with invalid length",
+		name: "short IPv4 TE Router ID TLV",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				0x7,
-				0x8, 0x5,
-				192, 168, 1, 2, 1,
-			},
+			Value: []byte{84, 18},
 		},
 		wantErr: true,
 	}, {
-		name: "is-reachability TLV with maximum link bandwidth sub-TLV",
+		name: "long IPv4 TE Router ID TLV",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				// Length of SubTLVs
-				0x6,
-				// SubTLV type and length
-				0x9, 0x4,
-				// 728.25 as a float32
-				0x44, 0x36, 0x10, 0x00,
-			},
+			Value: []byte{84, 18, 192, 72, 84},
 		},
-		wantLSP: &isisLSP{
+		wantErr: true,
+	}, {
+		name: "simple IPv4 TE Router ID",
+		inTLV: &rawTLV{
+			Value: []byte{84, 18, 192, 72},
+		},
+		inLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(255),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH,
-													MaxLinkBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MaxLinkBandwidth{
-														Bandwidth: float32ByteSlice(728.25),
-													},
-												},
-											},
-										},
-									},
-								},
-							},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
+						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
+							RouterId: []string{"192.16.1.1"},
 						},
 					},
 				},
 			},
 		},
-	}, {
-		name: "is-reachability TLV with maximum link bandwidth sub-TLV with invalid length",
-		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				0x7,
-				0x9, 0x5,
-				0x44, 0x36, 0x10, 0x00, 0x10,
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID,
+						Ipv4TeRouterId: &oc.Lsp_Tlv_Ipv4TeRouterId{
+							RouterId: []string{"192.16.1.1", "84.18.192.72"},
+						},
+					},
+				},
 			},
 		},
-		wantErr: true,
-	}, {
-		name: "is-reachability TLV with maximum reservable bandwidth sub-TLV",
+	}}
+
+	for _, tt := range tests {
+		got := tt.inLSP
+		if got == nil {
+			got = newISISLSP()
+		}
+
+		err := got.processTERouterIDTLV(tt.inTLV)
+
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processTERouterIDTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processTERouterIDTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessIPv6TERouterIDTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		inLSP   *isisLSP
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "IPv6 TE Router ID",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0xFF, 0xFF,
-				// Length of SubTLVs
-				06,
-				// SubTLV type and length
-				0xA, 0x4,
-				// Value (728.25) as float 32
-				0x44, 0x36, 0x10, 0x00,
-			},
+			Value: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x1},
 		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
-									SystemId: ygot.String("4900.0000.0000.01"),
-									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
-										0: {
-											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(65535),
-											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH,
-													MaxReservableLinkBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MaxReservableLinkBandwidth{
-														Bandwidth: float32ByteSlice(728.25),
-													},
-												},
-											},
-										},
-									},
-								},
-							},
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_TE_ROUTER_ID,
+						Ipv6TeRouterId: &oc.Lsp_Tlv_Ipv6TeRouterId{
+							RouterId: []string{"2001:db8::1"},
 						},
 					},
 				},
 			},
 		},
 	}, {
-		name: "is-reachability TLV with maximum reservable link bandwidth sub-TLV with invalid length",
+		name: "short IPv6 TE Router ID TLV",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				0x7,
-				0x10, 0x3,
-				0x44, 0x36, 0x10,
-			},
+			Value: []byte{84, 18, 192, 72},
 		},
 		wantErr: true,
 	}, {
-		name: "is-reachability TLV with residual bandwidth sub-TLV",
+		name: "long IPv6 TE Router ID TLV",
 		inTLV: &rawTLV{
-			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0xFF, 0xFF,
-				// Length of SubTLVs
-				06,
-				// SubTLV type and length
-				0x26, 0x4,
-				// Value (728.25) as float 32
-				0x44, 0x36, 0x10, 0x00,
-			},
+			Value: []byte{0x20, 0x01, 0x0d, 0xb8, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x1, 0x2},
 		},
-		wantLSP: &isisLSP{
-			LSP: &oc.Lsp{
-				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
-					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
-						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
-						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
-							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
-								"4900.0000.0000.01": {
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got := tt.inLSP
+		if got == nil {
+			got = newISISLSP()
+		}
+
+		err := got.processIPv6TERouterIDTLV(tt.inTLV)
+
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processIPv6TERouterIDTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if tt.wantErr {
+			t.Errorf("%s: i.processIPv6TERouterIDTLV(%v): did not get expected error", tt.name, tt.inTLV)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processIPv6TERouterIDTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessISReachabilityTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "two neighbors",
+		inTLV: &rawTLV{
+			Type: 2,
+			Value: []byte{
+				// Virtual flag
+				0x00,
+				// Default metric - internal, 10
+				0x0A,
+				// Delay, expense and error metrics - unsupported
+				0x80, 0x80, 0x80,
+				// Neighbor ID
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00,
+				// Default metric - external, 63
+				0x7F,
+				// Delay metric - internal, 1
+				0x01,
+				// Expense metric - external, 2
+				0x42,
+				// Error metric - unsupported, external
+				0xC0,
+				// Neighbor ID - pseudonode
+				0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x03,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS,
+						IsReachability: &oc.Lsp_Tlv_IsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_IsReachability_Neighbor{
+								"1920.0000.2001.00": {
+									SystemId: ygot.String("1920.0000.2001.00"),
+									DefaultMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_DefaultMetric{
+										Flags:  oc.OpenconfigIsis_DefaultMetric_Flags_INTERNAL,
+										Metric: ygot.Uint8(10),
+									},
+									DelayMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_DelayMetric{
+										Flags:  []oc.E_OpenconfigIsis_IsisMetricFlags{oc.OpenconfigIsis_IsisMetricFlags_UNSUPPORTED, oc.OpenconfigIsis_IsisMetricFlags_INTERNAL},
+										Metric: ygot.Uint8(0),
+									},
+									ExpenseMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_ExpenseMetric{
+										Flags:  []oc.E_OpenconfigIsis_IsisMetricFlags{oc.OpenconfigIsis_IsisMetricFlags_UNSUPPORTED, oc.OpenconfigIsis_IsisMetricFlags_INTERNAL},
+										Metric: ygot.Uint8(0),
+									},
+									ErrorMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_ErrorMetric{
+										Flags:  []oc.E_OpenconfigIsis_IsisMetricFlags{oc.OpenconfigIsis_IsisMetricFlags_UNSUPPORTED, oc.OpenconfigIsis_IsisMetricFlags_INTERNAL},
+										Metric: ygot.Uint8(0),
+									},
+								},
+								"1920.0000.2002.03": {
+									SystemId: ygot.String("1920.0000.2002.03"),
+									DefaultMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_DefaultMetric{
+										Metric: ygot.Uint8(63),
+									},
+									DelayMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_DelayMetric{
+										Flags:  []oc.E_OpenconfigIsis_IsisMetricFlags{oc.OpenconfigIsis_IsisMetricFlags_INTERNAL},
+										Metric: ygot.Uint8(1),
+									},
+									ExpenseMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_ExpenseMetric{
+										Metric: ygot.Uint8(2),
+									},
+									ErrorMetric: &oc.Lsp_Tlv_IsReachability_Neighbor_ErrorMetric{
+										Flags:  []oc.E_OpenconfigIsis_IsisMetricFlags{oc.OpenconfigIsis_IsisMetricFlags_UNSUPPORTED},
+										Metric: ygot.Uint8(0),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "virtual flag only",
+		inTLV: &rawTLV{
+			Type:  2,
+			Value: []byte{0x00},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS: {
+						Type:           oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IIS_NEIGHBORS,
+						IsReachability: &oc.Lsp_Tlv_IsReachability{},
+					},
+				},
+			},
+		},
+	}, {
+		name: "truncated neighbor",
+		inTLV: &rawTLV{
+			Type:  2,
+			Value: []byte{0x00, 0x0A, 0x80, 0x80, 0x80, 0x19, 0x20},
+		},
+		wantErr: true,
+	}, {
+		name: "empty TLV",
+		inTLV: &rawTLV{
+			Type: 2,
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got := newISISLSP()
+		if err := got.processISReachabilityTLV(tt.inTLV); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processISReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if tt.wantErr {
+			t.Errorf("%s: i.processISReachabilityTLV(%v): did not get expected error", tt.name, tt.inTLV)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processISReachabilityTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessExtendedISReachabilityTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		inLSP   *isisLSP
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "short TLV",
+		inTLV: &rawTLV{
+			Value: []byte{0x10, 0x20},
+		},
+		wantErr: true,
+	}, {
+		name: "simple is-reachability tlv with zero subtlvs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// System ID
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				// Default metric
+				0, 0, 42,
+				// SubTLV length
+				0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
 									SystemId: ygot.String("4900.0000.0000.01"),
 									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
 										0: {
 											Id:     ygot.Uint64(0),
-											Metric: ygot.Uint32(65535),
+											Metric: ygot.Uint32(42),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "unknown subtlv between known subtlvs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0x2A,
+				// SubTLV length
+				0x14,
+				// Administrative group subTLV
+				0x3, 0x4, 0x0, 0x2A, 0x2A, 0x0,
+				// Unknown subTLV
+				0xC8, 0x2, 0xDE, 0xAD,
+				// Link local/remote identifiers subTLV
+				0x4, 0x8, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x2,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(42),
 											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
-												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH: {
-													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH,
-													ResidualBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_ResidualBandwidth{
-														Bandwidth: float32ByteSlice(728.25),
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
+													AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
+														AdminGroup: []uint32{2763264},
+													},
+												},
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_ID: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_ID,
+													LinkId: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkId{
+														Local:  ygot.Uint32(1),
+														Remote: ygot.Uint32(2),
+													},
+												},
+											},
+											UndefinedSubtlv: map[uint8]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_UndefinedSubtlv{
+												200: {
+													Type:   ygot.Uint8(200),
+													Length: ygot.Uint8(2),
+													Value:  oc.Binary{0xDE, 0xAD},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "repeated unknown subtlv",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0x2A,
+				// SubTLV length
+				0x6,
+				0xC8, 0x1, 0x1,
+				0xC8, 0x1, 0x2,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "append to existing neighbor in TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				// Default metric
+				0, 0, 42,
+				// SubTLV length
+				0x6,
+				// SubTLV type
+				0x3,
+				// SubTLV len
+				0x4,
+				// SubTLV value
+				0x0, 0x2A, 0x2A, 0x0,
+			},
+		},
+		inLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(42),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(42),
+										},
+										1: {
+											Id:     ygot.Uint64(1),
+											Metric: ygot.Uint32(42),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
+													AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
+														AdminGroup: []uint32{2763264},
 													},
 												},
 											},
@@ -1670,308 +2453,1955 @@ func TestProcessExtendedISReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "is-reachability TLV with residual bandwidth sub-TLV with invalid length",
+		name: "is-reachability tlv with administrative group subtlv",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0x2A,
+				// SubTLV length
+				0x6,
+				// SubTLV type
+				0x3,
+				// SubTLV len
+				0x4,
+				// SubTLV value
+				0x0, 0x2A, 0x2A, 0x0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(42),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP,
+													AdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdminGroup{
+														AdminGroup: []uint32{2763264},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with incorrect length admin group",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0x2A,
+				// Length of SubTLVs, SubTLV type and length
+				0x6, 0x3, 0x3,
+				// Value (should be 4b)
+				0x2A, 0x2A,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with IPv4 Interface Address subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x6, 0x4,
+				// Value
+				192, 168, 1, 1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS,
+													Ipv4InterfaceAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4InterfaceAddress{
+														Address: []string{"192.168.1.1"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length IPv4 Interface address",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x5,
+				// SubTLV type and length
+				0x6, 0x3,
+				// Value,
+				10, 0, 1,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with IPv6 Interface Address subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x12,
+				// SubTLV type and length
+				0xC, 0x10,
+				// Value
+				0x20, 0x01, 0x0D, 0xB8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS,
+													Ipv6InterfaceAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6InterfaceAddress{
+														Address: []string{"2001:db8::1"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with two IPv6 Interface Address subTLVs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x24,
+				// SubTLV type and length
+				0xC, 0x10,
+				// Value
+				0x20, 0x01, 0x0D, 0xB8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				// SubTLV type and length
+				0xC, 0x10,
+				// Value
+				0xFE, 0x80, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_INTERFACE_ADDRESS,
+													Ipv6InterfaceAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6InterfaceAddress{
+														Address: []string{"2001:db8::1", "fe80::2"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with IPv6 neighbor address - expanded IPv6 addresses",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x12,
+				// SubTLV type and length
+				0xD, 0x10,
+				// Value
+				0x20, 0x01, 0x0D, 0xB8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x2,
+			},
+		},
+		inLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{},
+			},
+			opts: parseOptions{expandIPv6: true},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS,
+													Ipv6NeighborAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6NeighborAddress{
+														Address: []string{"2001:0db8:0000:0000:0000:0000:0000:0002"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			opts: parseOptions{expandIPv6: true},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length IPv6 Interface address",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x6,
+				// SubTLV type and length
+				0xC, 0x4,
+				// Value
+				192, 168, 1, 1,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with IPv6 Neighbor Address subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x12,
+				// SubTLV type and length
+				0xD, 0x10,
+				// Value - 2001:0db8:0000:0000:0001:0000:0000:00ab
+				0x20, 0x01, 0x0D, 0xB8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x1, 0x0, 0x0, 0x0, 0x0, 0x0, 0xAB,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV6_NEIGHBOR_ADDRESS,
+													Ipv6NeighborAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv6NeighborAddress{
+														Address: []string{"2001:db8::1:0:0:ab"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length IPv6 Neighbor address",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x13,
+				// SubTLV type and length
+				0xD, 0x11,
+				// Value
+				0x20, 0x01, 0x0D, 0xB8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with unidirectional link delay subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x21, 0x4,
+				// Flags and delay
+				0x0, 0x0, 0x3, 0xE8,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY,
+													LinkDelay: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkDelay{
+														ABit:  ygot.Bool(false),
+														Delay: ygot.Uint32(1000),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with anomalous unidirectional link delay subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x21, 0x4,
+				// Flags and delay
+				0x80, 0x1, 0x0, 0x0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY,
+													LinkDelay: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkDelay{
+														ABit:  ygot.Bool(true),
+														Delay: ygot.Uint32(65536),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length unidirectional link delay",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x5,
+				// SubTLV type and length
+				0x21, 0x3,
+				// Value
+				0x0, 0x3, 0xE8,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with min/max unidirectional link delay subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x9,
+				// SubTLV type and length
+				0x22, 0x7,
+				// Flags, min delay and max delay
+				0x0, 0x0, 0x1, 0xF4, 0x0, 0x3, 0xE8,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY,
+													MinMaxLinkDelay: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MinMaxLinkDelay{
+														ABit:     ygot.Bool(false),
+														MinDelay: ygot.Uint32(500),
+														MaxDelay: ygot.Uint32(1000),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with anomalous min/max unidirectional link delay subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x9,
+				// SubTLV type and length
+				0x22, 0x7,
+				// Flags, min delay and max delay
+				0x80, 0x0, 0x0, 0x64, 0x1, 0x0, 0x0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MIN_MAX_LINK_DELAY,
+													MinMaxLinkDelay: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MinMaxLinkDelay{
+														ABit:     ygot.Bool(true),
+														MinDelay: ygot.Uint32(100),
+														MaxDelay: ygot.Uint32(65536),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length min/max unidirectional link delay",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x8,
+				// SubTLV type and length
+				0x22, 0x6,
+				// Value
+				0x0, 0x1, 0xF4, 0x0, 0x3, 0xE8,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with unidirectional delay variation subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x23, 0x4,
+				// Reserved (ignored) and variation
+				0xFF, 0x1, 0x2, 0x3,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY_VARIATION: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_DELAY_VARIATION,
+													LinkDelayVariation: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkDelayVariation{
+														// 0x010203 decoded big-endian.
+														Delay: ygot.Uint32(66051),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length unidirectional delay variation",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x7,
+				// SubTLV type and length
+				0x23, 0x5,
+				// Value
+				0x0, 0x1, 0x2, 0x3, 0x4,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with unidirectional link loss subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x24, 0x4,
+				// Flags and loss
+				0x0, 0x0, 0x82, 0x35,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS,
+													LinkLoss: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkLoss{
+														ABit: ygot.Bool(false),
+														// 33333 * 0.000003% is approximately 0.1% loss.
+														LinkLoss: ygot.Uint32(33333),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with anomalous unidirectional link loss subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x24, 0x4,
+				// Flags and loss
+				0x80, 0xFF, 0xFF, 0xFE,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_LOSS,
+													LinkLoss: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkLoss{
+														ABit: ygot.Bool(true),
+														// The maximum loss value, 0xFFFFFE, is approximately 50.3% loss.
+														LinkLoss: ygot.Uint32(16777214),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length unidirectional link loss",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x4,
+				// SubTLV type and length
+				0x24, 0x2,
+				// Value
+				0x0, 0x1,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with single word extended admin group subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0xE, 0x4,
+				// Admin groups 0 and 31
+				0x80, 0x0, 0x0, 0x1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP,
+													ExtendedAdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_ExtendedAdminGroup{
+														ExtendedAdminGroup: []uint32{0x80000001},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with three word extended admin group subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0xE,
+				// SubTLV type and length
+				0xE, 0xC,
+				// Admin group 0
+				0x80, 0x0, 0x0, 0x0,
+				// No admin groups in the range 32-63
+				0x0, 0x0, 0x0, 0x0,
+				// Admin group 95
+				0x0, 0x0, 0x0, 0x1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_EXTENDED_ADMIN_GROUP,
+													ExtendedAdminGroup: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_ExtendedAdminGroup{
+														ExtendedAdminGroup: []uint32{0x80000000, 0x0, 0x1},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length extended admin group",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x8,
+				// SubTLV type and length
+				0xE, 0x6,
+				// Value
+				0x80, 0x0, 0x0, 0x1, 0x0, 0x0,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with repeated extended admin group",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0xC,
+				// SubTLV type and length
+				0xE, 0x4,
+				// Value
+				0x80, 0x0, 0x0, 0x1,
+				// SubTLV type and length
+				0xE, 0x4,
+				// Value
+				0x0, 0x0, 0x0, 0x2,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with link protection type subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x4,
+				// SubTLV type and length
+				0x14, 0x2,
+				// Protection capabilities (dedicated 1:1) and reserved byte
+				0x08, 0x0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE,
+													LinkProtectionType: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkProtectionType{
+														Type: []oc.E_OpenconfigIsis_LinkProtectionType_Type{
+															oc.OpenconfigIsis_LinkProtectionType_Type_ONE_ONE,
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with multiple link protection types",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x4,
+				// SubTLV type and length
+				0x14, 0x2,
+				// Protection capabilities and reserved byte
+				0x25, 0x0,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_PROTECTION_TYPE,
+													LinkProtectionType: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LinkProtectionType{
+														Type: []oc.E_OpenconfigIsis_LinkProtectionType_Type{
+															oc.OpenconfigIsis_LinkProtectionType_Type_ENHANCED,
+															oc.OpenconfigIsis_LinkProtectionType_Type_SHARED,
+															oc.OpenconfigIsis_LinkProtectionType_Type_EXTRA_TRAFFIC,
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length link protection type",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of subTLVs
+				0x3,
+				// SubTLV type and length
+				0x14, 0x1,
+				// Value
+				0x10,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with TE default metric subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				// IGP metric
+				0x0, 0x0, 0xA,
+				// Length of SubTLVs
+				0x5,
+				// SubTLV type and length
+				0x12, 0x3,
+				// TE metric
+				0x1, 0x0, 0x2,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(10),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_TE_DEFAULT_METRIC: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_TE_DEFAULT_METRIC,
+													TeDefaultMetric: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_TeDefaultMetric{
+														Metric: ygot.Uint32(65538),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with invalid length TE default metric",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xA,
+				// Length of subTLVs
+				0x6,
+				// SubTLV type and length
+				0x12, 0x4,
+				// Value
+				0x0, 0x0, 0x0, 0x64,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with IPv4 Neighbor Address subTLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x8, 0x4,
+				// Value
+				192, 0, 2, 1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS,
+													Ipv4NeighborAddress: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_Ipv4NeighborAddress{
+														Address: []string{"192.0.2.1"},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with IPv4 Neighbor Address with invalid length",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				0x7,
+				0x8, 0x5,
+				192, 168, 1, 2, 1,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with maximum link bandwidth sub-TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				// Length of SubTLVs
+				0x6,
+				// SubTLV type and length
+				0x9, 0x4,
+				// 728.25 as a float32
+				0x44, 0x36, 0x10, 0x00,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(255),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH,
+													MaxLinkBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MaxLinkBandwidth{
+														Bandwidth: float32ByteSlice(728.25),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with maximum link bandwidth sub-TLV with invalid length",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				0x7,
+				0x9, 0x5,
+				0x44, 0x36, 0x10, 0x00, 0x10,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with maximum reservable bandwidth sub-TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0xFF, 0xFF,
+				// Length of SubTLVs
+				06,
+				// SubTLV type and length
+				0xA, 0x4,
+				// Value (728.25) as float 32
+				0x44, 0x36, 0x10, 0x00,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(65535),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH,
+													MaxReservableLinkBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_MaxReservableLinkBandwidth{
+														Bandwidth: float32ByteSlice(728.25),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with maximum reservable link bandwidth sub-TLV with invalid length",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				0x7,
+				0x10, 0x3,
+				0x44, 0x36, 0x10,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "is-reachability TLV with residual bandwidth sub-TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0xFF, 0xFF,
+				// Length of SubTLVs
+				06,
+				// SubTLV type and length
+				0x26, 0x4,
+				// Value (728.25) as float 32
+				0x44, 0x36, 0x10, 0x00,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY,
+						ExtendedIsReachability: &oc.Lsp_Tlv_ExtendedIsReachability{
+							Neighbor: map[string]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor{
+								"4900.0000.0000.01": {
+									SystemId: ygot.String("4900.0000.0000.01"),
+									Instance: map[uint64]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance{
+										0: {
+											Id:     ygot.Uint64(0),
+											Metric: ygot.Uint32(65535),
+											Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv{
+												oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH: {
+													Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH,
+													ResidualBandwidth: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_ResidualBandwidth{
+														Bandwidth: float32ByteSlice(728.25),
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "is-reachability TLV with residual bandwidth sub-TLV with invalid length",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				0x0, 0x0, 0xFF,
+				0x9,
+				0x10, 0x8,
+				0x44, 0x36, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "short TLV after valid TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x42, 0x0,
+				0x42,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "Unreserved bandwidth - valid values",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{34},
+				// SubTLV type and length
+				[]byte{0xB, 0x20},
+				// Values per priority level
+				float32ByteSlice(0.0),
+				float32ByteSlice(1.0),
+				float32ByteSlice(2.0),
+				float32ByteSlice(3.0),
+				float32ByteSlice(4.0),
+				float32ByteSlice(5.0),
+				float32ByteSlice(6.0),
+				float32ByteSlice(7.0),
+			),
+		},
+		wantLSP: &isisLSP{
+			LSP: func() *oc.Lsp {
+				l := &oc.Lsp{}
+				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
+				n := neigh.GetOrCreateInstance(0)
+				n.Metric = ygot.Uint32(65535)
+				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_UNRESERVED_BANDWIDTH)
+				for i := range []uint8{0, 1, 2, 3, 4, 5, 6, 7} {
+					b := s.GetOrCreateSetupPriority(uint8(i))
+					b.Bandwidth = float32ByteSlice(float32(i))
+				}
+				return l
+			}(),
+		},
+	}, {
+		name: "Unreserved bandwidth - invalid length",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{34},
+				// SubTLV type and length
+				[]byte{0x0, 0x0, 0x0},
+			),
+		},
+		wantErr: true,
+	}, {
+		name: "link local and remote ID - invalid length",
+		inTLV: &rawTLV{
+			Length: 3,
+		},
+		wantErr: true,
+	}, {
+		name: "link local and remote ID",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{10},
+				// SubTLV type and length
+				[]byte{4, 8},
+				// Local ID
+				[]byte{0x1, 0x1, 0x1, 0x1},
+				// Remote ID
+				[]byte{0x2, 0x2, 0x2, 0x2},
+			),
+		},
+		wantLSP: &isisLSP{
+			LSP: func() *oc.Lsp {
+				l := &oc.Lsp{}
+				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
+				n := neigh.GetOrCreateInstance(0)
+				n.Metric = ygot.Uint32(65535)
+				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_ID).GetOrCreateLinkId()
+				s.Local = ygot.Uint32(16843009)
+				s.Remote = ygot.Uint32(33686018)
+				return l
+			}(),
+		},
+	}, {
+		name: "link local and remote SID with invalid length",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{10},
+				// SubTLV type and length
+				[]byte{4, 7},
+				// Local ID
+				[]byte{0x1, 0x1, 0x1, 0x1},
+				// Remote ID
+				[]byte{0x2, 0x2, 0x2},
+			),
+		},
+		wantErr: true,
+	}, {
+		name: "adjacency SID - valid value",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{7},
+				// Type + Length
+				[]byte{31, 5},
+				// Flags and Value
+				[]byte{0x30, 0xFF, 0x00, 0x00, 0x2A},
+			),
+		},
+		wantLSP: &isisLSP{
+			LSP: func() *oc.Lsp {
+				l := &oc.Lsp{}
+				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
+				n := neigh.GetOrCreateInstance(0)
+				n.Metric = ygot.Uint32(65535)
+				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
+				if err := s.AppendAdjacencySid(&oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdjacencySid{
+					Value:  ygot.Uint32(42),
+					Weight: ygot.Uint8(255),
+					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+					},
+				}); err != nil {
+					panic(err)
+				}
+				return l
+			}(),
+		},
+	}, {
+		name: "multiple adjacency SIDs",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{14},
+				// Type and Length
+				[]byte{31, 5},
+				// Flags, Weight, and Value
+				[]byte{0x30, 0xFF, 0x0, 0x0, 0x2A},
+				// Type and Length
+				[]byte{31, 5},
+				// Flags, Weight, and Value
+				[]byte{0x30, 0xFF, 0xFF, 0xFF, 0xFF},
+			),
+		},
+		wantLSP: &isisLSP{
+			LSP: func() *oc.Lsp {
+				l := &oc.Lsp{}
+				n := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01").GetOrCreateInstance(0)
+				n.Metric = ygot.Uint32(65535)
+				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
+
+				sids := []*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdjacencySid{{
+					Value:  ygot.Uint32(42),
+					Weight: ygot.Uint8(255),
+					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+					},
+				}, {
+					Value:  ygot.Uint32(16777215),
+					Weight: ygot.Uint8(255),
+					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+					},
+				}}
+
+				for _, as := range sids {
+					if err := s.AppendAdjacencySid(as); err != nil {
+						panic(err)
+					}
+				}
+
+				return l
+			}(),
+		},
+	}, {
+		name: "multiple LAN adjacency SIDs",
+		inTLV: &rawTLV{
+			Value: appendByteSlice(
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
+				[]byte{0x0, 0xFF, 0xFF},
+				// Length of SubTLVs
+				[]byte{26},
+				// Type and Length
+				[]byte{32, 11},
+				// Flags, Weight
+				[]byte{0x30, 0x00},
+				// SystemID
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x2},
+				// Value
+				[]byte{0x0, 0x0, 0x1},
+				// Type and Length
+				[]byte{32, 11},
+				// Flags, Weight
+				[]byte{0x30, 0x0},
+				// System ID
+				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x03},
+				// Value
+				[]byte{0x0, 0x0, 0x2},
+			),
+		},
+		wantLSP: &isisLSP{
+			LSP: func() *oc.Lsp {
+				l := &oc.Lsp{}
+				n := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01").GetOrCreateInstance(0)
+				n.Metric = ygot.Uint32(65535)
+				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_LAN_SID)
+
+				sids := []*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LanAdjacencySid{{
+					Value:      ygot.Uint32(1),
+					Weight:     ygot.Uint8(0),
+					NeighborId: ygot.String("4900.0000.0002"),
+					Flags: []oc.E_OpenconfigIsis_LanAdjacencySid_Flags{
+						oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE,
+						oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL,
+					},
+				}, {
+					Value:      ygot.Uint32(2),
+					Weight:     ygot.Uint8(0),
+					NeighborId: ygot.String("4900.0000.0003"),
+					Flags: []oc.E_OpenconfigIsis_LanAdjacencySid_Flags{
+						oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE,
+						oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL,
+					},
+				}}
+
+				for _, as := range sids {
+					if err := s.AppendLanAdjacencySid(as); err != nil {
+						panic(err)
+					}
+				}
+
+				return l
+			}(),
+		},
+	}}
+
+	for _, tt := range tests {
+		got := tt.inLSP
+		if got == nil {
+			got = newISISLSP()
+		}
+
+		err := got.processExtendedISReachabilityTLV(tt.inTLV)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processExtendedISReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		// The instance ID counters are internal state of the parser, and
+		// are covered by TestProcessExtendedISReachabilityTLVRepeatedNeighbor.
+		got.isReachInstances = nil
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processExtendedISReachabilityTLV(%v): did not get expected LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessExtendedISReachabilityTLVRepeatedNeighbor(t *testing.T) {
+	nbrTLV := func(metric uint8) *rawTLV {
+		return &rawTLV{
+			Value: []byte{
+				// System ID
+				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+				// Default metric
+				0, 0, metric,
+				// SubTLV length
+				0,
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		inLSP         *isisLSP
+		inTLVs        []*rawTLV
+		wantInstances map[uint64]uint32
+	}{{
+		name:          "neighbor in two TLVs",
+		inTLVs:        []*rawTLV{nbrTLV(10), nbrTLV(20)},
+		wantInstances: map[uint64]uint32{0: 10, 1: 20},
+	}, {
+		name:          "neighbor in three TLVs",
+		inTLVs:        []*rawTLV{nbrTLV(10), nbrTLV(20), nbrTLV(30)},
+		wantInstances: map[uint64]uint32{0: 10, 1: 20, 2: 30},
+	}, {
+		name: "existing instance is not overwritten",
+		inLSP: func() *isisLSP {
+			l := newISISLSP()
+			n := l.LSP.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
+			n.GetOrCreateInstance(1).Metric = ygot.Uint32(42)
+			return l
+		}(),
+		inTLVs:        []*rawTLV{nbrTLV(10), nbrTLV(20)},
+		wantInstances: map[uint64]uint32{0: 10, 1: 42, 2: 20},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.inLSP
+			if got == nil {
+				got = newISISLSP()
+			}
+
+			for _, r := range tt.inTLVs {
+				if err := got.processExtendedISReachabilityTLV(r); err != nil {
+					t.Fatalf("i.processExtendedISReachabilityTLV(%v): got unexpected error: %v", r, err)
+				}
+			}
+
+			n := got.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetExtendedIsReachability().GetNeighbor("4900.0000.0000.01")
+			if n == nil {
+				t.Fatalf("i.processExtendedISReachabilityTLV: did not get neighbor 4900.0000.0000.01")
+			}
+			gotInstances := map[uint64]uint32{}
+			for id, inst := range n.Instance {
+				gotInstances[id] = uint32Value(inst.Metric)
+			}
+			if diff := pretty.Compare(gotInstances, tt.wantInstances); diff != "" {
+				t.Errorf("i.processExtendedISReachabilityTLV: did not get expected instances, diff(-got,+want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		inLSP   *isisLSP
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "tlv with no subtlvs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control - 0b10100000 = up/down, 32 bit prefix
+				0xA0,
+				// 4-bytes of prefix
+				192, 168, 1, 1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.168.1.1/32": {
+									Prefix: ygot.String("192.168.1.1/32"),
+									Metric: ygot.Uint32(42),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+
+		name: "tlv with two prefixes with no subtlvs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control - 0b10100000 = up/down, 32 bit prefix
+				0xA0,
+				// 4-bytes of prefix
+				192, 168, 1, 1,
+				// Metric
+				0x0, 0x0, 0x0, 0xFF,
+				// Control
+				0xA0,
+				// 4 bytes of prefix
+				192, 0, 2, 1,
+			},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.168.1.1/32": {
+									Prefix: ygot.String("192.168.1.1/32"),
+									Metric: ygot.Uint32(42),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+								"192.0.2.1/32": {
+									Prefix: ygot.String("192.0.2.1/32"),
+									Metric: ygot.Uint32(255),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "tlv where prefix length is >32",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
-				0x0, 0x0, 0xFF,
-				0x9,
-				0x10, 0x8,
-				0x44, 0x36, 0x10, 0x0, 0x0, 0x0, 0x0, 0x0,
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control - 0b00111111 pfx len == 63
+				0x3F,
+				// 9 bytes of prefix (63+7)/8
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
 			},
 		},
 		wantErr: true,
 	}, {
-		name: "short TLV after valid TLV",
+		name: "tlv where address overflows",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x42, 0x0,
-				0x42,
+				0x0, 0x0, 0x0, 0x2A,
+				0xA0,
+				192, 168, 1, 1, 1,
 			},
 		},
 		wantErr: true,
 	}, {
-		name: "Unreserved bandwidth - valid values",
+		name: "tlv with no subtlvs being appended to an existing LSP",
 		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{34},
-				// SubTLV type and length
-				[]byte{0xB, 0x20},
-				// Values per priority level
-				float32ByteSlice(0.0),
-				float32ByteSlice(1.0),
-				float32ByteSlice(2.0),
-				float32ByteSlice(3.0),
-				float32ByteSlice(4.0),
-				float32ByteSlice(5.0),
-				float32ByteSlice(6.0),
-				float32ByteSlice(7.0),
-			),
+			Value: []byte{
+				0x0, 0x0, 0x2A, 0x2A,
+				// up/down set, length 8 prefix
+				0x88,
+				// prefix
+				0xA,
+				// No subTLVs.
+			},
 		},
-		wantLSP: &isisLSP{
-			LSP: func() *oc.Lsp {
-				l := &oc.Lsp{}
-				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
-				n := neigh.GetOrCreateInstance(0)
-				n.Metric = ygot.Uint32(65535)
-				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_UNRESERVED_BANDWIDTH)
-				for i := range []uint8{0, 1, 2, 3, 4, 5, 6, 7} {
-					b := s.GetOrCreateSetupPriority(uint8(i))
-					b.Bandwidth = float32ByteSlice(float32(i))
-				}
-				return l
-			}(),
+		inLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.168.1.1/32": {
+									Prefix: ygot.String("192.168.1.1/32"),
+									Metric: ygot.Uint32(42),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+							},
+						},
+					},
+				},
+			},
 		},
-	}, {
-		name: "Unreserved bandwidth - invalid length",
-		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{34},
-				// SubTLV type and length
-				[]byte{0x0, 0x0, 0x0},
-			),
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.168.1.1/32": {
+									Prefix: ygot.String("192.168.1.1/32"),
+									Metric: ygot.Uint32(42),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+								"10.0.0.0/8": {
+									Prefix: ygot.String("10.0.0.0/8"),
+									Metric: ygot.Uint32(10794),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(true),
+								},
+							},
+						},
+					},
+				},
+			},
 		},
-		wantErr: true,
 	}, {
-		name: "link local and remote ID - invalid length",
+		name: "tlv with no subtlvs, with subtlv present bit set",
 		inTLV: &rawTLV{
-			Length: 3,
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x2A,
+				// subTLV present bit set, pfx len 8
+				0xC4,
+				192,
+				// Missing subTLVs.
+			},
 		},
 		wantErr: true,
 	}, {
-		name: "link local and remote ID",
-		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{10},
-				// SubTLV type and length
-				[]byte{4, 8},
-				// Local ID
-				[]byte{0x1, 0x1, 0x1, 0x1},
-				// Remote ID
-				[]byte{0x2, 0x2, 0x2, 0x2},
-			),
-		},
-		wantLSP: &isisLSP{
-			LSP: func() *oc.Lsp {
-				l := &oc.Lsp{}
-				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
-				n := neigh.GetOrCreateInstance(0)
-				n.Metric = ygot.Uint32(65535)
-				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_LINK_ID).GetOrCreateLinkId()
-				s.Local = ygot.Uint32(16843009)
-				s.Remote = ygot.Uint32(33686018)
-				return l
-			}(),
-		},
-	}, {
-		name: "link local and remote SID with invalid length",
+		name: "tlv with subtlvs, but insufficient data for length",
 		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{10},
-				// SubTLV type and length
-				[]byte{4, 7},
-				// Local ID
-				[]byte{0x1, 0x1, 0x1, 0x1},
-				// Remote ID
-				[]byte{0x2, 0x2, 0x2},
-			),
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x2A,
+				0xC4,
+				192,
+				// SubTLV length
+				0x2A,
+				// SubTLV contents
+				0x42, 0x42, 0x42,
+			},
 		},
 		wantErr: true,
 	}, {
-		name: "adjacency SID - valid value",
+		name: "tlv with prefix SID subtlv, MPLS label encoding",
 		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{7},
-				// Type + Length
-				[]byte{31, 5},
-				// Flags and Value
-				[]byte{0x30, 0xFF, 0x00, 0x00, 0x2A},
-			),
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x2A,
+				0xC4,
+				192,
+				// SubTLV length
+				0x7,
+				// SubTLV contents
+				0x3, 0x5,
+				// PrefixSID flags, 0b11110111 - such that all flags are set.
+				0xFC,
+				// Algorithm
+				0x1,
+				// MPLS label value
+				0x0, 0x0, 0x2A,
+			},
 		},
 		wantLSP: &isisLSP{
-			LSP: func() *oc.Lsp {
-				l := &oc.Lsp{}
-				neigh := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01")
-				n := neigh.GetOrCreateInstance(0)
-				n.Metric = ygot.Uint32(65535)
-				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
-				if err := s.AppendAdjacencySid(&oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdjacencySid{
-					Value:  ygot.Uint32(42),
-					Weight: ygot.Uint8(255),
-					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
-						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
-						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
-					},
-				}); err != nil {
-					panic(err)
-				}
-				return l
-			}(),
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.0.0.0/4": {
+									Prefix: ygot.String("192.0.0.0/4"),
+									Metric: ygot.Uint32(42),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(true),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID,
+											PrefixSid: map[uint32]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid{
+												42: {
+													Algorithm: ygot.Uint8(1),
+													Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{
+														oc.OpenconfigIsis_PrefixSid_Flags_READVERTISEMENT,
+														oc.OpenconfigIsis_PrefixSid_Flags_NODE,
+														oc.OpenconfigIsis_PrefixSid_Flags_NO_PHP,
+														oc.OpenconfigIsis_PrefixSid_Flags_EXPLICIT_NULL,
+														oc.OpenconfigIsis_PrefixSid_Flags_VALUE,
+														oc.OpenconfigIsis_PrefixSid_Flags_LOCAL,
+													},
+													Value: ygot.Uint32(42),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
 		},
 	}, {
-		name: "multiple adjacency SIDs",
+		name: "default route with prefix SID subtlv",
 		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{14},
-				// Type and Length
-				[]byte{31, 5},
-				// Flags, Weight, and Value
-				[]byte{0x30, 0xFF, 0x0, 0x0, 0x2A},
-				// Type and Length
-				[]byte{31, 5},
-				// Flags, Weight, and Value
-				[]byte{0x30, 0xFF, 0xFF, 0xFF, 0xFF},
-			),
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 0
+				0x40,
+				// No prefix bytes.
+				// SubTLV length
+				0x8,
+				// SubTLV contents
+				0x3, 0x6,
+				// Prefix SID flags, node flag set.
+				0x40,
+				// Algorithm
+				0x0,
+				// Index value
+				0x0, 0x0, 0x0, 0x64,
+				// Following prefix, parsed only if the sub-TLVs are
+				// correctly skipped.
+				0x0, 0x0, 0x0, 0x14,
+				0x18,
+				192, 0, 2,
+			},
 		},
 		wantLSP: &isisLSP{
-			LSP: func() *oc.Lsp {
-				l := &oc.Lsp{}
-				n := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01").GetOrCreateInstance(0)
-				n.Metric = ygot.Uint32(65535)
-				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID)
-
-				sids := []*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdjacencySid{{
-					Value:  ygot.Uint32(42),
-					Weight: ygot.Uint8(255),
-					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
-						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
-						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
-					},
-				}, {
-					Value:  ygot.Uint32(16777215),
-					Weight: ygot.Uint8(255),
-					Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
-						oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
-						oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"0.0.0.0/0": {
+									Prefix: ygot.String("0.0.0.0/0"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID,
+											PrefixSid: map[uint32]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid{
+												100: {
+													Algorithm: ygot.Uint8(0),
+													Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{
+														oc.OpenconfigIsis_PrefixSid_Flags_NODE,
+													},
+													Value: ygot.Uint32(100),
+												},
+											},
+										},
+									},
+								},
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(20),
+									SBit:   ygot.Bool(false),
+									UpDown: ygot.Bool(false),
+								},
+							},
+						},
 					},
-				}}
-
-				for _, as := range sids {
-					if err := s.AppendAdjacencySid(as); err != nil {
-						panic(err)
-					}
-				}
-
-				return l
-			}(),
+				},
+			},
 		},
 	}, {
-		name: "multiple LAN adjacency SIDs",
+		name: "prefix with administrative tag",
 		inTLV: &rawTLV{
-			Value: appendByteSlice(
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1},
-				[]byte{0x0, 0xFF, 0xFF},
-				// Length of SubTLVs
-				[]byte{26},
-				// Type and Length
-				[]byte{32, 11},
-				// Flags, Weight
-				[]byte{0x30, 0x00},
-				// SystemID
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x2},
-				// Value
-				[]byte{0x0, 0x0, 0x1},
-				// Type and Length
-				[]byte{32, 11},
-				// Flags, Weight
-				[]byte{0x30, 0x0},
-				// System ID
-				[]byte{0x49, 0x0, 0x0, 0x0, 0x0, 0x03},
-				// Value
-				[]byte{0x0, 0x0, 0x2},
-			),
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 24
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x6,
+				// SubTLV contents
+				0x1, 0x4,
+				0x0, 0x0, 0x0, 0x64,
+			},
 		},
 		wantLSP: &isisLSP{
-			LSP: func() *oc.Lsp {
-				l := &oc.Lsp{}
-				n := l.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("4900.0000.0000.01").GetOrCreateInstance(0)
-				n.Metric = ygot.Uint32(65535)
-				s := n.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_LAN_SID)
-
-				sids := []*oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LanAdjacencySid{{
-					Value:      ygot.Uint32(1),
-					Weight:     ygot.Uint8(0),
-					NeighborId: ygot.String("4900.0000.0002"),
-					Flags: []oc.E_OpenconfigIsis_LanAdjacencySid_Flags{
-						oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE,
-						oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL,
-					},
-				}, {
-					Value:      ygot.Uint32(2),
-					Weight:     ygot.Uint8(0),
-					NeighborId: ygot.String("4900.0000.0003"),
-					Flags: []oc.E_OpenconfigIsis_LanAdjacencySid_Flags{
-						oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE,
-						oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL,
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
+						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
+							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG,
+											Tag: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Tag{
+												Tag32: []uint32{100},
+											},
+										},
+									},
+								},
+							},
+						},
 					},
-				}}
-
-				for _, as := range sids {
-					if err := s.AppendLanAdjacencySid(as); err != nil {
-						panic(err)
-					}
-				}
-
-				return l
-			}(),
+				},
+			},
 		},
-	}}
-
-	for _, tt := range tests {
-		got := tt.inLSP
-		if got == nil {
-			got = newISISLSP()
-		}
-
-		err := got.processExtendedISReachabilityTLV(tt.inTLV)
-		if err != nil {
-			if !tt.wantErr {
-				t.Errorf("%s: i.processExtendedISReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
-			}
-			continue
-		}
-
-		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
-			t.Errorf("%s: i.processExtendedISReachabilityTLV(%v): did not get expected LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
-		}
-	}
-}
-
-func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
-	tests := []struct {
-		name    string
-		inTLV   *rawTLV
-		inLSP   *isisLSP
-		wantLSP *isisLSP
-		wantErr bool
-	}{{
-		name: "tlv with no subtlvs",
+	}, {
+		name: "prefix with several administrative tags",
 		inTLV: &rawTLV{
 			Value: []byte{
-				// Metric
-				0x0, 0x0, 0x0, 0x2A,
-				// Control - 0b10100000 = up/down, 32 bit prefix
-				0xA0,
-				// 4-bytes of prefix
-				192, 168, 1, 1,
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 24
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x10,
+				// SubTLV contents, two tags
+				0x1, 0x8,
+				0x0, 0x0, 0x0, 0x64,
+				0x0, 0x0, 0xFF, 0xFF,
+				// A further sub-TLV with a third tag
+				0x1, 0x4,
+				0xDE, 0xAD, 0xBE, 0xEF,
 			},
 		},
 		wantLSP: &isisLSP{
@@ -1981,11 +4411,19 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
 						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
 							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
-								"192.168.1.1/32": {
-									Prefix: ygot.String("192.168.1.1/32"),
-									Metric: ygot.Uint32(42),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG,
+											Tag: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Tag{
+												Tag32: []uint32{100, 65535, 3735928559},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -1994,22 +4432,33 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-
-		name: "tlv with two prefixes with no subtlvs",
+		name: "prefix with invalid length administrative tag",
 		inTLV: &rawTLV{
 			Value: []byte{
-				// Metric
-				0x0, 0x0, 0x0, 0x2A,
-				// Control - 0b10100000 = up/down, 32 bit prefix
-				0xA0,
-				// 4-bytes of prefix
-				192, 168, 1, 1,
-				// Metric
-				0x0, 0x0, 0x0, 0xFF,
-				// Control
-				0xA0,
-				// 4 bytes of prefix
-				192, 0, 2, 1,
+				0x0, 0x0, 0x0, 0x0A,
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x5,
+				// SubTLV contents
+				0x1, 0x3,
+				0x0, 0x0, 0x64,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "prefix with 64-bit administrative tag",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 24
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0xA,
+				// SubTLV contents
+				0x2, 0x8,
+				0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x64,
 			},
 		},
 		wantLSP: &isisLSP{
@@ -2019,17 +4468,19 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
 						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
 							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
-								"192.168.1.1/32": {
-									Prefix: ygot.String("192.168.1.1/32"),
-									Metric: ygot.Uint32(42),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
-								},
-								"192.0.2.1/32": {
-									Prefix: ygot.String("192.0.2.1/32"),
-									Metric: ygot.Uint32(255),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG64,
+											Tag64: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Tag64{
+												Tag64: []uint64{4294967396},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -2038,52 +4489,56 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "tlv where prefix length is >32",
-		inTLV: &rawTLV{
-			Value: []byte{
-				// Metric
-				0x0, 0x0, 0x0, 0x2A,
-				// Control - 0b00111111 pfx len == 63
-				0x3F,
-				// 9 bytes of prefix (63+7)/8
-				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-			},
-		},
-		wantErr: true,
-	}, {
-		name: "tlv where address overflows",
+		name: "prefix with truncated 64-bit administrative tag",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x0, 0x0, 0x0, 0x2A,
-				0xA0,
-				192, 168, 1, 1, 1,
+				0x0, 0x0, 0x0, 0x0A,
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0xC,
+				// SubTLV contents, one complete and one truncated tag
+				0x2, 0xA,
+				0x0, 0x0, 0x0, 0x1, 0x0, 0x0, 0x0, 0x64,
+				0x0, 0x0,
 			},
 		},
 		wantErr: true,
 	}, {
-		name: "tlv with no subtlvs being appended to an existing LSP",
+		name: "prefix with prefix attribute flags",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x0, 0x0, 0x2A, 0x2A,
-				// up/down set, length 8 prefix
-				0x88,
-				// prefix
-				0xA,
-				// No subTLVs.
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 24
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x4,
+				// SubTLV contents, N-flag set followed by a reserved byte
+				0x4, 0x2,
+				0x20, 0x0,
 			},
 		},
-		inLSP: &isisLSP{
+		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
 					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY: {
 						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
 						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
 							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
-								"192.168.1.1/32": {
-									Prefix: ygot.String("192.168.1.1/32"),
-									Metric: ygot.Uint32(42),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_FLAGS,
+											Flags: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Flags{
+												Flags: []oc.E_OpenconfigIsis_Flags_Flags{oc.OpenconfigIsis_Flags_Flags_NODE_FLAG},
+											},
+										},
+									},
 								},
 							},
 						},
@@ -2091,6 +4546,25 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 				},
 			},
 		},
+	}, {
+		name: "prefix with source router IDs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				// subTLVs present, prefix length 24
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x18,
+				// IPv4 source router ID sub-TLV
+				0xB, 0x4,
+				192, 0, 2, 1,
+				// IPv6 source router ID sub-TLV
+				0xC, 0x10,
+				0x20, 0x01, 0x0d, 0xb8, 0x0, 0x0, 0x0, 0x0,
+				0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1,
+			},
+		},
 		wantLSP: &isisLSP{
 			LSP: &oc.Lsp{
 				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
@@ -2098,17 +4572,25 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
 						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
 							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
-								"192.168.1.1/32": {
-									Prefix: ygot.String("192.168.1.1/32"),
-									Metric: ygot.Uint32(42),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
-								},
-								"10.0.0.0/8": {
-									Prefix: ygot.String("10.0.0.0/8"),
-									Metric: ygot.Uint32(10794),
-									SBit:   ygot.Bool(false),
-									UpDown: ygot.Bool(true),
+								"192.0.2.0/24": {
+									Prefix: ygot.String("192.0.2.0/24"),
+									Metric: ygot.Uint32(10),
+									SBit:   ygot.Bool(true),
+									UpDown: ygot.Bool(false),
+									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID,
+											Ipv4SourceRouterId: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Ipv4SourceRouterId{
+												RouterId: ygot.String("192.0.2.1"),
+											},
+										},
+										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID: {
+											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV6_ROUTER_ID,
+											Ipv6SourceRouterId: &oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_Ipv6SourceRouterId{
+												RouterId: ygot.String("2001:db8::1"),
+											},
+										},
+									},
 								},
 							},
 						},
@@ -2117,48 +4599,57 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "tlv with no subtlvs, with subtlv present bit set",
+		name: "prefix with invalid length source router ID",
 		inTLV: &rawTLV{
 			Value: []byte{
-				0x0, 0x0, 0x0, 0x2A,
-				// subTLV present bit set, pfx len 8
-				0xC4,
-				192,
-				// Missing subTLVs.
+				0x0, 0x0, 0x0, 0x0A,
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0x7,
+				// IPv6 source router ID sub-TLV carrying an IPv4 address
+				0xC, 0x5,
+				192, 0, 2, 1, 0,
 			},
 		},
 		wantErr: true,
 	}, {
-		name: "tlv with subtlvs, but insufficient data for length",
+		name: "tlv with prefix SID subtlv, value flag with index length",
 		inTLV: &rawTLV{
 			Value: []byte{
 				0x0, 0x0, 0x0, 0x2A,
 				0xC4,
 				192,
 				// SubTLV length
-				0x2A,
+				0x8,
 				// SubTLV contents
-				0x42, 0x42, 0x42,
+				0x3, 0x6,
+				// Prefix SID flags, no-PHP, explicit-null, value and local set.
+				0x3C,
+				// Algorithm
+				0x0,
+				// Index length value
+				0x2A, 0x2A, 0x2A, 0x2A,
 			},
 		},
 		wantErr: true,
 	}, {
-		name: "tlv with prefix SID subtlv, MPLS label encoding",
+		name: "tlv with prefix SID subtlv, index value encoding",
 		inTLV: &rawTLV{
 			Value: []byte{
 				0x0, 0x0, 0x0, 0x2A,
 				0xC4,
 				192,
 				// SubTLV length
-				0x7,
+				0x8,
 				// SubTLV contents
-				0x3, 0x5,
-				// PrefixSID flags, 0b11110111 - such that all flags are set.
-				0xFC,
+				0x3, 0x6,
+				// Prefix SID flags, value and local unset.
+				0xF4,
 				// Algorithm
-				0x1,
-				// MPLS label value
-				0x0, 0x0, 0x2A,
+				0x0,
+				// Index value
+				0x2A, 0x2A, 0x2A, 0x2A,
 			},
 		},
 		wantLSP: &isisLSP{
@@ -2177,17 +4668,16 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID: {
 											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID,
 											PrefixSid: map[uint32]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid{
-												42: {
-													Algorithm: ygot.Uint8(1),
+												707406378: {
+													Algorithm: ygot.Uint8(0),
 													Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{
 														oc.OpenconfigIsis_PrefixSid_Flags_READVERTISEMENT,
 														oc.OpenconfigIsis_PrefixSid_Flags_NODE,
 														oc.OpenconfigIsis_PrefixSid_Flags_NO_PHP,
 														oc.OpenconfigIsis_PrefixSid_Flags_EXPLICIT_NULL,
-														oc.OpenconfigIsis_PrefixSid_Flags_VALUE,
 														oc.OpenconfigIsis_PrefixSid_Flags_LOCAL,
 													},
-													Value: ygot.Uint32(42),
+													Value: ygot.Uint32(707406378),
 												},
 											},
 										},
@@ -2200,22 +4690,43 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "tlv with prefix SID subtlv, index value encoding",
+		name: "tlv with prefix SID subtlv, value with incorrect length",
 		inTLV: &rawTLV{
 			Value: []byte{
 				0x0, 0x0, 0x0, 0x2A,
 				0xC4,
 				192,
 				// SubTLV length
+				0x9,
+				// SubTLV contents
+				0x3, 0x5,
+				//  MPLS label specified
+				0xF7,
+				// Algorithm
+				0x0,
+				// Index value
+				0x2A, 0x2A, 0x2A, 0x2A,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "prefix SID subtlv exactly filling the TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				// Control - sub-TLVs present, 24 bit prefix
+				0x58,
+				10, 0, 1,
+				// SubTLV length
 				0x8,
 				// SubTLV contents
 				0x3, 0x6,
-				// Prefix SID flags, value and local unset.
-				0xF4,
+				// PrefixSID flags - node SID
+				0x40,
 				// Algorithm
 				0x0,
 				// Index value
-				0x2A, 0x2A, 0x2A, 0x2A,
+				0x0, 0x0, 0x0, 0x2A,
 			},
 		},
 		wantLSP: &isisLSP{
@@ -2225,25 +4736,21 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY,
 						ExtendedIpv4Reachability: &oc.Lsp_Tlv_ExtendedIpv4Reachability{
 							Prefix: map[string]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix{
-								"192.0.0.0/4": {
-									Prefix: ygot.String("192.0.0.0/4"),
-									Metric: ygot.Uint32(42),
+								"10.0.1.0/24": {
+									Prefix: ygot.String("10.0.1.0/24"),
+									Metric: ygot.Uint32(10),
 									SBit:   ygot.Bool(true),
-									UpDown: ygot.Bool(true),
+									UpDown: ygot.Bool(false),
 									Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv{
 										oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID: {
 											Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID,
 											PrefixSid: map[uint32]*oc.Lsp_Tlv_ExtendedIpv4Reachability_Prefix_Subtlv_PrefixSid{
-												707406378: {
+												42: {
 													Algorithm: ygot.Uint8(0),
 													Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{
-														oc.OpenconfigIsis_PrefixSid_Flags_READVERTISEMENT,
 														oc.OpenconfigIsis_PrefixSid_Flags_NODE,
-														oc.OpenconfigIsis_PrefixSid_Flags_NO_PHP,
-														oc.OpenconfigIsis_PrefixSid_Flags_EXPLICIT_NULL,
-														oc.OpenconfigIsis_PrefixSid_Flags_LOCAL,
 													},
-													Value: ygot.Uint32(707406378),
+													Value: ygot.Uint32(42),
 												},
 											},
 										},
@@ -2256,22 +4763,306 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			},
 		},
 	}, {
-		name: "tlv with prefix SID subtlv, value with incorrect length",
+		name: "prefix SID subtlv followed by a trailing byte",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				0x58,
+				10, 0, 1,
+				// SubTLV length
+				0x8,
+				// SubTLV contents
+				0x3, 0x6,
+				0x40,
+				0x0,
+				0x0, 0x0, 0x0, 0x2A,
+				// Trailing byte
+				0xFF,
+			},
+		},
+		wantErr: true,
+	}, {
+		name: "subtlv length overflowing the TLV",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x0, 0x0, 0x0, 0x0A,
+				0x58,
+				10, 0, 1,
+				// SubTLV length, one byte longer than the sub-TLVs
+				0x9,
+				0x3, 0x6,
+				0x40,
+				0x0,
+				0x0, 0x0, 0x0, 0x2A,
+			},
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got := tt.inLSP
+		if got == nil {
+			got = newISISLSP()
+		}
+
+		err := got.processExtendedIPReachTLV(tt.inTLV)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processExtendedIPReachTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processExtendedIPReachTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
+
+func TestProcessExtendedIPReachTLVDuplicatePrefix(t *testing.T) {
+	r := &rawTLV{
+		Value: []byte{
+			0x0, 0x0, 0x0, 0x0A,
+			0x18,
+			10, 0, 1,
+			// Duplicate of 10.0.1.0/24, with a metric of 20 and a
+			// prefix SID sub-TLV.
+			0x0, 0x0, 0x0, 0x14,
+			0x58,
+			10, 0, 1,
+			0x8,
+			0x3, 0x6, 0x40, 0x0, 0x0, 0x0, 0x0, 0x2A,
+			// Distinct prefix following the duplicate.
+			0x0, 0x0, 0x0, 0x1E,
+			0x18,
+			10, 0, 2,
+		},
+	}
+
+	i := newISISLSP()
+	err := i.processExtendedIPReachTLV(r)
+	if diff := errdiff.Substring(err, "duplicate prefix 10.0.1.0/24 in Extended IP Reachability TLV"); diff != "" {
+		t.Errorf("i.processExtendedIPReachTLV(%v): did not get expected error, %s", r, diff)
+	}
+
+	pfxs := i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability().Prefix
+	if p := pfxs["10.0.1.0/24"]; p == nil || p.Metric == nil || *p.Metric != 10 || len(p.Subtlv) != 0 {
+		t.Errorf("i.processExtendedIPReachTLV(%v): did not retain first instance of duplicate prefix, got: %v", r, p)
+	}
+	if p := pfxs["10.0.2.0/24"]; p == nil || p.Metric == nil || *p.Metric != 30 {
+		t.Errorf("i.processExtendedIPReachTLV(%v): did not parse prefix following duplicate, got: %v", r, p)
+	}
+}
+
+func TestProcessMTIPv4ReachabilityTLV(t *testing.T) {
+	mtLSP := func(pfxs ...*oc.Lsp_Tlv_MtIpv4Reachability_Prefix) *isisLSP {
+		m := map[oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Key]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix{}
+		for _, p := range pfxs {
+			m[oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Key{MtId: *p.MtId, Prefix: *p.Prefix}] = p
+		}
+		return &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MT_IPV4_REACHABILITY,
+						MtIpv4Reachability: &oc.Lsp_Tlv_MtIpv4Reachability{
+							Prefix: m,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		inLSP   *isisLSP
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "tlv with no subtlvs",
+		inTLV: &rawTLV{
+			Value: []byte{
+				// MT ID 2
+				0x00, 0x02,
+				// Metric
+				0x0, 0x0, 0x0, 0x2A,
+				// Control - 0b10100000 = up/down, 32 bit prefix
+				0xA0,
+				// 4-bytes of prefix
+				192, 168, 1, 1,
+			},
+		},
+		wantLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.168.1.1/32"),
+			Metric: ygot.Uint32(42),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(true),
+		}),
+	}, {
+		name: "reserved bits of MT ID are ignored",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0xF0, 0x02,
+				0x0, 0x0, 0x0, 0x2A,
+				// Control - 24 bit prefix
+				0x18,
+				192, 0, 2,
+			},
+		},
+		wantLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(42),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(false),
+		}),
+	}, {
+		name: "prefix with prefix SID in MT ID 2",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x00, 0x02,
+				0x0, 0x0, 0x0, 0x0A,
+				// Control - subTLVs present, 32 bit prefix
+				0x60,
+				192, 0, 2, 1,
+				// SubTLV length
+				0x8,
+				// SubTLV contents
+				0x3, 0x6,
+				// Prefix SID flags, node flag set.
+				0x40,
+				// Algorithm
+				0x0,
+				// Index value
+				0x0, 0x0, 0x0, 0x10,
+			},
+		},
+		wantLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.0.2.1/32"),
+			Metric: ygot.Uint32(10),
+			SBit:   ygot.Bool(true),
+			UpDown: ygot.Bool(false),
+			Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID,
+					PrefixSid: map[uint32]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_PrefixSid{
+						16: {
+							Algorithm: ygot.Uint8(0),
+							Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{
+								oc.OpenconfigIsis_PrefixSid_Flags_NODE,
+							},
+							Value: ygot.Uint32(16),
+						},
+					},
+				},
+			},
+		}),
+	}, {
+		name: "prefix with tag and source router ID in MT ID 2",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x00, 0x02,
+				0x0, 0x0, 0x0, 0x0A,
+				// Control - subTLVs present, 24 bit prefix
+				0x58,
+				192, 0, 2,
+				// SubTLV length
+				0xC,
+				// Tag sub-TLV
+				0x1, 0x4,
+				0x0, 0x0, 0x0, 0x2A,
+				// IPv4 source router ID sub-TLV
+				0xB, 0x4,
+				192, 0, 2, 1,
+			},
+		},
+		wantLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(10),
+			SBit:   ygot.Bool(true),
+			UpDown: ygot.Bool(false),
+			Subtlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE]*oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv{
+				oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG,
+					Tag: &oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Tag{
+						Tag32: []uint32{42},
+					},
+				},
+				oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID: {
+					Type: oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_IPV4_ROUTER_ID,
+					Ipv4SourceRouterId: &oc.Lsp_Tlv_MtIpv4Reachability_Prefix_Subtlv_Ipv4SourceRouterId{
+						RouterId: ygot.String("192.0.2.1"),
+					},
+				},
+			},
+		}),
+	}, {
+		name: "same prefix in a different topology",
 		inTLV: &rawTLV{
 			Value: []byte{
+				0x00, 0x02,
 				0x0, 0x0, 0x0, 0x2A,
-				0xC4,
-				192,
-				// SubTLV length
-				0x9,
-				// SubTLV contents
-				0x3, 0x5,
-				//  MPLS label specified
-				0xF7,
-				// Algorithm
-				0x0,
-				// Index value
-				0x2A, 0x2A, 0x2A, 0x2A,
+				0x18,
+				192, 0, 2,
+			},
+		},
+		inLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(3),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(10),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(false),
+		}),
+		wantLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(3),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(10),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(false),
+		}, &oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(42),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(false),
+		}),
+	}, {
+		name: "duplicate prefix in the same topology",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x00, 0x02,
+				0x0, 0x0, 0x0, 0x2A,
+				0x18,
+				192, 0, 2,
+			},
+		},
+		inLSP: mtLSP(&oc.Lsp_Tlv_MtIpv4Reachability_Prefix{
+			MtId:   ygot.Uint16(2),
+			Prefix: ygot.String("192.0.2.0/24"),
+			Metric: ygot.Uint32(10),
+			SBit:   ygot.Bool(false),
+			UpDown: ygot.Bool(false),
+		}),
+		wantErr: true,
+	}, {
+		name: "insufficient data for MT ID",
+		inTLV: &rawTLV{
+			Value: []byte{0x00},
+		},
+		wantErr: true,
+	}, {
+		name: "tlv where address overflows",
+		inTLV: &rawTLV{
+			Value: []byte{
+				0x00, 0x02,
+				0x0, 0x0, 0x0, 0x2A,
+				0xA0,
+				192, 168, 1,
 			},
 		},
 		wantErr: true,
@@ -2283,16 +5074,21 @@ func TestProcessExtendedIPv4ReachabilityTLV(t *testing.T) {
 			got = newISISLSP()
 		}
 
-		err := got.processExtendedIPReachTLV(tt.inTLV)
+		err := got.processMTIPv4ReachabilityTLV(tt.inTLV)
 		if err != nil {
 			if !tt.wantErr {
-				t.Errorf("%s: i.processExtendedIPReachTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+				t.Errorf("%s: i.processMTIPv4ReachabilityTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
 			}
 			continue
 		}
 
+		if tt.wantErr {
+			t.Errorf("%s: i.processMTIPv4ReachabilityTLV(%v): did not get expected error", tt.name, tt.inTLV)
+			continue
+		}
+
 		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
-			t.Errorf("%s: i.processExtendedIPReachTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+			t.Errorf("%s: i.processMTIPv4ReachabilityTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
 		}
 	}
 }
@@ -2419,6 +5215,27 @@ func TestParseAdjSIDSubTLV(t *testing.T) {
 			Value:  ygot.Uint32(0),
 			Weight: ygot.Uint8(0),
 		},
+	}, {
+		name: "persistent flag with local and value flags",
+		in: &rawTLV{
+			Value: []byte{
+				// Value, local and persistent flags set. The persistent
+				// flag is not represented in the OpenConfig model.
+				0x34,
+				// Weight
+				0x00,
+				// Label value
+				0x00, 0x00, 0x2A,
+			},
+		},
+		want: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_AdjacencySid{
+			Flags: []oc.E_OpenconfigIsis_AdjacencySid_Flags{
+				oc.OpenconfigIsis_AdjacencySid_Flags_VALUE,
+				oc.OpenconfigIsis_AdjacencySid_Flags_LOCAL,
+			},
+			Value:  ygot.Uint32(42),
+			Weight: ygot.Uint8(0),
+		},
 	}, {
 		name: "local and value flag",
 		in: &rawTLV{
@@ -2622,6 +5439,30 @@ func TestParseLANAdjSIDSubTLV(t *testing.T) {
 			Weight:     ygot.Uint8(0),
 			NeighborId: ygot.String("4900.0000.0001"),
 		},
+	}, {
+		name: "persistent flag with local and value flags",
+		in: &rawTLV{
+			Value: []byte{
+				// Value, local and persistent flags set. The persistent
+				// flag is not represented in the OpenConfig model.
+				0x34,
+				// Weight
+				0x00,
+				// System ID
+				0x49, 0, 0, 0, 0, 1,
+				// Label value
+				0x00, 0x00, 0x2A,
+			},
+		},
+		want: &oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance_Subtlv_LanAdjacencySid{
+			Flags: []oc.E_OpenconfigIsis_LanAdjacencySid_Flags{
+				oc.OpenconfigIsis_LanAdjacencySid_Flags_VALUE,
+				oc.OpenconfigIsis_LanAdjacencySid_Flags_LOCAL,
+			},
+			Value:      ygot.Uint32(42),
+			Weight:     ygot.Uint8(0),
+			NeighborId: ygot.String("4900.0000.0001"),
+		},
 	}, {
 		name: "local and value flag",
 		in: &rawTLV{
@@ -2816,6 +5657,27 @@ func TestParseLSPFlags(t *testing.T) {
 		name: "overload",
 		in:   0x4,
 		want: []oc.E_OpenconfigIsis_Lsp_Flags{oc.OpenconfigIsis_Lsp_Flags_OVERLOAD},
+	}, {
+		name: "overload and attached default",
+		in:   0xC,
+		want: []oc.E_OpenconfigIsis_Lsp_Flags{
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT,
+			oc.OpenconfigIsis_Lsp_Flags_OVERLOAD,
+		},
+	}, {
+		name: "all flags, with IS type bits set",
+		in:   0xFF,
+		want: []oc.E_OpenconfigIsis_Lsp_Flags{
+			oc.OpenconfigIsis_Lsp_Flags_PARTITION_REPAIR,
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_ERROR,
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_EXPENSE,
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DELAY,
+			oc.OpenconfigIsis_Lsp_Flags_ATTACHED_DEFAULT,
+			oc.OpenconfigIsis_Lsp_Flags_OVERLOAD,
+		},
+	}, {
+		name: "IS type bits only",
+		in:   0x3,
 	}}
 
 	for _, tt := range tests {
@@ -2825,6 +5687,61 @@ func TestParseLSPFlags(t *testing.T) {
 	}
 }
 
+func TestParsePrefixAttributeFlagsSubTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []byte
+		want    []oc.E_OpenconfigIsis_Flags_Flags
+		wantErr bool
+	}{{
+		name: "external flag",
+		in:   []byte{0x80},
+		want: []oc.E_OpenconfigIsis_Flags_Flags{oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG},
+	}, {
+		name: "readvertisement flag",
+		in:   []byte{0x40},
+		want: []oc.E_OpenconfigIsis_Flags_Flags{oc.OpenconfigIsis_Flags_Flags_READVERTISEMENT_FLAG},
+	}, {
+		name: "node flag",
+		in:   []byte{0x20},
+		want: []oc.E_OpenconfigIsis_Flags_Flags{oc.OpenconfigIsis_Flags_Flags_NODE_FLAG},
+	}, {
+		name: "all flags, with reserved bits set",
+		in:   []byte{0xFF},
+		want: []oc.E_OpenconfigIsis_Flags_Flags{
+			oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG,
+			oc.OpenconfigIsis_Flags_Flags_READVERTISEMENT_FLAG,
+			oc.OpenconfigIsis_Flags_Flags_NODE_FLAG,
+		},
+	}, {
+		name: "external and node flags with trailing reserved bytes",
+		in:   []byte{0xA0, 0xFF, 0xFF},
+		want: []oc.E_OpenconfigIsis_Flags_Flags{
+			oc.OpenconfigIsis_Flags_Flags_EXTERNAL_FLAG,
+			oc.OpenconfigIsis_Flags_Flags_NODE_FLAG,
+		},
+	}, {
+		name: "no flags",
+		in:   []byte{0x00},
+	}, {
+		name:    "empty sub-TLV",
+		in:      []byte{},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got, err := parsePrefixAttributeFlagsSubTLV(&rawTLV{Type: 4, Length: uint8(len(tt.in)), Value: tt.in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: parsePrefixAttributeFlagsSubTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, tt.in, err, tt.wantErr)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parsePrefixAttributeFlagsSubTLV(%v): did not get expected output, got: %v, want: %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
 func TestParseLinkLocalRemoteSubTLV(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -2873,3 +5790,281 @@ func TestParseLinkLocalRemoteSubTLV(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePrefixSIDSubTLV(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               []byte
+		want             *prefixSIDSubTLV
+		wantErrSubstring string
+	}{{
+		name: "index",
+		in:   []byte{0x40, 0x00, 0x00, 0x00, 0x00, 0x2A},
+		want: &prefixSIDSubTLV{
+			Value: 42,
+			Flags: []oc.E_OpenconfigIsis_PrefixSid_Flags{oc.OpenconfigIsis_PrefixSid_Flags_NODE},
+		},
+	}, {
+		name: "label",
+		in:   []byte{0x0C, 0x01, 0x00, 0x3E, 0x80},
+		want: &prefixSIDSubTLV{
+			Algorithm: 1,
+			Value:     16000,
+			Flags:     []oc.E_OpenconfigIsis_PrefixSid_Flags{oc.OpenconfigIsis_PrefixSid_Flags_VALUE, oc.OpenconfigIsis_PrefixSid_Flags_LOCAL},
+		},
+	}, {
+		name:             "empty",
+		wantErrSubstring: "invalid length: 0",
+	}, {
+		name:             "index with label length",
+		in:               []byte{0x00, 0x00, 0x00, 0x00, 0x2A},
+		wantErrSubstring: "invalid Prefix-SID length for VALUE flag false, got: 5, want: 6",
+	}, {
+		name:             "index with 4 bytes",
+		in:               []byte{0x00, 0x00, 0x00, 0x2A},
+		wantErrSubstring: "invalid Prefix-SID length for VALUE flag false, got: 4, want: 6",
+	}, {
+		name:             "label with 4 bytes",
+		in:               []byte{0x0C, 0x00, 0x3E, 0x80},
+		wantErrSubstring: "invalid Prefix-SID length for VALUE flag true, got: 4, want: 5",
+	}, {
+		name:             "value flag with index length",
+		in:               []byte{0x0C, 0x00, 0x00, 0x00, 0x00, 0x2A},
+		wantErrSubstring: "invalid Prefix-SID length for VALUE flag true, got: 6, want: 5",
+	}, {
+		name:             "index with trailing bytes",
+		in:               []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x2A, 0xFF},
+		wantErrSubstring: "invalid Prefix-SID length for VALUE flag false, got: 7, want: 6",
+	}}
+
+	for _, tt := range tests {
+		// The value is given a capacity equal to its length, as per the
+		// sub-TLVs returned by TLVBytesToTLVs, such that a read beyond
+		// the end of the sub-TLV panics.
+		in := tt.in[:len(tt.in):len(tt.in)]
+		got, err := parsePrefixSIDSubTLV(&rawTLV{Type: 3, Length: uint8(len(in)), Value: in})
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: parsePrefixSIDSubTLV(%v): did not get expected error, %s", tt.name, tt.in, diff)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: parsePrefixSIDSubTLV(%v): did not get expected result, diff(-got,+want):\n%s", tt.name, tt.in, diff)
+		}
+	}
+}
+
+func TestShortPrefixSIDSubTLV(t *testing.T) {
+	// lsp is an LSP containing an Extended IP Reachability TLV with a
+	// Prefix-SID sub-TLV that carries a 2-byte index, which is shorter
+	// than the 4 bytes required, followed by a Dynamic Hostname TLV. Since
+	// the sub-TLVs returned by TLVBytesToTLVs are capped at their length,
+	// reading the index beyond the end of the sub-TLV would panic.
+	lsp := []byte{
+		0, 0, 0x40, 0, 0xce, 0x39, 0, 0, 0, 0, 0, 1, 0, 0, 0x03,
+		135, 15,
+		0, 0, 0, 10,
+		// Control - subTLVs present, 24 bit prefix
+		0x58,
+		192, 0, 2,
+		// SubTLV length
+		6,
+		// Prefix-SID sub-TLV with a 2-byte index
+		3, 4, 0x40, 0x00, 0x00, 0x10,
+		137, 2, 'r', '1',
+	}
+
+	got, ok, err := ISISBytesToLSP(lsp, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(%v): could not parse LSP: %v", lsp, err)
+	}
+	if diff := errdiff.Substring(err, "invalid Prefix-SID length for VALUE flag false, got: 4, want: 6"); diff != "" {
+		t.Errorf("ISISBytesToLSP(%v): did not get expected error, %s", lsp, diff)
+	}
+
+	p := got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY).GetExtendedIpv4Reachability().GetPrefix("192.0.2.0/24")
+	if p == nil {
+		t.Errorf("ISISBytesToLSP(%v): did not retain prefix with invalid Prefix-SID", lsp)
+	} else if st := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID); st != nil {
+		t.Errorf("ISISBytesToLSP(%v): got unexpected Prefix-SID sub-TLV: %v", lsp, st)
+	}
+
+	if h := got.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME).GetHostname(); h == nil || len(h.Hostname) != 1 || h.Hostname[0] != "r1" {
+		t.Errorf("ISISBytesToLSP(%v): did not parse TLV following invalid Prefix-SID, got: %v", lsp, h)
+	}
+}
+
+func TestValidatePrefixSIDFlags(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               []byte
+		wantErrSubstring string
+	}{{
+		name: "index",
+		in:   []byte{0x60, 0x00, 0x00, 0x00, 0x00, 0x2A},
+	}, {
+		name: "label",
+		in:   []byte{0x0C, 0x00, 0x00, 0x3E, 0x80},
+	}, {
+		name: "explicit null with no PHP",
+		in:   []byte{0x30, 0x00, 0x00, 0x00, 0x00, 0x2A},
+	}, {
+		name:             "explicit null without no PHP",
+		in:               []byte{0x10, 0x00, 0x00, 0x00, 0x00, 0x2A},
+		wantErrSubstring: "EXPLICIT_NULL set without NO_PHP",
+	}}
+
+	for _, tt := range tests {
+		err := validatePrefixSIDFlags(&rawTLV{Type: 3, Length: uint8(len(tt.in)), Value: tt.in})
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: validatePrefixSIDFlags(%v): did not get expected error, %s", tt.name, tt.in, diff)
+		}
+	}
+}
+
+func TestProcessMTTLV(t *testing.T) {
+	tests := []struct {
+		name    string
+		inTLV   *rawTLV
+		wantLSP *isisLSP
+		wantErr bool
+	}{{
+		name: "standard and IPv6 topologies",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 4,
+			Value:  []byte{0x00, 0x00, 0x00, 0x02},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY,
+						MultiTopology: &oc.Lsp_Tlv_MultiTopology{
+							Topology: map[uint16]*oc.Lsp_Tlv_MultiTopology_Topology{
+								0: {MtId: ygot.Uint16(0)},
+								2: {MtId: ygot.Uint16(2)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "overload and attached flags",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 4,
+			Value:  []byte{0x80, 0x02, 0x40, 0x03},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY,
+						MultiTopology: &oc.Lsp_Tlv_MultiTopology{
+							Topology: map[uint16]*oc.Lsp_Tlv_MultiTopology_Topology{
+								2: {
+									MtId:       ygot.Uint16(2),
+									Attributes: oc.OpenconfigIsis_Topology_Attributes_OVERLOAD,
+								},
+								3: {
+									MtId:       ygot.Uint16(3),
+									Attributes: oc.OpenconfigIsis_Topology_Attributes_ATTACHED,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "overload and attached flags for one topology",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 2,
+			Value:  []byte{0xC0, 0x02},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY,
+						MultiTopology: &oc.Lsp_Tlv_MultiTopology{
+							Topology: map[uint16]*oc.Lsp_Tlv_MultiTopology_Topology{
+								2: {
+									MtId:       ygot.Uint16(2),
+									Attributes: oc.OpenconfigIsis_Topology_Attributes_OVERLOAD,
+								},
+							},
+						},
+					},
+				},
+				UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+					229: {
+						Type:   ygot.Uint8(229),
+						Length: ygot.Uint8(2),
+						Value:  oc.Binary{0xC0, 0x02},
+					},
+				},
+			},
+		},
+	}, {
+		name: "reserved bits are ignored",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 2,
+			Value:  []byte{0x30, 0x02},
+		},
+		wantLSP: &isisLSP{
+			LSP: &oc.Lsp{
+				Tlv: map[oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE]*oc.Lsp_Tlv{
+					oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY: {
+						Type: oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_MULTI_TOPOLOGY,
+						MultiTopology: &oc.Lsp_Tlv_MultiTopology{
+							Topology: map[uint16]*oc.Lsp_Tlv_MultiTopology_Topology{
+								2: {MtId: ygot.Uint16(2)},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, {
+		name: "truncated entry",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 3,
+			Value:  []byte{0x00, 0x00, 0x00},
+		},
+		wantErr: true,
+	}, {
+		name: "duplicate MT ID",
+		inTLV: &rawTLV{
+			Type:   229,
+			Length: 4,
+			Value:  []byte{0x00, 0x02, 0x80, 0x02},
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		got := newISISLSP()
+		err := got.processMTTLV(tt.inTLV)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: i.processMTTLV(%v): got unexpected error: %v", tt.name, tt.inTLV, err)
+			}
+			continue
+		}
+
+		if tt.wantErr {
+			t.Errorf("%s: i.processMTTLV(%v): did not get expected error", tt.name, tt.inTLV)
+			continue
+		}
+
+		if diff := pretty.Compare(got, tt.wantLSP); diff != "" {
+			t.Errorf("%s: i.processMTTLV(%v): got incorrect LSP, diff(-got,+want):\n%s", tt.name, tt.inTLV, diff)
+		}
+	}
+}
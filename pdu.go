@@ -0,0 +1,210 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+const (
+	// isisIRPD is the intradomain routeing protocol discriminator that is
+	// carried in the first byte of the common header of IS-IS PDUs.
+	isisIRPD uint8 = 0x83
+	// isisVersion is the value of the version/protocol ID extension and
+	// version fields of the IS-IS common header.
+	isisVersion uint8 = 1
+	// commonHeaderLen is the length of the IS-IS common header, which is
+	// common to all IS-IS PDUs.
+	commonHeaderLen = 8
+	// defaultIDLength is the system ID length that is indicated by a value
+	// of 0 in the ID length field of the common header.
+	defaultIDLength = 6
+	// placeholderRemainingLifetime is the remaining lifetime, in seconds,
+	// that is written into synthetic LSP headers. It is the default
+	// MaxAge of an LSP, such that the LSP is not considered to be purged.
+	placeholderRemainingLifetime uint16 = 1200
+)
+
+// IS-IS PDU types, as carried in the common header.
+const (
+	// PDUTypeL1LANHello is the PDU type of a level 1 LAN IS-IS Hello.
+	PDUTypeL1LANHello uint8 = 15
+	// PDUTypeL2LANHello is the PDU type of a level 2 LAN IS-IS Hello.
+	PDUTypeL2LANHello uint8 = 16
+	// PDUTypeP2PHello is the PDU type of a point-to-point IS-IS Hello.
+	PDUTypeP2PHello uint8 = 17
+	// PDUTypeL1LSP is the PDU type of a level 1 LSP.
+	PDUTypeL1LSP uint8 = 18
+	// PDUTypeL2LSP is the PDU type of a level 2 LSP.
+	PDUTypeL2LSP uint8 = 20
+	// PDUTypeL1CSNP is the PDU type of a level 1 CSNP.
+	PDUTypeL1CSNP uint8 = 24
+	// PDUTypeL2CSNP is the PDU type of a level 2 CSNP.
+	PDUTypeL2CSNP uint8 = 25
+	// PDUTypeL1PSNP is the PDU type of a level 1 PSNP.
+	PDUTypeL1PSNP uint8 = 26
+	// PDUTypeL2PSNP is the PDU type of a level 2 PSNP.
+	PDUTypeL2PSNP uint8 = 27
+)
+
+// LSPIDOffset is the offset of the LSP ID field within a standard IS-IS LSP
+// PDU. It can be supplied as the offset to ISISBytesToLSP to parse a PDU that
+// includes the common header, such as one returned by WrapAsStandardPDU.
+const LSPIDOffset = commonHeaderLen + 4
+
+// WrapAsStandardPDU takes an input slice of bytes that contains an IS-IS LSP
+// starting at the LSP ID field, as is handled by ISISBytesToLSP, and returns
+// a new slice that contains the same LSP framed as a standard IS-IS PDU. The
+// IS-IS common header, and the PDU length and remaining lifetime fields of the
+// LSP header are prepended to the input. pduType is the PDU type to be
+// written to the common header (e.g., PDUTypeL2LSP), and idLength is the
+// system ID length written to the ID length field, where 0 indicates the
+// default length of 6 bytes. The PDU length is computed from the length of
+// the input, whereas the remaining lifetime is a placeholder value, since it
+// is not included in the input slice. Returns nil if the resulting PDU would
+// be too long for its length to be represented.
+func WrapAsStandardPDU(lspBytes []byte, pduType, idLength uint8) []byte {
+	sysIDLen := int(idLength)
+	switch idLength {
+	case 0:
+		sysIDLen = defaultIDLength
+	case 255:
+		// 255 indicates a null system ID.
+		sysIDLen = 0
+	}
+
+	// The LSP header consists of the common header, the PDU length (2
+	// bytes), remaining lifetime (2 bytes), LSP ID (system ID length + 2
+	// bytes), sequence number (4 bytes), checksum (2 bytes) and the
+	// P/ATT/OL/IS type flags (1 byte).
+	hdrLen := commonHeaderLen + 2 + 2 + sysIDLen + 2 + 4 + 2 + 1
+	pduLen := LSPIDOffset + len(lspBytes)
+	if pduLen > 0xFFFF {
+		return nil
+	}
+
+	pdu := make([]byte, LSPIDOffset, pduLen)
+	pdu[0] = isisIRPD
+	pdu[1] = uint8(hdrLen)
+	pdu[2] = isisVersion
+	pdu[3] = idLength
+	pdu[4] = pduType
+	pdu[5] = isisVersion
+	// pdu[6] is reserved, and pdu[7] is the maximum area addresses, where 0
+	// indicates the default of 3.
+	binary.BigEndian.PutUint16(pdu[8:10], uint16(pduLen))
+	binary.BigEndian.PutUint16(pdu[10:12], placeholderRemainingLifetime)
+
+	return append(pdu, lspBytes...)
+}
+
+// checkPDULength checks that the length of pdu, a standard IS-IS LSP PDU in
+// which the LSP ID begins at offset, matches the PDU length field of the LSP
+// header. Any bytes before offset-LSPIDOffset are not considered to be part of
+// the PDU. Returns an error if there are bytes after the declared end of the
+// PDU, or the PDU is shorter than its declared length.
+func checkPDULength(pdu []byte, offset int) error {
+	if offset < LSPIDOffset || len(pdu) < offset {
+		return fmt.Errorf("invalid offset %d for PDU of length %d", offset, len(pdu))
+	}
+
+	pduLen := int(binary.BigEndian.Uint16(pdu[offset-4 : offset-2]))
+	got := len(pdu) - (offset - LSPIDOffset)
+	switch {
+	case got > pduLen:
+		return fmt.Errorf("invalid PDU, %d trailing bytes after declared PDU length of %d bytes", got-pduLen, pduLen)
+	case got < pduLen:
+		return fmt.Errorf("invalid PDU, declared PDU length of %d bytes exceeds %d bytes supplied", pduLen, got)
+	}
+	return nil
+}
+
+// pduTypeMask is the mask of the PDU type field of the common header. The
+// three most significant bits of the field are reserved.
+const pduTypeMask uint8 = 0x1F
+
+// ISISPDUToLSP takes an input slice of bytes that contains a complete IS-IS
+// LSP PDU, starting at the intradomain routeing protocol discriminator of the
+// common header, as captured from the wire. The common header, along with the
+// PDU length and remaining lifetime fields of the LSP header are validated and
+// stored in the returned LSP, and the remainder of the PDU is parsed as per
+// ISISBytesToLSP. Any bytes after the declared PDU length are ignored, unless
+// WithStrictPDULength is supplied. Returns an error, and a false bool, if the
+// PDU is not an L1 or L2 LSP, or the header is malformed. The ParseOptions
+// supplied modify the behaviour of the parsing.
+func ISISPDUToLSP(pdu []byte, opts ...ParseOption) (*oc.Lsp, bool, error) {
+	if len(pdu) < LSPIDOffset {
+		return nil, false, fmt.Errorf("invalid PDU, need at least %d bytes for header, got %d bytes", LSPIDOffset, len(pdu))
+	}
+
+	if pdu[0] != isisIRPD {
+		return nil, false, fmt.Errorf("invalid PDU, unknown intradomain routeing protocol discriminator %#x", pdu[0])
+	}
+
+	var level oc.E_OpenconfigIsis_Lsp_PduType
+	switch pt := pdu[4] & pduTypeMask; pt {
+	case PDUTypeL1LSP:
+		level = oc.OpenconfigIsis_Lsp_PduType_LEVEL_1
+	case PDUTypeL2LSP:
+		level = oc.OpenconfigIsis_Lsp_PduType_LEVEL_2
+	default:
+		return nil, false, fmt.Errorf("invalid PDU, PDU type %d is not an LSP", pt)
+	}
+
+	// The LSP ID is parsed assuming the default system ID length.
+	if idLen := pdu[3]; idLen != 0 && idLen != defaultIDLength {
+		return nil, false, fmt.Errorf("invalid PDU, unsupported system ID length %d", idLen)
+	}
+
+	pduLen := binary.BigEndian.Uint16(pdu[8:10])
+	switch {
+	case int(pduLen) < LSPIDOffset:
+		return nil, false, fmt.Errorf("invalid PDU, declared PDU length of %d bytes is shorter than the header", pduLen)
+	case int(pduLen) > len(pdu):
+		return nil, false, fmt.Errorf("invalid PDU, declared PDU length of %d bytes exceeds %d bytes supplied", pduLen, len(pdu))
+	}
+
+	// Bytes after the end of the PDU, such as link-layer padding, are
+	// discarded, unless the PDU length is strictly checked.
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	end := int(pduLen)
+	if o.strictPDULength {
+		end = len(pdu)
+	}
+
+	// The remaining lifetime from the header takes precedence over any
+	// that is supplied in the options.
+	opts = append(opts[:len(opts):len(opts)], WithRemainingLifetime(binary.BigEndian.Uint16(pdu[10:12])))
+	lsp, ok, err := ISISBytesToLSP(pdu[:end], LSPIDOffset, opts...)
+	if !ok {
+		return nil, false, err
+	}
+
+	lsp.PduType = level
+	lsp.PduLength = ygot.Uint16(pduLen)
+	lsp.Version = ygot.Uint8(pdu[2])
+	lsp.Version2 = ygot.Uint8(pdu[5])
+	lsp.IdLength = ygot.Uint8(pdu[3])
+	lsp.MaximumAreaAddresses = ygot.Uint8(pdu[7])
+
+	return lsp, true, err
+}
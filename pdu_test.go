@@ -0,0 +1,188 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+func TestWrapAsStandardPDU(t *testing.T) {
+	tests := []struct {
+		name          string
+		inBytes       []byte
+		inPDUType     uint8
+		inIDLength    uint8
+		wantHeaderLen uint8
+	}{{
+		name:          "level 2 LSP with default ID length",
+		inBytes:       exampleLSP1,
+		inPDUType:     PDUTypeL2LSP,
+		wantHeaderLen: 27,
+	}, {
+		name:          "level 1 LSP with explicit ID length",
+		inBytes:       exampleLSP2,
+		inPDUType:     PDUTypeL1LSP,
+		inIDLength:    6,
+		wantHeaderLen: 27,
+	}}
+
+	for _, tt := range tests {
+		got := WrapAsStandardPDU(tt.inBytes, tt.inPDUType, tt.inIDLength)
+
+		wantCommon := []byte{isisIRPD, tt.wantHeaderLen, 1, tt.inIDLength, tt.inPDUType, 1, 0, 0}
+		if !bytes.Equal(got[:commonHeaderLen], wantCommon) {
+			t.Errorf("%s: WrapAsStandardPDU(...): did not get expected common header, got: %v, want: %v", tt.name, got[:commonHeaderLen], wantCommon)
+		}
+
+		if l := int(binary.BigEndian.Uint16(got[8:10])); l != len(got) || l != len(tt.inBytes)+LSPIDOffset {
+			t.Errorf("%s: WrapAsStandardPDU(...): did not get expected PDU length, got: %d, want: %d", tt.name, l, len(got))
+		}
+
+		if !bytes.Equal(got[LSPIDOffset:], tt.inBytes) {
+			t.Errorf("%s: WrapAsStandardPDU(...): LSP contents were modified, got: %v, want: %v", tt.name, got[LSPIDOffset:], tt.inBytes)
+		}
+
+		// Round-trip the wrapped PDU, such that it is parsed from the
+		// start of the full header.
+		want, _, wantErr := ISISBytesToLSP(tt.inBytes, 0)
+		gotLSP, ok, err := ISISBytesToLSP(got, LSPIDOffset)
+		if !ok {
+			t.Errorf("%s: ISISBytesToLSP(WrapAsStandardPDU(...)): could not parse wrapped PDU: %v", tt.name, err)
+			continue
+		}
+
+		if (err == nil) != (wantErr == nil) {
+			t.Errorf("%s: ISISBytesToLSP(WrapAsStandardPDU(...)): did not get expected error, got: %v, want: %v", tt.name, err, wantErr)
+		}
+
+		if diff := pretty.Compare(gotLSP, want); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(WrapAsStandardPDU(...)): did not get expected LSP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestWrapAsStandardPDUTooLong(t *testing.T) {
+	if got := WrapAsStandardPDU(make([]byte, 0xFFFF), PDUTypeL2LSP, 0); got != nil {
+		t.Errorf("WrapAsStandardPDU(<65535 bytes>): got PDU of length %d, want: nil", len(got))
+	}
+}
+
+// exampleL2PDU is a level 2 LSP PDU, as captured from the wire, including the
+// IS-IS common header. The LSP it carries is exampleLSP1, with a remaining
+// lifetime of 1187 seconds.
+var exampleL2PDU = mustDecodeLSPHex("83:1b:01:00:14:01:00:00:00:e1:04:a3:00:00:40:00:ce:39:00:00:00:00:14:26:27:7f:03:01:0e:0d:39:75:2f:01:00:00:14:00:00:90:00:00:01:0e:02:05:d4:81:02:cc:8e:86:04:0a:f4:a8:1f:84:04:0a:f4:a8:1f:89:0e:72:65:30:2d:70:72:30:35:2e:73:71:6c:38:38:16:4f:00:00:40:00:ce:39:02:00:00:1e:44:06:04:c0:a8:c9:24:04:08:00:00:01:43:00:00:00:00:0b:20:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:4e:ee:6b:28:0a:04:4e:ee:6b:28:09:04:4f:15:02:f9:03:04:00:00:00:00:ec:24:00:00:00:00:00:80:26:07:f8:b0:00:00:00:00:00:00:00:03:40:00:ce:39:00:00:00:1e:00:40:20:01:48:60:c0:a8:c9:20:87:12:00:00:00:00:20:0a:f4:a8:1f:00:00:00:1e:1b:c0:a8:c9:20:f2:05:0a:f4:a8:1f:01")
+
+func TestISISPDUToLSP(t *testing.T) {
+	withHeader := func(i int, v byte) []byte {
+		b := append([]byte{}, exampleL2PDU...)
+		b[i] = v
+		return b
+	}
+
+	tests := []struct {
+		name             string
+		inBytes          []byte
+		inOpts           []ParseOption
+		wantPDUType      oc.E_OpenconfigIsis_Lsp_PduType
+		wantErrSubstring string
+	}{{
+		name:        "captured level 2 LSP",
+		inBytes:     exampleL2PDU,
+		wantPDUType: oc.OpenconfigIsis_Lsp_PduType_LEVEL_2,
+	}, {
+		name:        "level 1 LSP",
+		inBytes:     withHeader(4, PDUTypeL1LSP),
+		wantPDUType: oc.OpenconfigIsis_Lsp_PduType_LEVEL_1,
+	}, {
+		name:        "padding after PDU",
+		inBytes:     append(append([]byte{}, exampleL2PDU...), 0, 0, 0),
+		wantPDUType: oc.OpenconfigIsis_Lsp_PduType_LEVEL_2,
+	}, {
+		name:             "padding after PDU with strict length",
+		inBytes:          append(append([]byte{}, exampleL2PDU...), 0, 0, 0),
+		inOpts:           []ParseOption{WithStrictPDULength(true)},
+		wantErrSubstring: "3 trailing bytes",
+	}, {
+		name:             "wrong discriminator",
+		inBytes:          withHeader(0, 0x82),
+		wantErrSubstring: "unknown intradomain routeing protocol discriminator 0x82",
+	}, {
+		name:             "hello PDU type",
+		inBytes:          withHeader(4, 17),
+		wantErrSubstring: "PDU type 17 is not an LSP",
+	}, {
+		name:             "unsupported ID length",
+		inBytes:          withHeader(3, 8),
+		wantErrSubstring: "unsupported system ID length 8",
+	}, {
+		name:             "truncated PDU",
+		inBytes:          exampleL2PDU[:len(exampleL2PDU)-1],
+		wantErrSubstring: "exceeds 224 bytes supplied",
+	}, {
+		name:             "PDU length shorter than header",
+		inBytes:          withHeader(9, 4),
+		wantErrSubstring: "declared PDU length of 4 bytes",
+	}, {
+		name:             "short header",
+		inBytes:          exampleL2PDU[:4],
+		wantErrSubstring: "need at least 12 bytes",
+	}}
+
+	want, _, wantErr := ISISBytesToLSP(exampleLSP1, 0)
+	for _, tt := range tests {
+		got, ok, err := ISISPDUToLSP(tt.inBytes, tt.inOpts...)
+		if tt.wantErrSubstring != "" {
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Errorf("%s: ISISPDUToLSP(...): did not get expected error, %s", tt.name, diff)
+			}
+			if ok || got != nil {
+				t.Errorf("%s: ISISPDUToLSP(...): got parsed LSP for rejected input, got: %v, want: nil", tt.name, got)
+			}
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: ISISPDUToLSP(...): could not parse PDU: %v", tt.name, err)
+			continue
+		}
+		if (err == nil) != (wantErr == nil) {
+			t.Errorf("%s: ISISPDUToLSP(...): did not get expected error, got: %v, want: %v", tt.name, err, wantErr)
+		}
+
+		if got.PduType != tt.wantPDUType {
+			t.Errorf("%s: ISISPDUToLSP(...): did not get expected PDU type, got: %v, want: %v", tt.name, got.PduType, tt.wantPDUType)
+		}
+		if l := got.PduLength; l == nil || *l != 225 {
+			t.Errorf("%s: ISISPDUToLSP(...): did not get expected PDU length, got: %v, want: 225", tt.name, l)
+		}
+		if l := got.RemainingLifetime; l == nil || *l != 1187 {
+			t.Errorf("%s: ISISPDUToLSP(...): did not get expected remaining lifetime, got: %v, want: 1187", tt.name, l)
+		}
+
+		// The LSP contents are the same as those parsed from the LSP
+		// without its header.
+		got.PduType = oc.OpenconfigIsis_Lsp_PduType_UNSET
+		got.PduLength, got.RemainingLifetime, got.Version, got.Version2, got.IdLength, got.MaximumAreaAddresses = nil, nil, nil, nil, nil, nil
+		if diff := pretty.Compare(got, want); diff != "" {
+			t.Errorf("%s: ISISPDUToLSP(...): did not get expected LSP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+const (
+	// poiTLVType is the type of the Purge Originator Identification TLV,
+	// defined in RFC6232.
+	poiTLVType uint8 = 13
+	// isTypeL1 and isTypeL2 are the values of the IS type bits of the LSP
+	// flags for level 1 and level 2 intermediate systems.
+	isTypeL1 uint8 = 0x01
+	isTypeL2 uint8 = 0x03
+)
+
+// PurgeArgs specifies the contents of a purge that is generated by
+// SynthesizePurge.
+type PurgeArgs struct {
+	// PDUType is the PDU type of the purge, PDUTypeL1LSP or PDUTypeL2LSP.
+	// If unset, a level 2 LSP is generated.
+	PDUType uint8
+	// SequenceNumber is the sequence number of the LSP that is being
+	// purged, which is carried in the purge. If unset, a sequence number
+	// of 1 is used, since 0 is not a valid LSP sequence number.
+	SequenceNumber uint32
+	// Originator is the system ID, in canonical format (xxxx.yyyy.zzzz),
+	// of the system originating the purge. If set, a Purge Originator
+	// Identification TLV (RFC6232) is included in the purge, otherwise the
+	// purge contains no TLVs.
+	Originator string
+}
+
+// SynthesizePurge generates a purge of the LSP with the supplied LSP ID (in
+// the format xxxx.yyyy.zzzz.aa-bb) as a standard IS-IS LSP PDU, including the
+// common header. The purge has a remaining lifetime of zero, a TLV section
+// that is empty or contains only a Purge Originator Identification TLV, and a
+// valid checksum. It is intended for testing the handling of withdrawn LSPs.
+// Returns an error if the arguments are invalid.
+func SynthesizePurge(lspID string, args PurgeArgs) ([]byte, error) {
+	id, err := lspIDToBytes(lspID)
+	if err != nil {
+		return nil, err
+	}
+
+	pduType, isType := args.PDUType, isTypeL2
+	switch pduType {
+	case 0:
+		pduType = PDUTypeL2LSP
+	case PDUTypeL1LSP:
+		isType = isTypeL1
+	case PDUTypeL2LSP:
+	default:
+		return nil, fmt.Errorf("invalid PDU type for purge: %d", pduType)
+	}
+
+	seq := args.SequenceNumber
+	if seq == 0 {
+		seq = 1
+	}
+
+	// The LSP is encoded starting at the LSP ID, as is handled by
+	// ISISBytesToLSP, such that it can then be wrapped in the common header.
+	lsp := make([]byte, 0, 24)
+	lsp = append(lsp, id...)
+	lsp = append(lsp, 0, 0, 0, 0, 0, 0, isType)
+	binary.BigEndian.PutUint32(lsp[8:12], seq)
+
+	if args.Originator != "" {
+		sysID, err := canonicalHexToBytes(args.Originator, defaultIDLength)
+		if err != nil {
+			return nil, fmt.Errorf("invalid purge originator: %v", err)
+		}
+		// The POI TLV consists of the number of system IDs, followed by
+		// the system IDs.
+		lsp = append(lsp, poiTLVType, uint8(1+len(sysID)), 1)
+		lsp = append(lsp, sysID...)
+	}
+
+	binary.BigEndian.PutUint16(lsp[lspChecksumOffset:lspChecksumOffset+2], fletcherChecksum(lsp, lspChecksumOffset))
+
+	pdu := WrapAsStandardPDU(lsp, pduType, 0)
+	binary.BigEndian.PutUint16(pdu[LSPIDOffset-2:LSPIDOffset], 0)
+	return pdu, nil
+}
+
+// IsPurge returns true if the supplied standard IS-IS LSP PDU, including the
+// common header, is a purge - i.e., it has a remaining lifetime of zero. The
+// TLVs of the PDU are not considered, such that a purge that retains an
+// authentication TLV (RFC5304), or is otherwise not empty, is a purge.
+// Returns an error if the PDU is not an LSP PDU.
+func IsPurge(pdu []byte) (bool, error) {
+	if len(pdu) < LSPIDOffset {
+		return false, fmt.Errorf("invalid LSP PDU, need at least %d bytes, got %d bytes", LSPIDOffset, len(pdu))
+	}
+	if pdu[0] != isisIRPD {
+		return false, fmt.Errorf("invalid LSP PDU, unknown protocol discriminator: %#x", pdu[0])
+	}
+	if t := pdu[4] & pduTypeMask; t != PDUTypeL1LSP && t != PDUTypeL2LSP {
+		return false, fmt.Errorf("invalid LSP PDU, PDU type %d is not an LSP", t)
+	}
+	return binary.BigEndian.Uint16(pdu[LSPIDOffset-2:LSPIDOffset]) == 0, nil
+}
+
+// IsPurgedLSP returns true if the parsed LSP is a purge - i.e., its remaining
+// lifetime is known to be zero, as is the case when it is parsed from a PDU
+// using ISISPDUToLSP, or supplied using WithRemainingLifetime. A purge is
+// identified by its remaining lifetime alone, such that an LSP that retains a
+// stale TLV section is still a purge, whereas an LSP with no TLVs that has not
+// expired is not.
+func IsPurgedLSP(lsp *oc.Lsp) bool {
+	return lsp != nil && lsp.RemainingLifetime != nil && *lsp.RemainingLifetime == 0
+}
+
+// PurgeOriginator is the contents of the Purge Originator Identification (POI)
+// TLV of a purge, defined in RFC6232.
+type PurgeOriginator struct {
+	// Originator is the system ID, in canonical format, of the system that
+	// inserted the POI TLV - i.e., the system that originated the purge, or
+	// that propagated a purge that was received without a POI TLV.
+	Originator string
+	// ReceivedFrom is the system ID, in canonical format, of the neighbor
+	// from which a purge without a POI TLV was received. It is empty where
+	// the purge was originated by Originator.
+	ReceivedFrom string
+}
+
+// parsePOI parses the value of a POI TLV, which consists of the number of
+// system IDs carried, 1 or 2, followed by the system IDs. System IDs are
+// assumed to be 6 bytes long. Returns an error if the value is malformed.
+func parsePOI(b []byte) (*PurgeOriginator, error) {
+	if len(b) < 1 {
+		return nil, fmt.Errorf("invalid length POI TLV, no number of system IDs")
+	}
+
+	n := int(b[0])
+	if n != 1 && n != 2 {
+		return nil, fmt.Errorf("invalid POI TLV, number of system IDs must be 1 or 2, got %d", n)
+	}
+	if want := 1 + n*defaultIDLength; len(b) != want {
+		return nil, fmt.Errorf("invalid length POI TLV with %d system IDs, %d != %d", n, len(b), want)
+	}
+
+	p := &PurgeOriginator{
+		Originator: canonicalHexString(b[1 : 1+defaultIDLength]),
+	}
+	if n == 2 {
+		p.ReceivedFrom = canonicalHexString(b[1+defaultIDLength:])
+	}
+	return p, nil
+}
+
+// PurgeOriginatorID returns the contents of the POI TLV of the LSP, decoded
+// from its undefined TLVs. Returns nil if the LSP does not contain a POI TLV,
+// or an error if it cannot be decoded.
+func PurgeOriginatorID(lsp *oc.Lsp) (*PurgeOriginator, error) {
+	u := lsp.GetUndefinedTlv(poiTLVType)
+	if u == nil {
+		return nil, nil
+	}
+	return parsePOI(u.Value)
+}
@@ -0,0 +1,363 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestSynthesizePurge(t *testing.T) {
+	tests := []struct {
+		name             string
+		inLSPID          string
+		inArgs           PurgeArgs
+		wantPDUType      uint8
+		wantLSP          *oc.Lsp
+		wantPOI          []byte
+		wantErrSubstring string
+	}{{
+		name:        "level 2 purge without POI",
+		inLSPID:     "0000.4000.ce39.00-00",
+		inArgs:      PurgeArgs{SequenceNumber: 0x1426},
+		wantPDUType: PDUTypeL2LSP,
+		wantLSP: &oc.Lsp{
+			LspId:          ygot.String("0000.4000.ce39.00-00"),
+			SequenceNumber: ygot.Uint32(0x1426),
+		},
+	}, {
+		name:    "level 1 purge with POI",
+		inLSPID: "1920.0000.2001.02-01",
+		inArgs: PurgeArgs{
+			PDUType:        PDUTypeL1LSP,
+			SequenceNumber: 42,
+			Originator:     "1920.0000.2002",
+		},
+		wantPDUType: PDUTypeL1LSP,
+		wantLSP: &oc.Lsp{
+			LspId:          ygot.String("1920.0000.2001.02-01"),
+			SequenceNumber: ygot.Uint32(42),
+		},
+		wantPOI: []byte{1, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02},
+	}, {
+		name:        "default sequence number",
+		inLSPID:     "0000.4000.ce39.00-00",
+		wantPDUType: PDUTypeL2LSP,
+		wantLSP: &oc.Lsp{
+			LspId:          ygot.String("0000.4000.ce39.00-00"),
+			SequenceNumber: ygot.Uint32(1),
+		},
+	}, {
+		name:             "invalid LSP ID",
+		inLSPID:          "0000.4000.ce39.00",
+		wantErrSubstring: "no fragment number",
+	}, {
+		name:             "invalid originator",
+		inLSPID:          "0000.4000.ce39.00-00",
+		inArgs:           PurgeArgs{Originator: "0000.4000"},
+		wantErrSubstring: "invalid purge originator",
+	}, {
+		name:             "invalid PDU type",
+		inLSPID:          "0000.4000.ce39.00-00",
+		inArgs:           PurgeArgs{PDUType: 24},
+		wantErrSubstring: "invalid PDU type",
+	}}
+
+	for _, tt := range tests {
+		got, err := SynthesizePurge(tt.inLSPID, tt.inArgs)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: SynthesizePurge(%s, %v): did not get expected error, %s", tt.name, tt.inLSPID, tt.inArgs, diff)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if got[4] != tt.wantPDUType {
+			t.Errorf("%s: SynthesizePurge(%s, %v): did not get expected PDU type, got: %d, want: %d", tt.name, tt.inLSPID, tt.inArgs, got[4], tt.wantPDUType)
+		}
+
+		if l := int(binary.BigEndian.Uint16(got[8:10])); l != len(got) {
+			t.Errorf("%s: SynthesizePurge(%s, %v): did not get expected PDU length, got: %d, want: %d", tt.name, tt.inLSPID, tt.inArgs, l, len(got))
+		}
+
+		purge, err := IsPurge(got)
+		if err != nil || !purge {
+			t.Errorf("%s: IsPurge(SynthesizePurge(%s, %v)): got: %v, %v, want: true, nil", tt.name, tt.inLSPID, tt.inArgs, purge, err)
+		}
+
+		body := got[LSPIDOffset:]
+		if ck, want := binary.BigEndian.Uint16(body[lspChecksumOffset:lspChecksumOffset+2]), fletcherChecksum(body, lspChecksumOffset); ck != want {
+			t.Errorf("%s: SynthesizePurge(%s, %v): did not get valid checksum, got: %#04x, want: %#04x", tt.name, tt.inLSPID, tt.inArgs, ck, want)
+		}
+
+		lsp, ok, err := ISISBytesToLSP(got, LSPIDOffset)
+		if !ok || err != nil {
+			t.Errorf("%s: ISISBytesToLSP(SynthesizePurge(%s, %v)): could not parse purge, ok: %v, err: %v", tt.name, tt.inLSPID, tt.inArgs, ok, err)
+			continue
+		}
+
+		var gotPOI []byte
+		if err := ParseTLVStream(got, LSPIDOffset, func(tlvType uint8, value []byte) error {
+			if tlvType != poiTLVType {
+				return fmt.Errorf("unexpected TLV type %d", tlvType)
+			}
+			gotPOI = value
+			return nil
+		}); err != nil {
+			t.Errorf("%s: ParseTLVStream(SynthesizePurge(%s, %v)): got unexpected error: %v", tt.name, tt.inLSPID, tt.inArgs, err)
+		}
+
+		if !bytes.Equal(gotPOI, tt.wantPOI) {
+			t.Errorf("%s: SynthesizePurge(%s, %v): did not get expected POI TLV, got: %v, want: %v", tt.name, tt.inLSPID, tt.inArgs, gotPOI, tt.wantPOI)
+		}
+
+		if len(lsp.Tlv) != 0 {
+			t.Errorf("%s: ISISBytesToLSP(SynthesizePurge(%s, %v)): got unexpected TLVs: %v", tt.name, tt.inLSPID, tt.inArgs, lsp.Tlv)
+		}
+
+		// Only the LSP ID and sequence number are determined by the
+		// arguments to SynthesizePurge.
+		gotLSP := &oc.Lsp{
+			LspId:          lsp.LspId,
+			SequenceNumber: lsp.SequenceNumber,
+		}
+		if diff := pretty.Compare(gotLSP, tt.wantLSP); diff != "" {
+			t.Errorf("%s: ISISBytesToLSP(SynthesizePurge(%s, %v)): did not get expected LSP, diff(-got,+want):\n%s", tt.name, tt.inLSPID, tt.inArgs, diff)
+		}
+	}
+}
+
+func TestIsPurge(t *testing.T) {
+	tests := []struct {
+		name             string
+		in               []byte
+		want             bool
+		wantErrSubstring string
+	}{{
+		name: "non-purge",
+		in:   WrapAsStandardPDU(exampleLSP1, PDUTypeL2LSP, 0),
+	}, {
+		name: "reserved bits set in PDU type",
+		in:   WrapAsStandardPDU(exampleLSP1, 0xE0|PDUTypeL2LSP, 0),
+	}, {
+		name:             "not an LSP",
+		in:               WrapAsStandardPDU(exampleLSP1, 24, 0),
+		wantErrSubstring: "is not an LSP",
+	}, {
+		name:             "invalid discriminator",
+		in:               append([]byte{0x82}, WrapAsStandardPDU(exampleLSP1, PDUTypeL2LSP, 0)[1:]...),
+		wantErrSubstring: "unknown protocol discriminator",
+	}, {
+		name:             "too short",
+		in:               []byte{0x83, 27, 1, 0},
+		wantErrSubstring: "need at least 12 bytes",
+	}}
+
+	for _, tt := range tests {
+		got, err := IsPurge(tt.in)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: IsPurge(%v): did not get expected error, %s", tt.name, tt.in, diff)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: IsPurge(%v): got: %v, want: %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsPurgedLSP(t *testing.T) {
+	purge, err := SynthesizePurge("1920.0000.2001.00-00", PurgeArgs{})
+	if err != nil {
+		t.Fatalf("SynthesizePurge(...): got unexpected error: %v", err)
+	}
+
+	// stalePurge is exampleL2PDU, with its TLVs retained, but a remaining
+	// lifetime of zero.
+	stalePurge := append([]byte{}, exampleL2PDU...)
+	binary.BigEndian.PutUint16(stalePurge[LSPIDOffset-2:LSPIDOffset], 0)
+
+	// emptyLSP is an LSP that has not expired, and contains no TLVs.
+	emptyLSP := []byte{0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 1, 0, 0, 0x03}
+
+	tests := []struct {
+		name  string
+		inPDU []byte
+		want  bool
+	}{{
+		name:  "purge",
+		inPDU: purge,
+		want:  true,
+	}, {
+		name:  "purge with stale TLVs",
+		inPDU: stalePurge,
+		want:  true,
+	}, {
+		name:  "non-purge with no TLVs",
+		inPDU: WrapAsStandardPDU(emptyLSP, PDUTypeL2LSP, 0),
+	}, {
+		name:  "non-purge",
+		inPDU: exampleL2PDU,
+	}}
+
+	for _, tt := range tests {
+		lsp, ok, err := ISISPDUToLSP(tt.inPDU)
+		if !ok {
+			t.Errorf("%s: ISISPDUToLSP(...): could not parse PDU: %v", tt.name, err)
+			continue
+		}
+		if got := IsPurgedLSP(lsp); got != tt.want {
+			t.Errorf("%s: IsPurgedLSP(%v): got: %v, want: %v", tt.name, lsp, got, tt.want)
+		}
+	}
+
+	lsp, _, _ := ISISBytesToLSP(emptyLSP, 0)
+	if IsPurgedLSP(lsp) {
+		t.Errorf("IsPurgedLSP(%v): got: true for LSP without remaining lifetime, want: false", lsp)
+	}
+}
+
+func TestAuthenticatedPurge(t *testing.T) {
+	// lsp is a purge that retains an HMAC-MD5 authentication TLV, and whose
+	// checksum has not been recomputed after its contents were removed.
+	lsp := []byte{
+		0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0, 0, 0, 2, 0, 0, 0x03,
+		10, 17,
+		54, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F, 0x10,
+	}
+	pdu := WrapAsStandardPDU(lsp, PDUTypeL2LSP, 0)
+	binary.BigEndian.PutUint16(pdu[LSPIDOffset-2:LSPIDOffset], 0)
+
+	isPurge, err := IsPurge(pdu)
+	if err != nil {
+		t.Fatalf("IsPurge(%v): got unexpected error: %v", pdu, err)
+	}
+	if !isPurge {
+		t.Errorf("IsPurge(%v): got: false, want: true", pdu)
+	}
+
+	got, ok, err := ISISPDUToLSP(pdu, WithChecksumCheck(true))
+	if !ok || err != nil {
+		t.Fatalf("ISISPDUToLSP(%v, WithChecksumCheck(true)): got unexpected error, ok: %v, err: %v", pdu, ok, err)
+	}
+	if !IsPurgedLSP(got) {
+		t.Errorf("IsPurgedLSP(%v): got: false, want: true", got)
+	}
+	if a, err := LSPAuthentication(lsp, 0); err != nil || a == nil || a.Type != 54 || a.ValueLength != 16 {
+		t.Errorf("LSPAuthentication(%v): did not get expected HMAC-MD5 authentication, got: %+v, err: %v", lsp, a, err)
+	}
+
+	// The same LSP that has not been purged has its checksum checked.
+	binary.BigEndian.PutUint16(pdu[LSPIDOffset-2:LSPIDOffset], 1200)
+	if _, _, err := ISISPDUToLSP(pdu, WithChecksumCheck(true)); err == nil {
+		t.Errorf("ISISPDUToLSP(%v, WithChecksumCheck(true)): did not get expected checksum error for non-purge", pdu)
+	}
+}
+
+func TestPurgeOriginatorID(t *testing.T) {
+	// poiLSP returns an LSP containing a POI TLV with the value v.
+	poiLSP := func(v []byte) *oc.Lsp {
+		return &oc.Lsp{
+			UndefinedTlv: map[uint8]*oc.Lsp_UndefinedTlv{
+				poiTLVType: {
+					Type:   ygot.Uint8(poiTLVType),
+					Length: ygot.Uint8(uint8(len(v))),
+					Value:  oc.Binary(v),
+				},
+			},
+		}
+	}
+
+	synthesized, err := SynthesizePurge("0000.4000.ce39.00-00", PurgeArgs{Originator: "1920.0000.2002"})
+	if err != nil {
+		t.Fatalf("SynthesizePurge(...): got unexpected error: %v", err)
+	}
+	synthesizedLSP, _, err := ISISBytesToLSP(synthesized, LSPIDOffset)
+	if err != nil {
+		t.Fatalf("ISISBytesToLSP(SynthesizePurge(...)): got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		want             *PurgeOriginator
+		wantErrSubstring string
+	}{{
+		name:  "originated purge",
+		inLSP: poiLSP([]byte{1, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02}),
+		want: &PurgeOriginator{
+			Originator: "1920.0000.2002",
+		},
+	}, {
+		name: "propagated purge",
+		inLSP: poiLSP([]byte{
+			2,
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x02,
+			0x00, 0x00, 0x40, 0x00, 0xce, 0x39,
+		}),
+		want: &PurgeOriginator{
+			Originator:   "1920.0000.2002",
+			ReceivedFrom: "0000.4000.ce39",
+		},
+	}, {
+		name:  "synthesized purge",
+		inLSP: synthesizedLSP,
+		want: &PurgeOriginator{
+			Originator: "1920.0000.2002",
+		},
+	}, {
+		name:  "no POI TLV",
+		inLSP: &oc.Lsp{},
+	}, {
+		name:             "empty POI TLV",
+		inLSP:            poiLSP([]byte{}),
+		wantErrSubstring: "no number of system IDs",
+	}, {
+		name:             "invalid number of system IDs",
+		inLSP:            poiLSP([]byte{3, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02}),
+		wantErrSubstring: "must be 1 or 2",
+	}, {
+		name:             "missing second system ID",
+		inLSP:            poiLSP([]byte{2, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02}),
+		wantErrSubstring: "invalid length POI TLV with 2 system IDs",
+	}, {
+		name: "trailing bytes",
+		inLSP: poiLSP([]byte{
+			1,
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x02,
+			0x00, 0x00, 0x40, 0x00, 0xce, 0x39, 0x00, 0x00,
+		}),
+		wantErrSubstring: "invalid length POI TLV with 1 system IDs",
+	}}
+
+	for _, tt := range tests {
+		got, err := PurgeOriginatorID(tt.inLSP)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: PurgeOriginatorID(%v): did not get expected error, %s", tt.name, tt.inLSP, diff)
+		}
+
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: PurgeOriginatorID(%v): did not get expected POI, diff(-got,+want):\n%s", tt.name, tt.inLSP, diff)
+		}
+	}
+}
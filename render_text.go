@@ -0,0 +1,344 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// textPrefixSID is a prefix SID that is rendered by RenderText, abstracting
+// the differences between the IPv4 and IPv6 reachability TLV types.
+type textPrefixSID struct {
+	value     uint32
+	algorithm uint8
+	flags     []oc.E_OpenconfigIsis_PrefixSid_Flags
+}
+
+// RenderText returns a human-readable, multi-line description of the LSP, in
+// a format resembling the verbose output of an IS-IS database on a router. The
+// LSP header fields are followed by each TLV, sorted by name, with the
+// contents of the TLV indented beneath it. Bandwidths, which are stored in
+// the model as IEEE floating point bytes, are rendered as decoded values in
+// bytes per second. TLVs whose contents are not rendered are listed by name,
+// and undefined TLVs are listed by type and length. Returns an error if the
+// LSP does not have an LSP ID, or contains a bandwidth that cannot be decoded.
+func RenderText(lsp *oc.Lsp) (string, error) {
+	if lsp == nil || lsp.LspId == nil {
+		return "", fmt.Errorf("cannot render LSP without LSP ID")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "LSP ID: %s\n", *lsp.LspId)
+	fmt.Fprintf(&b, "  Sequence number: 0x%08x\n", uint32Value(lsp.SequenceNumber))
+	if lsp.Checksum != nil {
+		fmt.Fprintf(&b, "  Checksum: 0x%04x\n", *lsp.Checksum)
+	}
+	if lsp.RemainingLifetime != nil {
+		fmt.Fprintf(&b, "  Remaining lifetime: %d\n", *lsp.RemainingLifetime)
+	}
+	if lsp.PduLength != nil {
+		fmt.Fprintf(&b, "  PDU length: %d\n", *lsp.PduLength)
+	}
+	fmt.Fprintf(&b, "  IS type: %d\n", uint8Value(lsp.IsType))
+	if len(lsp.Flags) != 0 {
+		var fs []string
+		for _, f := range lsp.Flags {
+			fs = append(fs, f.String())
+		}
+		fmt.Fprintf(&b, "  Flags: %s\n", strings.Join(fs, " "))
+	}
+
+	var types []oc.E_OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE
+	for t := range lsp.Tlv {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, t := range types {
+		tlv := lsp.Tlv[t]
+		fmt.Fprintf(&b, "  TLV %s:\n", t)
+		switch t {
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_AREA_ADDRESSES:
+			if c := tlv.GetAreaAddress(); c != nil {
+				for _, a := range c.Address {
+					fmt.Fprintf(&b, "    Area address: %s\n", a)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_NLPID:
+			if c := tlv.GetNlpid(); c != nil {
+				for _, n := range c.Nlpid {
+					fmt.Fprintf(&b, "    NLPID: %s\n", n)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_DYNAMIC_NAME:
+			if c := tlv.GetHostname(); c != nil {
+				for _, h := range c.Hostname {
+					fmt.Fprintf(&b, "    Hostname: %s\n", h)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_INTERFACE_ADDRESSES:
+			if c := tlv.GetIpv4InterfaceAddresses(); c != nil {
+				for _, a := range c.Address {
+					fmt.Fprintf(&b, "    IPv4 interface address: %s\n", a)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_INTERFACE_ADDRESSES:
+			if c := tlv.GetIpv6InterfaceAddresses(); c != nil {
+				for _, a := range c.Address {
+					fmt.Fprintf(&b, "    IPv6 interface address: %s\n", a)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV4_TE_ROUTER_ID:
+			if c := tlv.GetIpv4TeRouterId(); c != nil {
+				for _, a := range c.RouterId {
+					fmt.Fprintf(&b, "    TE router ID: %s\n", a)
+				}
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY:
+			if err := renderExtendedISReachText(&b, tlv.GetExtendedIsReachability()); err != nil {
+				return "", err
+			}
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IPV4_REACHABILITY:
+			renderExtendedIPReachText(&b, tlv.GetExtendedIpv4Reachability())
+		case oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_IPV6_REACHABILITY:
+			renderIPv6ReachText(&b, tlv.GetIpv6Reachability())
+		}
+	}
+
+	var undef []int
+	for t := range lsp.UndefinedTlv {
+		undef = append(undef, int(t))
+	}
+	sort.Ints(undef)
+	for _, t := range undef {
+		fmt.Fprintf(&b, "  Undefined TLV %d: length %d\n", t, len(lsp.UndefinedTlv[uint8(t)].Value))
+	}
+
+	return b.String(), nil
+}
+
+// renderExtendedISReachText writes the neighbors of the extended IS
+// reachability TLV r to b, sorted by neighbor ID, with the sub-TLVs of each
+// instance of the neighbor indented beneath it.
+func renderExtendedISReachText(b *strings.Builder, r *oc.Lsp_Tlv_ExtendedIsReachability) error {
+	if r == nil {
+		return nil
+	}
+
+	var ids []string
+	for id := range r.Neighbor {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		n := r.Neighbor[id]
+		var insts []uint64
+		for i := range n.Instance {
+			insts = append(insts, i)
+		}
+		sort.Slice(insts, func(i, j int) bool { return insts[i] < insts[j] })
+
+		for _, i := range insts {
+			inst := n.Instance[i]
+			fmt.Fprintf(b, "    Neighbor: %s, metric: %d\n", id, uint32Value(inst.Metric))
+			if err := renderISReachSubTLVsText(b, inst); err != nil {
+				return fmt.Errorf("cannot render neighbor %s, %v", id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// renderISReachSubTLVsText writes the sub-TLVs of the extended IS reachability
+// neighbor instance inst to b.
+func renderISReachSubTLVsText(b *strings.Builder, inst *oc.Lsp_Tlv_ExtendedIsReachability_Neighbor_Instance) error {
+	bandwidth := func(name string, v []byte) error {
+		f, err := binaryToFloat32(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s, %v", strings.ToLower(name), err)
+		}
+		fmt.Fprintf(b, "      %s: %g bytes/s\n", name, f)
+		return nil
+	}
+
+	s := inst.GetSubtlv
+	if a := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_INTERFACE_ADDRESS).GetIpv4InterfaceAddress(); a != nil {
+		for _, addr := range a.Address {
+			fmt.Fprintf(b, "      IPv4 interface address: %s\n", addr)
+		}
+	}
+	if a := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_IPV4_NEIGHBOR_ADDRESS).GetIpv4NeighborAddress(); a != nil {
+		for _, addr := range a.Address {
+			fmt.Fprintf(b, "      IPv4 neighbor address: %s\n", addr)
+		}
+	}
+	if g := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADMIN_GROUP).GetAdminGroup(); g != nil {
+		for _, ag := range g.AdminGroup {
+			fmt.Fprintf(b, "      Admin group: 0x%08x\n", ag)
+		}
+	}
+	if bw := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH).GetMaxLinkBandwidth(); bw != nil {
+		if err := bandwidth("Maximum link bandwidth", bw.Bandwidth); err != nil {
+			return err
+		}
+	}
+	if bw := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_RESERVABLE_BANDWIDTH).GetMaxReservableLinkBandwidth(); bw != nil {
+		if err := bandwidth("Maximum reservable bandwidth", bw.Bandwidth); err != nil {
+			return err
+		}
+	}
+	if st := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_UNRESERVED_BANDWIDTH); st != nil {
+		for pri := uint8(0); pri < 8; pri++ {
+			if p := st.SetupPriority[pri]; p != nil {
+				if err := bandwidth(fmt.Sprintf("Unreserved bandwidth, priority %d", pri), p.Bandwidth); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if bw := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_RESIDUAL_BANDWIDTH).GetResidualBandwidth(); bw != nil {
+		if err := bandwidth("Residual bandwidth", bw.Bandwidth); err != nil {
+			return err
+		}
+	}
+	if m := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_TE_DEFAULT_METRIC).GetTeDefaultMetric(); m != nil {
+		fmt.Fprintf(b, "      TE default metric: %d\n", uint32Value(m.Metric))
+	}
+
+	if st := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_SID); st != nil {
+		var vals []uint32
+		for v := range st.AdjacencySid {
+			vals = append(vals, v)
+		}
+		sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+		for _, v := range vals {
+			a := st.AdjacencySid[v]
+			fmt.Fprintf(b, "      Adjacency SID: %d, weight: %d, flags: %v\n", v, uint8Value(a.Weight), a.Flags)
+		}
+	}
+	if st := s(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_ADJ_LAN_SID); st != nil {
+		var vals []uint32
+		for v := range st.LanAdjacencySid {
+			vals = append(vals, v)
+		}
+		sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+		for _, v := range vals {
+			a := st.LanAdjacencySid[v]
+			fmt.Fprintf(b, "      LAN adjacency SID: %d, neighbor: %s, weight: %d, flags: %v\n", v, stringValue(a.NeighborId), uint8Value(a.Weight), a.Flags)
+		}
+	}
+
+	var undef []int
+	for t := range inst.UndefinedSubtlv {
+		undef = append(undef, int(t))
+	}
+	sort.Ints(undef)
+	for _, t := range undef {
+		fmt.Fprintf(b, "      Undefined sub-TLV %d: length %d\n", t, len(inst.UndefinedSubtlv[uint8(t)].Value))
+	}
+	return nil
+}
+
+// renderExtendedIPReachText writes the prefixes of the extended IPv4
+// reachability TLV r to b, sorted by prefix.
+func renderExtendedIPReachText(b *strings.Builder, r *oc.Lsp_Tlv_ExtendedIpv4Reachability) {
+	if r == nil {
+		return
+	}
+
+	var pfxs []string
+	for p := range r.Prefix {
+		pfxs = append(pfxs, p)
+	}
+	sort.Strings(pfxs)
+
+	for _, pfx := range pfxs {
+		p := r.Prefix[pfx]
+		renderPrefixText(b, pfx, uint32Value(p.Metric), boolValue(p.UpDown))
+
+		var sids []textPrefixSID
+		if st := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID); st != nil {
+			for _, s := range st.PrefixSid {
+				sids = append(sids, textPrefixSID{value: uint32Value(s.Value), algorithm: uint8Value(s.Algorithm), flags: s.Flags})
+			}
+		}
+		renderPrefixSIDsText(b, sids)
+		if t := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetTag(); t != nil {
+			renderTagsText(b, t.Tag32)
+		}
+	}
+}
+
+// renderIPv6ReachText writes the prefixes of the IPv6 reachability TLV r to
+// b, sorted by prefix.
+func renderIPv6ReachText(b *strings.Builder, r *oc.Lsp_Tlv_Ipv6Reachability) {
+	if r == nil {
+		return
+	}
+
+	var pfxs []string
+	for p := range r.Prefix {
+		pfxs = append(pfxs, p)
+	}
+	sort.Strings(pfxs)
+
+	for _, pfx := range pfxs {
+		p := r.Prefix[pfx]
+		renderPrefixText(b, pfx, uint32Value(p.Metric), boolValue(p.UpDown))
+		if boolValue(p.XBit) {
+			fmt.Fprintf(b, "      External\n")
+		}
+
+		var sids []textPrefixSID
+		if st := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_PREFIX_SID); st != nil {
+			for _, s := range st.PrefixSid {
+				sids = append(sids, textPrefixSID{value: uint32Value(s.Value), algorithm: uint8Value(s.Algorithm), flags: s.Flags})
+			}
+		}
+		renderPrefixSIDsText(b, sids)
+		if t := p.GetSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IP_REACHABILITY_TAG).GetTag(); t != nil {
+			renderTagsText(b, t.Tag32)
+		}
+	}
+}
+
+// renderPrefixText writes the line describing the prefix pfx to b.
+func renderPrefixText(b *strings.Builder, pfx string, metric uint32, upDown bool) {
+	fmt.Fprintf(b, "    Prefix: %s, metric: %d", pfx, metric)
+	if upDown {
+		b.WriteString(", up/down")
+	}
+	b.WriteString("\n")
+}
+
+// renderPrefixSIDsText writes the prefix SIDs in sids to b, sorted by value.
+func renderPrefixSIDsText(b *strings.Builder, sids []textPrefixSID) {
+	sort.Slice(sids, func(i, j int) bool { return sids[i].value < sids[j].value })
+	for _, s := range sids {
+		fmt.Fprintf(b, "      Prefix SID: %d, algorithm: %d, flags: %v\n", s.value, s.algorithm, s.flags)
+	}
+}
+
+// renderTagsText writes the administrative tags in tags to b.
+func renderTagsText(b *strings.Builder, tags []uint32) {
+	for _, t := range tags {
+		fmt.Fprintf(b, "      Tag: %d\n", t)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+	"github.com/openconfig/lsdbparse/pkg/oc"
+	"github.com/openconfig/ygot/ygot"
+)
+
+func TestRenderText(t *testing.T) {
+	lsp3, ok, err := ISISBytesToLSP(exampleLSP3, 0)
+	if !ok {
+		t.Fatalf("ISISBytesToLSP(exampleLSP3): could not parse LSP: %v", err)
+	}
+
+	badBandwidth := &oc.Lsp{LspId: ygot.String("0000.0000.0001.00-00")}
+	inst := badBandwidth.GetOrCreateTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_EXTENDED_IS_REACHABILITY).GetOrCreateExtendedIsReachability().GetOrCreateNeighbor("0000.0000.0002.00").GetOrCreateInstance(0)
+	inst.GetOrCreateSubtlv(oc.OpenconfigIsisLsdbTypes_ISIS_SUBTLV_TYPE_IS_REACHABILITY_MAX_LINK_BANDWIDTH).GetOrCreateMaxLinkBandwidth().Bandwidth = []byte{0x4e, 0x95}
+
+	tests := []struct {
+		name             string
+		inLSP            *oc.Lsp
+		wantLines        []string
+		wantErrSubstring string
+	}{{
+		name:  "example LSP",
+		inLSP: lsp3,
+		wantLines: []string{
+			"LSP ID: 0000.4000.ce3a.00-00",
+			"  Sequence number: 0x00001809",
+			"  Checksum: 0xf12e",
+			"    Hostname: re0-bb07.sql88",
+			"    Neighbor: 0000.4000.ce3c.00, metric: 10",
+			"      Maximum link bandwidth: 2.5e+09 bytes/s",
+			"      Unreserved bandwidth, priority 5: 1.99985e+09 bytes/s",
+			"      Adjacency SID: 20, weight: 0, flags: [VALUE LOCAL]",
+			"      LAN adjacency SID: 22, neighbor: 0000.4000.ce39, weight: 0, flags: [VALUE LOCAL]",
+			"    Prefix: 192.168.201.32/27, metric: 30",
+			"    Prefix: 100.1.1.13/32, metric: 0",
+			"      Prefix SID: 200, algorithm: 0, flags: [NODE]",
+			"    Prefix: 2001:4860:c0a8:c920::/64, metric: 30",
+			"  Undefined TLV 14: length 2",
+		},
+	}, {
+		name:             "nil LSP",
+		wantErrSubstring: "cannot render LSP without LSP ID",
+	}, {
+		name:             "invalid bandwidth",
+		inLSP:            badBandwidth,
+		wantErrSubstring: "cannot render neighbor 0000.0000.0002.00, invalid maximum link bandwidth",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderText(tt.inLSP)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("RenderText(%v): did not get expected error, %s", tt.inLSP, diff)
+			}
+
+			lines := map[string]bool{}
+			for _, l := range strings.Split(got, "\n") {
+				lines[l] = true
+			}
+			for _, want := range tt.wantLines {
+				if !lines[want] {
+					t.Errorf("RenderText(%v): did not get expected line %q, got:\n%s", tt.inLSP, want, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// lspEntriesTLVType is the type of the LSP Entries TLV, which is
+	// carried in sequence number PDUs.
+	lspEntriesTLVType uint8 = 9
+	// lspEntryLen is the length of a single entry within the LSP Entries
+	// TLV.
+	lspEntryLen = 16
+	// lspIDLen is the length of an LSP ID with the default system ID
+	// length.
+	lspIDLen = defaultIDLength + 2
+	// csnpHeaderLen is the length of the CSNP header, consisting of the
+	// common header, the PDU length (2 bytes), source ID (7 bytes), and
+	// the start and end LSP IDs.
+	csnpHeaderLen = commonHeaderLen + 2 + defaultIDLength + 1 + 2*lspIDLen
+	// psnpHeaderLen is the length of the PSNP header, consisting of the
+	// common header, the PDU length (2 bytes) and source ID (7 bytes).
+	psnpHeaderLen = commonHeaderLen + 2 + defaultIDLength + 1
+)
+
+// LSPEntry is a summary of an LSP, as carried in the LSP Entries TLV (9) of a
+// sequence number PDU.
+type LSPEntry struct {
+	// LSPID is the LSP ID, in the format xxxx.yyyy.zzzz.aa-bb.
+	LSPID string
+	// SequenceNumber is the sequence number of the LSP.
+	SequenceNumber uint32
+	// Checksum is the checksum of the LSP.
+	Checksum uint16
+	// RemainingLifetime is the remaining lifetime of the LSP, in seconds.
+	RemainingLifetime uint16
+}
+
+// CSNP is the contents of a Complete Sequence Number PDU.
+type CSNP struct {
+	// PDUType is the PDU type of the CSNP, PDUTypeL1CSNP or PDUTypeL2CSNP.
+	PDUType uint8
+	// SourceID is the ID of the system that sent the CSNP, in the format
+	// xxxx.yyyy.zzzz.nn.
+	SourceID string
+	// StartLSPID and EndLSPID are the first and last LSP IDs of the range
+	// of LSP IDs that is described by the CSNP.
+	StartLSPID, EndLSPID string
+	// Entries are the LSP entries carried in the CSNP, in the order in
+	// which they appear in the PDU.
+	Entries []LSPEntry
+}
+
+// PSNP is the contents of a Partial Sequence Number PDU.
+type PSNP struct {
+	// PDUType is the PDU type of the PSNP, PDUTypeL1PSNP or PDUTypeL2PSNP.
+	PDUType uint8
+	// SourceID is the ID of the system that sent the PSNP, in the format
+	// xxxx.yyyy.zzzz.nn.
+	SourceID string
+	// Entries are the LSP entries carried in the PSNP, in the order in
+	// which they appear in the PDU.
+	Entries []LSPEntry
+}
+
+// lspIDString returns the LSP ID b, in the format xxxx.yyyy.zzzz.aa-bb.
+func lspIDString(b []byte) string {
+	return fastCanonicalHexString(b[:lspIDLen-1]) + "-" + fastCanonicalHexString(b[lspIDLen-1:lspIDLen])
+}
+
+// parseLSPEntriesTLV parses the value of an LSP Entries TLV (9), which consists
+// of a sequence of 16-byte entries, each containing the remaining lifetime,
+// LSP ID, sequence number and checksum of an LSP. Returns an error if the
+// length of the value is not a multiple of the entry length.
+func parseLSPEntriesTLV(b []byte) ([]LSPEntry, error) {
+	if len(b)%lspEntryLen != 0 {
+		return nil, fmt.Errorf("invalid length LSP Entries TLV, %d is not a multiple of %d", len(b), lspEntryLen)
+	}
+
+	var entries []LSPEntry
+	for ; len(b) > 0; b = b[lspEntryLen:] {
+		entries = append(entries, LSPEntry{
+			RemainingLifetime: binary.BigEndian.Uint16(b[0:2]),
+			LSPID:             lspIDString(b[2:10]),
+			SequenceNumber:    binary.BigEndian.Uint32(b[10:14]),
+			Checksum:          binary.BigEndian.Uint16(b[14:16]),
+		})
+	}
+	return entries, nil
+}
+
+// snpBody validates the common header of the sequence number PDU pdu, which
+// must be of one of the PDU types in types, and have a header of hdrLen bytes.
+// It returns the PDU type, and the TLVs of the PDU, which are bounded by the
+// PDU length field. Returns an error if the header is malformed.
+func snpBody(pdu []byte, hdrLen int, types ...uint8) (uint8, []byte, error) {
+	if len(pdu) < hdrLen {
+		return 0, nil, fmt.Errorf("invalid PDU, need at least %d bytes for header, got %d bytes", hdrLen, len(pdu))
+	}
+
+	if pdu[0] != isisIRPD {
+		return 0, nil, fmt.Errorf("invalid PDU, unknown intradomain routeing protocol discriminator %#x", pdu[0])
+	}
+
+	pt := pdu[4] & pduTypeMask
+	var ok bool
+	for _, t := range types {
+		if pt == t {
+			ok = true
+		}
+	}
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid PDU, unexpected PDU type %d", pt)
+	}
+
+	// The IDs are parsed assuming the default system ID length.
+	if idLen := pdu[3]; idLen != 0 && idLen != defaultIDLength {
+		return 0, nil, fmt.Errorf("invalid PDU, unsupported system ID length %d", idLen)
+	}
+
+	pduLen := int(binary.BigEndian.Uint16(pdu[8:10]))
+	switch {
+	case pduLen < hdrLen:
+		return 0, nil, fmt.Errorf("invalid PDU, declared PDU length of %d bytes is shorter than the header", pduLen)
+	case pduLen > len(pdu):
+		return 0, nil, fmt.Errorf("invalid PDU, declared PDU length of %d bytes exceeds %d bytes supplied", pduLen, len(pdu))
+	}
+	return pt, pdu[hdrLen:pduLen], nil
+}
+
+// snpEntries returns the LSP entries carried in the LSP Entries TLVs of tlvs,
+// the TLVs of a sequence number PDU. Other TLVs, such as authentication, are
+// ignored. Returns an error if the TLVs are malformed.
+func snpEntries(tlvs []byte) ([]LSPEntry, error) {
+	var entries []LSPEntry
+	err := walkTLVs(tlvs, func(tlvType uint8, value []byte) error {
+		if tlvType != lspEntriesTLVType {
+			return nil
+		}
+		e, err := parseLSPEntriesTLV(value)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseCSNP parses the Complete Sequence Number PDU pdu, starting at the
+// intradomain routeing protocol discriminator of the common header, and
+// returns the source ID, the range of LSP IDs described, and the LSP entries
+// that it carries. Returns an error if the PDU is not a level 1 or level 2
+// CSNP, or is malformed.
+func ParseCSNP(pdu []byte) (*CSNP, error) {
+	pt, tlvs, err := snpBody(pdu, csnpHeaderLen, PDUTypeL1CSNP, PDUTypeL2CSNP)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := snpEntries(tlvs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSNP: %v", err)
+	}
+
+	// The source ID follows the PDU length, and is followed by the start
+	// and end LSP IDs.
+	src := commonHeaderLen + 2
+	start := src + defaultIDLength + 1
+	end := start + lspIDLen
+	return &CSNP{
+		PDUType:    pt,
+		SourceID:   fastCanonicalHexString(pdu[src:start]),
+		StartLSPID: lspIDString(pdu[start:end]),
+		EndLSPID:   lspIDString(pdu[end : end+lspIDLen]),
+		Entries:    entries,
+	}, nil
+}
+
+// ParsePSNP parses the Partial Sequence Number PDU pdu, starting at the
+// intradomain routeing protocol discriminator of the common header, and
+// returns the source ID and the LSP entries that it carries. Returns an error
+// if the PDU is not a level 1 or level 2 PSNP, or is malformed.
+func ParsePSNP(pdu []byte) (*PSNP, error) {
+	pt, tlvs, err := snpBody(pdu, psnpHeaderLen, PDUTypeL1PSNP, PDUTypeL2PSNP)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := snpEntries(tlvs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PSNP: %v", err)
+	}
+
+	src := commonHeaderLen + 2
+	return &PSNP{
+		PDUType:  pt,
+		SourceID: fastCanonicalHexString(pdu[src:psnpHeaderLen]),
+		Entries:  entries,
+	}, nil
+}
@@ -0,0 +1,191 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+// csnpPDU returns a level 2 CSNP sent by 1920.0000.2001.00, describing the
+// full range of LSP IDs, that contains the TLVs supplied.
+func csnpPDU(tlvs ...byte) []byte {
+	pdu := []byte{
+		0x83, 33, 1, 0, PDUTypeL2CSNP, 1, 0, 0,
+		0, 0, // PDU length
+		0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, // source ID
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, // start LSP ID
+		0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // end LSP ID
+	}
+	pdu = append(pdu, tlvs...)
+	binary.BigEndian.PutUint16(pdu[8:10], uint16(len(pdu)))
+	return pdu
+}
+
+// lspEntries is the value of an LSP Entries TLV, containing entries for
+// 1920.0000.2001.00-00, 1920.0000.2001.00-01 and 1920.0000.2002.00-00.
+var lspEntries = []byte{
+	0x04, 0xaf, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2a, 0xab, 0xcd,
+	0x04, 0xb0, 0x19, 0x20, 0x00, 0x00, 0x20, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x12, 0x34,
+	0x00, 0x00, 0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x56, 0x78,
+}
+
+func TestParseCSNP(t *testing.T) {
+	entries := []LSPEntry{{
+		LSPID:             "1920.0000.2001.00-00",
+		SequenceNumber:    42,
+		Checksum:          0xabcd,
+		RemainingLifetime: 1199,
+	}, {
+		LSPID:             "1920.0000.2001.00-01",
+		SequenceNumber:    1,
+		Checksum:          0x1234,
+		RemainingLifetime: 1200,
+	}, {
+		LSPID:          "1920.0000.2002.00-00",
+		SequenceNumber: 256,
+		Checksum:       0x5678,
+	}}
+
+	l1 := csnpPDU()
+	l1[4] = PDUTypeL1CSNP
+
+	tests := []struct {
+		name             string
+		in               []byte
+		want             *CSNP
+		wantErrSubstring string
+	}{{
+		name: "CSNP with several LSP entries",
+		in:   csnpPDU(append([]byte{9, 48}, lspEntries...)...),
+		want: &CSNP{
+			PDUType:    PDUTypeL2CSNP,
+			SourceID:   "1920.0000.2001.00",
+			StartLSPID: "0000.0000.0000.00-00",
+			EndLSPID:   "ffff.ffff.ffff.ff-ff",
+			Entries:    entries,
+		},
+	}, {
+		name: "entries split across TLVs with other TLVs",
+		in: csnpPDU(append(append(append([]byte{9, 32}, lspEntries[:32]...),
+			10, 2, 0, 0, // authentication TLV
+			9, 16), lspEntries[32:]...)...),
+		want: &CSNP{
+			PDUType:    PDUTypeL2CSNP,
+			SourceID:   "1920.0000.2001.00",
+			StartLSPID: "0000.0000.0000.00-00",
+			EndLSPID:   "ffff.ffff.ffff.ff-ff",
+			Entries:    entries,
+		},
+	}, {
+		name: "level 1 CSNP with no entries",
+		in:   l1,
+		want: &CSNP{
+			PDUType:    PDUTypeL1CSNP,
+			SourceID:   "1920.0000.2001.00",
+			StartLSPID: "0000.0000.0000.00-00",
+			EndLSPID:   "ffff.ffff.ffff.ff-ff",
+		},
+	}, {
+		name:             "malformed entry length",
+		in:               csnpPDU(append([]byte{9, 15}, lspEntries[:15]...)...),
+		wantErrSubstring: "15 is not a multiple of 16",
+	}, {
+		name:             "LSP PDU type",
+		in:               WrapAsStandardPDU(exampleLSP1, PDUTypeL2LSP, 0),
+		wantErrSubstring: "unexpected PDU type 20",
+	}, {
+		name:             "truncated header",
+		in:               csnpPDU()[:20],
+		wantErrSubstring: "need at least 33 bytes",
+	}, {
+		name:             "truncated TLVs",
+		in:               csnpPDU(append([]byte{9, 48}, lspEntries...)...)[:60],
+		wantErrSubstring: "exceeds 60 bytes supplied",
+	}}
+
+	for _, tt := range tests {
+		got, err := ParseCSNP(tt.in)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ParseCSNP(...): did not get expected error, %s", tt.name, diff)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: ParseCSNP(...): did not get expected CSNP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
+
+func TestParsePSNP(t *testing.T) {
+	// psnpPDU returns a level 2 PSNP sent by 1920.0000.2002.00 that contains
+	// the TLVs supplied.
+	psnpPDU := func(tlvs ...byte) []byte {
+		pdu := []byte{
+			0x83, 17, 1, 0, PDUTypeL2PSNP, 1, 0, 0,
+			0, 0, // PDU length
+			0x19, 0x20, 0x00, 0x00, 0x20, 0x02, 0x00, // source ID
+		}
+		pdu = append(pdu, tlvs...)
+		binary.BigEndian.PutUint16(pdu[8:10], uint16(len(pdu)))
+		return pdu
+	}
+
+	tests := []struct {
+		name             string
+		in               []byte
+		want             *PSNP
+		wantErrSubstring string
+	}{{
+		name: "PSNP acknowledging two LSPs",
+		in:   psnpPDU(append([]byte{9, 32}, lspEntries[:32]...)...),
+		want: &PSNP{
+			PDUType:  PDUTypeL2PSNP,
+			SourceID: "1920.0000.2002.00",
+			Entries: []LSPEntry{{
+				LSPID:             "1920.0000.2001.00-00",
+				SequenceNumber:    42,
+				Checksum:          0xabcd,
+				RemainingLifetime: 1199,
+			}, {
+				LSPID:             "1920.0000.2001.00-01",
+				SequenceNumber:    1,
+				Checksum:          0x1234,
+				RemainingLifetime: 1200,
+			}},
+		},
+	}, {
+		name:             "truncated entry",
+		in:               psnpPDU(append([]byte{9, 24}, lspEntries[:24]...)...),
+		wantErrSubstring: "24 is not a multiple of 16",
+	}, {
+		name:             "CSNP PDU type",
+		in:               csnpPDU(),
+		wantErrSubstring: "unexpected PDU type 25",
+	}}
+
+	for _, tt := range tests {
+		got, err := ParsePSNP(tt.in)
+		if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+			t.Errorf("%s: ParsePSNP(...): did not get expected error, %s", tt.name, diff)
+			continue
+		}
+		if diff := pretty.Compare(got, tt.want); diff != "" {
+			t.Errorf("%s: ParsePSNP(...): did not get expected PSNP, diff(-got,+want):\n%s", tt.name, diff)
+		}
+	}
+}
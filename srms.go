@@ -0,0 +1,57 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"fmt"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// srmsPreferenceSubTLVType is the type of the Segment Routing Mapping Server
+// (SRMS) preference sub-TLV of the Router Capability TLV (242), defined in
+// RFC8667.
+const srmsPreferenceSubTLVType uint8 = 24
+
+// parseSRMSPreferenceSubTLV parses the SRMS preference sub-TLV of the Router
+// Capability TLV, which consists of a single octet containing the preference
+// of the advertising router when acting as a mapping server. Returns the
+// preference, or an error if the sub-TLV is not 1 octet long.
+func parseSRMSPreferenceSubTLV(r *rawTLV) (uint8, error) {
+	if len(r.Value) != 1 {
+		return 0, fmt.Errorf("invalid length SRMS preference sub-TLV: %d != 1", len(r.Value))
+	}
+	return r.Value[0], nil
+}
+
+// SRMSPreference returns the SRMS preference that is advertised in the Router
+// Capability TLV supplied, which is used to select between multiple mapping
+// servers, decoded from its undefined sub-TLVs. Returns false if no SRMS
+// preference sub-TLV is present, or an error if it cannot be decoded.
+func SRMSPreference(c *oc.Lsp_Tlv_Capability) (uint8, bool, error) {
+	u := c.GetUndefinedSubtlv(srmsPreferenceSubTLVType)
+	if u == nil {
+		return 0, false, nil
+	}
+	p, err := parseSRMSPreferenceSubTLV(&rawTLV{
+		Type:   srmsPreferenceSubTLVType,
+		Length: uint8(len(u.Value)),
+		Value:  u.Value,
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return p, true, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"testing"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+func TestSRMSPreference(t *testing.T) {
+	tests := []struct {
+		name     string
+		inValue  []byte
+		want     uint8
+		wantOK   bool
+		wantErr  bool
+		noSubTLV bool
+	}{{
+		name:    "preference of 200",
+		inValue: []byte{200},
+		want:    200,
+		wantOK:  true,
+	}, {
+		name:    "empty",
+		inValue: []byte{},
+		wantErr: true,
+	}, {
+		name:    "too long",
+		inValue: []byte{200, 0},
+		wantErr: true,
+	}, {
+		name:     "no SRMS preference",
+		noSubTLV: true,
+	}}
+
+	for _, tt := range tests {
+		// Router capability TLV with router ID 192.0.2.1, no flags and an
+		// SRMS preference sub-TLV.
+		in := []byte{192, 0, 2, 1, 0}
+		if !tt.noSubTLV {
+			in = append(append(in, srmsPreferenceSubTLVType, uint8(len(tt.inValue))), tt.inValue...)
+		}
+		i := newISISLSP()
+		err := i.processCapabilityTLV(&rawTLV{Type: 242, Length: uint8(len(in)), Value: in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: processCapabilityTLV(%v): got unexpected error status, got: %v, wantErr: %v", tt.name, in, err, tt.wantErr)
+			continue
+		}
+
+		got, ok, err := SRMSPreference(i.LSP.GetTlv(oc.OpenconfigIsisLsdbTypes_ISIS_TLV_TYPE_ROUTER_CAPABILITY).GetCapability(0))
+		if err != nil {
+			t.Errorf("%s: SRMSPreference(...): got unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: SRMSPreference(...): did not get expected preference, got: %d, %v, want: %d, %v", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
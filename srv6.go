@@ -0,0 +1,118 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import "fmt"
+
+// SRv6EndpointBehavior is the code point of an SRv6 endpoint behavior, as
+// carried in the SRv6 End SID and End.X SID sub-TLVs defined in RFC9352. The
+// code points are allocated in the IANA SRv6 Endpoint Behaviors registry,
+// defined in RFC8986.
+type SRv6EndpointBehavior uint16
+
+const (
+	// SRv6EndpointEnd is the End behavior, the SRv6 instantiation of a
+	// prefix SID.
+	SRv6EndpointEnd SRv6EndpointBehavior = 1
+	// SRv6EndpointEndX is the End.X behavior, the SRv6 instantiation of an
+	// adjacency SID.
+	SRv6EndpointEndX SRv6EndpointBehavior = 5
+	// SRv6EndpointEndT is the End.T behavior, which performs a lookup in
+	// a specific IPv6 table.
+	SRv6EndpointEndT SRv6EndpointBehavior = 9
+	// SRv6EndpointEndDX6 is the End.DX6 behavior, which decapsulates and
+	// cross-connects to an IPv6 adjacency.
+	SRv6EndpointEndDX6 SRv6EndpointBehavior = 16
+	// SRv6EndpointEndDX4 is the End.DX4 behavior, which decapsulates and
+	// cross-connects to an IPv4 adjacency.
+	SRv6EndpointEndDX4 SRv6EndpointBehavior = 17
+	// SRv6EndpointEndDT6 is the End.DT6 behavior, which decapsulates and
+	// performs a lookup in a specific IPv6 table.
+	SRv6EndpointEndDT6 SRv6EndpointBehavior = 18
+	// SRv6EndpointEndDT4 is the End.DT4 behavior, which decapsulates and
+	// performs a lookup in a specific IPv4 table.
+	SRv6EndpointEndDT4 SRv6EndpointBehavior = 19
+	// SRv6EndpointEndDT46 is the End.DT46 behavior, which decapsulates and
+	// performs a lookup in a specific IP table.
+	SRv6EndpointEndDT46 SRv6EndpointBehavior = 20
+	// SRv6EndpointOpaque is the code point used for a behavior that is
+	// opaque, i.e., not signalled.
+	SRv6EndpointOpaque SRv6EndpointBehavior = 0xFFFF
+)
+
+// srv6EndpointBehaviorNames maps SRv6 endpoint behaviors from the IANA SRv6
+// Endpoint Behaviors registry to their names. It covers the code points
+// allocated by RFC8986 (0-27) and the End, End.X and End.T flavors with USD
+// (28-39), along with the opaque behavior; code points allocated by later
+// documents are not included, and are hence rendered as UNKNOWN(n). Behaviors
+// with flavors use the name in the registry, e.g., "End with PSP".
+var srv6EndpointBehaviorNames = map[SRv6EndpointBehavior]string{
+	0:                   "RESERVED",
+	SRv6EndpointEnd:     "End",
+	2:                   "End with PSP",
+	3:                   "End with USP",
+	4:                   "End with PSP & USP",
+	SRv6EndpointEndX:    "End.X",
+	6:                   "End.X with PSP",
+	7:                   "End.X with USP",
+	8:                   "End.X with PSP & USP",
+	SRv6EndpointEndT:    "End.T",
+	10:                  "End.T with PSP",
+	11:                  "End.T with USP",
+	12:                  "End.T with PSP & USP",
+	14:                  "End.B6.Encaps",
+	15:                  "End.BM",
+	SRv6EndpointEndDX6:  "End.DX6",
+	SRv6EndpointEndDX4:  "End.DX4",
+	SRv6EndpointEndDT6:  "End.DT6",
+	SRv6EndpointEndDT4:  "End.DT4",
+	SRv6EndpointEndDT46: "End.DT46",
+	21:                  "End.DX2",
+	22:                  "End.DX2V",
+	23:                  "End.DT2U",
+	24:                  "End.DT2M",
+	25:                  "RESERVED",
+	27:                  "End.B6.Encaps.Red",
+	28:                  "End with USD",
+	29:                  "End with PSP & USD",
+	30:                  "End with USP & USD",
+	31:                  "End with PSP, USP & USD",
+	32:                  "End.X with USD",
+	33:                  "End.X with PSP & USD",
+	34:                  "End.X with USP & USD",
+	35:                  "End.X with PSP, USP & USD",
+	36:                  "End.T with USD",
+	37:                  "End.T with PSP & USD",
+	38:                  "End.T with USP & USD",
+	39:                  "End.T with PSP, USP & USD",
+	SRv6EndpointOpaque:  "Opaque",
+}
+
+// String returns the name of the endpoint behavior from the IANA SRv6
+// Endpoint Behaviors registry, or UNKNOWN(n) for code points that are not
+// known.
+func (b SRv6EndpointBehavior) String() string {
+	if n, ok := srv6EndpointBehaviorNames[b]; ok {
+		return n
+	}
+	return fmt.Sprintf("UNKNOWN(%d)", uint16(b))
+}
+
+// IsKnown returns true if the endpoint behavior is allocated in the IANA SRv6
+// Endpoint Behaviors registry, and is not reserved.
+func (b SRv6EndpointBehavior) IsKnown() bool {
+	n, ok := srv6EndpointBehaviorNames[b]
+	return ok && n != "RESERVED"
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import "testing"
+
+func TestSRv6EndpointBehavior(t *testing.T) {
+	tests := []struct {
+		in         SRv6EndpointBehavior
+		wantString string
+		wantKnown  bool
+	}{
+		{SRv6EndpointEnd, "End", true},
+		{SRv6EndpointEndX, "End.X", true},
+		{SRv6EndpointEndDT4, "End.DT4", true},
+		{SRv6EndpointEndDT6, "End.DT6", true},
+		{SRv6EndpointBehavior(4), "End with PSP & USP", true},
+		{SRv6EndpointOpaque, "Opaque", true},
+		{SRv6EndpointBehavior(0), "RESERVED", false},
+		{SRv6EndpointBehavior(1000), "UNKNOWN(1000)", false},
+		// Code points from the IANA SRv6 Endpoint Behaviors registry around
+		// the unassigned code point 26.
+		{SRv6EndpointBehavior(24), "End.DT2M", true},
+		{SRv6EndpointBehavior(25), "RESERVED", false},
+		{SRv6EndpointBehavior(26), "UNKNOWN(26)", false},
+		{SRv6EndpointBehavior(27), "End.B6.Encaps.Red", true},
+		{SRv6EndpointBehavior(28), "End with USD", true},
+		{SRv6EndpointBehavior(31), "End with PSP, USP & USD", true},
+		{SRv6EndpointBehavior(32), "End.X with USD", true},
+		{SRv6EndpointBehavior(35), "End.X with PSP, USP & USD", true},
+		{SRv6EndpointBehavior(36), "End.T with USD", true},
+		{SRv6EndpointBehavior(39), "End.T with PSP, USP & USD", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.wantString {
+			t.Errorf("SRv6EndpointBehavior(%d).String(): got: %s, want: %s", uint16(tt.in), got, tt.wantString)
+		}
+		if got := tt.in.IsKnown(); got != tt.wantKnown {
+			t.Errorf("SRv6EndpointBehavior(%d).IsKnown(): got: %v, want: %v", uint16(tt.in), got, tt.wantKnown)
+		}
+	}
+}
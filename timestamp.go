@@ -0,0 +1,46 @@
+// Copyright 2018 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsdbparse
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/openconfig/lsdbparse/pkg/oc"
+)
+
+// LSPTimestamp returns the timestamp that is carried in the vendor timestamp
+// TLV of type tlvType within the LSP, as registered using WithTimestampTLV.
+// The supported encoding of the TLV is:
+//
+//	4 octets - seconds since the Unix epoch
+//	4 octets - nanoseconds
+//
+// Returns false if the LSP does not contain the TLV, or its contents are not
+// in the supported encoding, in which case the raw contents remain available
+// as an undefined TLV.
+func LSPTimestamp(lsp *oc.Lsp, tlvType uint8) (time.Time, bool) {
+	u := lsp.GetUndefinedTlv(tlvType)
+	if u == nil || len(u.Value) != 8 {
+		return time.Time{}, false
+	}
+
+	secs := binary.BigEndian.Uint32(u.Value[0:4])
+	nsecs := binary.BigEndian.Uint32(u.Value[4:8])
+	if nsecs >= uint32(time.Second) {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(secs), int64(nsecs)).UTC(), true
+}